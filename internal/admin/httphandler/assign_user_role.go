@@ -0,0 +1,89 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationAssignUserRole = "assign_user_role"
+	FileAssignUserRole      = OperationAssignUserRole + ".go"
+)
+
+// handleAdminAssignUserRole assigns a role to a user, which is a no-op when the user already holds it.
+// The user gets the permissions of the role along with its next access token.
+func (s *AdminServer) handleAdminAssignUserRole() http.HandlerFunc {
+	const self = "handleAdminAssignUserRole"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		userID, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAssignUserRole))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		roleID, err := uuid.Parse(r.PathValue("roleID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAssignUserRole))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Role ID must be a valid UUID.")
+			return
+		}
+
+		if _, err := s.userService.FindAnyByID(ctx, user.FindByIDRequest{ID: userID}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAssignUserRole))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		err = s.roleService.AssignUserRole(ctx, role.AssignUserRoleRequest{UserID: userID, RoleID: roleID})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAssignUserRole))
+			span.RecordError(err)
+			switch err {
+			case role.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any role with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.adminActionsCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("action", ActionAssignRole)))
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAssignUserRole))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileAssignUserRole, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationAssignUserRole)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,91 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationCreatePermission = "create_permission"
+	FileCreatePermission      = OperationCreatePermission + ".go"
+)
+
+// handleAdminCreatePermission registers a permission, so that roles can grant it
+// and the services trusting the access tokens can check it.
+func (s *AdminServer) handleAdminCreatePermission() http.HandlerFunc {
+	const self = "handleAdminCreatePermission"
+
+	type request struct {
+		Name        string `json:"name" validate:"required,max=128"`
+		Description string `json:"description" validate:"max=512"`
+	}
+
+	contract := map[string]responder.Field{
+		"Name": {
+			Name:       "name",
+			Validation: "Field is required and must have at most 128 characters.",
+		},
+		"Description": {
+			Name:       "description",
+			Validation: "Field must have at most 512 characters.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreatePermission))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreatePermission))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		createResponse, err := s.roleService.CreatePermission(ctx, role.CreatePermissionRequest{
+			Name:        req.Name,
+			Description: req.Description,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreatePermission))
+			span.RecordError(err)
+			switch err {
+			case role.ErrInvalidPermission:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, `Permission name must be lowercase segments separated by colons, such as "users:delete".`)
+			case role.ErrPermissionAlreadyExists:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "There is already a permission with provided name.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusCreated, &responder.DataField{Data: newPermissionResponse(createResponse.Permission)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreatePermission))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileCreatePermission, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationCreatePermission)
+	return otelhandler.ServeHTTP
+}
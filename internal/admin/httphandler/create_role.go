@@ -0,0 +1,98 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationCreateRole = "create_role"
+	FileCreateRole      = OperationCreateRole + ".go"
+)
+
+// handleAdminCreateRole creates a role granting existing permissions.
+func (s *AdminServer) handleAdminCreateRole() http.HandlerFunc {
+	const self = "handleAdminCreateRole"
+
+	type request struct {
+		Name        string   `json:"name" validate:"required,max=64"`
+		Description string   `json:"description" validate:"max=512"`
+		Permissions []string `json:"permissions" validate:"max=100"`
+	}
+
+	contract := map[string]responder.Field{
+		"Name": {
+			Name:       "name",
+			Validation: "Field is required and must have at most 64 characters.",
+		},
+		"Description": {
+			Name:       "description",
+			Validation: "Field must have at most 512 characters.",
+		},
+		"Permissions": {
+			Name:       "permissions",
+			Validation: "Field must have at most 100 permissions.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreateRole))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreateRole))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		createResponse, err := s.roleService.CreateRole(ctx, role.CreateRoleRequest{
+			Name:        req.Name,
+			Description: req.Description,
+			Permissions: req.Permissions,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreateRole))
+			span.RecordError(err)
+			switch err {
+			case role.ErrInvalidName:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Role name must be lowercase letters, digits, dashes and underscores.")
+			case role.ErrInvalidPermission, role.ErrPermissionNotFound:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Every permission must be an existing one.")
+			case role.ErrNameAlreadyInUse:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "There is already a role with provided name.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusCreated, &responder.DataField{Data: newRoleResponse(createResponse.Role)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreateRole))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileCreateRole, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationCreateRole)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,56 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDeletePermission = "delete_permission"
+	FileDeletePermission      = OperationDeletePermission + ".go"
+)
+
+// handleAdminDeletePermission deletes a permission along with its grants. Built-in permissions cannot be deleted.
+func (s *AdminServer) handleAdminDeletePermission() http.HandlerFunc {
+	const self = "handleAdminDeletePermission"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		err := s.roleService.DeletePermission(ctx, role.DeletePermissionRequest{Name: r.PathValue("permission")})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeletePermission))
+			span.RecordError(err)
+			switch err {
+			case role.ErrPermissionNotFound:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any permission with provided name.")
+			case role.ErrBuiltinPermission:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Built-in permissions cannot be deleted.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeletePermission))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeletePermission, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDeletePermission)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,63 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDeleteRole = "delete_role"
+	FileDeleteRole      = OperationDeleteRole + ".go"
+)
+
+// handleAdminDeleteRole deletes a role, which is unassigned from every user holding it.
+func (s *AdminServer) handleAdminDeleteRole() http.HandlerFunc {
+	const self = "handleAdminDeleteRole"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("roleID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteRole))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Role ID must be a valid UUID.")
+			return
+		}
+
+		err = s.roleService.DeleteRoleByID(ctx, role.DeleteRoleByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteRole))
+			span.RecordError(err)
+			switch err {
+			case role.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any role with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteRole))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteRole, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDeleteRole)
+	return otelhandler.ServeHTTP
+}
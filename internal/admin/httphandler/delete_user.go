@@ -0,0 +1,92 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDeleteUser = "delete_user"
+	FileDeleteUser      = OperationDeleteUser + ".go"
+)
+
+// handleAdminDeleteUser deletes a user after the grace period, or right away with the "permanent" query parameter.
+// Unlike users deleting themselves, admins are not asked for the password of the user.
+func (s *AdminServer) handleAdminDeleteUser() http.HandlerFunc {
+	const self = "handleAdminDeleteUser"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteUser))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		permanent := false
+		if value := r.URL.Query().Get("permanent"); value != "" {
+			permanent, err = strconv.ParseBool(value)
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteUser))
+				span.RecordError(err)
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Permanent must be either true or false.")
+				return
+			}
+		}
+
+		err = s.userService.DeleteByID(ctx, user.DeleteByIDRequest{ID: id, Permanent: permanent})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteUser))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		// Refresh tokens would otherwise outlive the deletion, and be usable again once restored
+		if !permanent {
+			if err := s.authService.SignOutEverywhere(ctx, auth.SignOutEverywhereRequest{UserID: id}); err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteUser))
+				span.RecordError(err)
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteUser, self, "failed to revoke refresh tokens", err))
+				responder.RespondInternalError(w, r)
+				return
+			}
+		}
+
+		s.adminActionsCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("action", ActionDelete)))
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteUser))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteUser, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDeleteUser)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,77 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDisableUser = "disable_user"
+	FileDisableUser      = OperationDisableUser + ".go"
+)
+
+// handleAdminDisableUser prevents a user from signing in and signs it out everywhere, until it is enabled again.
+func (s *AdminServer) handleAdminDisableUser() http.HandlerFunc {
+	const self = "handleAdminDisableUser"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableUser))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		err = s.userService.DisableByID(ctx, user.DisableByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableUser))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		// Refresh tokens would otherwise outlive the user being disabled
+		if err := s.authService.SignOutEverywhere(ctx, auth.SignOutEverywhereRequest{UserID: id}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableUser))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDisableUser, self, "failed to revoke refresh tokens", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		s.adminActionsCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("action", ActionDisable)))
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableUser))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDisableUser, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDisableUser)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,67 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationEnableUser = "enable_user"
+	FileEnableUser      = OperationEnableUser + ".go"
+)
+
+// handleAdminEnableUser allows a disabled user to sign in again.
+func (s *AdminServer) handleAdminEnableUser() http.HandlerFunc {
+	const self = "handleAdminEnableUser"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationEnableUser))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		err = s.userService.EnableByID(ctx, user.EnableByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationEnableUser))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.adminActionsCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("action", ActionEnable)))
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationEnableUser))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileEnableUser, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationEnableUser)
+	return otelhandler.ServeHTTP
+}
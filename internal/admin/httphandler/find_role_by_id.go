@@ -0,0 +1,62 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationFindRoleByID = "find_role_by_id"
+	FileFindRoleByID      = OperationFindRoleByID + ".go"
+)
+
+func (s *AdminServer) handleAdminFindRoleByID() http.HandlerFunc {
+	const self = "handleAdminFindRoleByID"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("roleID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindRoleByID))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Role ID must be a valid UUID.")
+			return
+		}
+
+		findResponse, err := s.roleService.FindRoleByID(ctx, role.FindRoleByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindRoleByID))
+			span.RecordError(err)
+			switch err {
+			case role.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any role with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: newRoleResponse(findResponse.Role)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindRoleByID))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFindRoleByID, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationFindRoleByID)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,63 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationFindUserByID = "find_user_by_id"
+	FileFindUserByID      = OperationFindUserByID + ".go"
+)
+
+// handleAdminFindUserByID finds any user, including disabled ones and ones pending deletion.
+func (s *AdminServer) handleAdminFindUserByID() http.HandlerFunc {
+	const self = "handleAdminFindUserByID"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindUserByID))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		findResponse, err := s.userService.FindAnyByID(ctx, user.FindByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindUserByID))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: s.newUserResponse(findResponse.User)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindUserByID))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFindUserByID, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationFindUserByID)
+	return otelhandler.ServeHTTP
+}
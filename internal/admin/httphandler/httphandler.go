@@ -0,0 +1,123 @@
+package httphandler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"auth/internal/auth"
+	authrepo "auth/internal/auth/repo/gorm"
+	"auth/internal/mail"
+	"auth/internal/role"
+	rolerepo "auth/internal/role/repo/gorm"
+	"auth/internal/user"
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/jkitajima/composer"
+
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const Path = "auth/internal/admin/httphandler"
+
+// Admin actions, recorded as the "action" attribute of the admin_actions counter
+const (
+	ActionDisable       = "disable"
+	ActionEnable        = "enable"
+	ActionVerifyEmail   = "verify_email"
+	ActionResetPassword = "reset_password"
+	ActionDelete        = "delete"
+	ActionAssignRole    = "assign_role"
+	ActionUnassignRole  = "unassign_role"
+)
+
+// AdminServer serves the API that admins manage users with, in place of querying the database directly.
+// Every route requires an access token carrying the admin claim or the permission that the route is gated by,
+// and the roles that grant those permissions are managed through the same API.
+type AdminServer struct {
+	entity              string
+	mux                 *chi.Mux
+	prefix              string
+	userService         *user.Service
+	authService         *auth.Service
+	roleService         *role.Service
+	keyring             *auth.Keyring
+	db                  user.Repoer
+	authRepo            auth.Repoer
+	inputValidator      *validator.Validate
+	logger              *slog.Logger
+	tracer              trace.Tracer
+	meter               metric.Meter
+	adminActionsCounter metric.Int64Counter
+}
+
+func (s *AdminServer) Prefix() string {
+	return s.prefix
+}
+
+func (s *AdminServer) Mux() http.Handler {
+	return s.mux
+}
+
+func (s *AdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func NewServer(
+	keyring *auth.Keyring,
+	resetconfig *auth.ResetConfig,
+	deletionconfig *user.DeletionConfig,
+	mailer mail.Mailer,
+	templates *mail.Templates,
+	db *gorm.DB,
+	validtr *validator.Validate,
+	logger *slog.Logger,
+	tracer trace.Tracer,
+	meter metric.Meter,
+) (composer.Server, error) {
+	s := &AdminServer{
+		entity:         "users",
+		prefix:         "/admin",
+		mux:            chi.NewRouter(),
+		keyring:        keyring,
+		db:             userrepo.NewRepo(db, logger),
+		authRepo:       authrepo.NewRepo(db, logger),
+		inputValidator: validtr,
+		logger:         logger,
+		tracer:         tracer,
+		meter:          meter,
+	}
+	s.userService = &user.Service{Repo: s.db, Deletion: deletionconfig}
+	s.roleService = &role.Service{Repo: rolerepo.NewRepo(db, logger)}
+	s.authService = &auth.Service{
+		Reset:     resetconfig,
+		Keyring:   keyring,
+		Mailer:    mailer,
+		Templates: templates,
+		UserRepo:  s.db,
+		Repo:      s.authRepo,
+	}
+
+	if err := s.instrument(); err != nil {
+		return s, err
+	}
+
+	s.addRoutes()
+	return s, nil
+}
+
+func (s *AdminServer) instrument() error {
+	adminActionsCounter, err := s.meter.Int64Counter("admin_actions",
+		metric.WithDescription("How many actions admins has taken on users, by action."),
+	)
+	if err != nil {
+		return err
+	}
+	s.adminActionsCounter = adminActionsCounter
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationListPermissions = "list_permissions"
+	FileListPermissions      = OperationListPermissions + ".go"
+)
+
+// handleAdminListPermissions lists every permission that roles can grant, built-in ones included.
+func (s *AdminServer) handleAdminListPermissions() http.HandlerFunc {
+	const self = "handleAdminListPermissions"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		listResponse, err := s.roleService.ListPermissions(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListPermissions))
+			span.RecordError(err)
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		data := make([]permissionResponse, 0, len(listResponse.Permissions))
+		for _, p := range listResponse.Permissions {
+			data = append(data, newPermissionResponse(p))
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: data}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListPermissions))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListPermissions, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationListPermissions)
+	return otelhandler.ServeHTTP
+}
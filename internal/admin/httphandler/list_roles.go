@@ -0,0 +1,48 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationListRoles = "list_roles"
+	FileListRoles      = OperationListRoles + ".go"
+)
+
+// handleAdminListRoles lists every role along with the permissions it grants.
+func (s *AdminServer) handleAdminListRoles() http.HandlerFunc {
+	const self = "handleAdminListRoles"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		listResponse, err := s.roleService.ListRoles(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListRoles))
+			span.RecordError(err)
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: newRoleResponses(listResponse.Roles)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListRoles))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListRoles, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationListRoles)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,71 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationListUserRoles = "list_user_roles"
+	FileListUserRoles      = OperationListUserRoles + ".go"
+)
+
+// handleAdminListUserRoles lists the roles assigned to a user, including disabled ones and ones pending deletion.
+func (s *AdminServer) handleAdminListUserRoles() http.HandlerFunc {
+	const self = "handleAdminListUserRoles"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUserRoles))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		if _, err := s.userService.FindAnyByID(ctx, user.FindByIDRequest{ID: id}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUserRoles))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		findResponse, err := s.roleService.FindUserRoles(ctx, role.FindUserRolesRequest{UserID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUserRoles))
+			span.RecordError(err)
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: newRoleResponses(findResponse.Roles)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUserRoles))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListUserRoles, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationListUserRoles)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,174 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationListUsers = "list_users"
+	FileListUsers      = OperationListUsers + ".go"
+)
+
+// Users per page when the request does not ask for a number of them
+const defaultListLimit = 50
+
+// handleAdminListUsers lists users a page at a time, filtered and sorted by the query string.
+// Every page but the last comes with a cursor for the next one, which keeps the filters and sort of the request.
+func (s *AdminServer) handleAdminListUsers() http.HandlerFunc {
+	const self = "handleAdminListUsers"
+
+	type request struct {
+		Email         string `validate:"max=320"`
+		Verified      string `validate:"omitempty,boolean"`
+		Disabled      string `validate:"omitempty,boolean"`
+		CreatedAfter  string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		CreatedBefore string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		Deleted       string `validate:"omitempty,oneof=exclude include only"`
+		Sort          string `validate:"omitempty,oneof=created_at -created_at email -email"`
+		Limit         int    `validate:"min=1,max=100"`
+		Cursor        string
+	}
+
+	type response struct {
+		Data []userResponse `json:"data"`
+		// Null on the last page
+		NextCursor *string `json:"next_cursor"`
+	}
+
+	contract := map[string]responder.Field{
+		"Email": {
+			Name:       "email",
+			Validation: "Parameter must have at most 320 characters.",
+		},
+		"Verified": {
+			Name:       "verified",
+			Validation: "Parameter must be either true or false.",
+		},
+		"Disabled": {
+			Name:       "disabled",
+			Validation: "Parameter must be either true or false.",
+		},
+		"CreatedAfter": {
+			Name:       "created_after",
+			Validation: "Parameter must be an RFC 3339 timestamp.",
+		},
+		"CreatedBefore": {
+			Name:       "created_before",
+			Validation: "Parameter must be an RFC 3339 timestamp.",
+		},
+		"Deleted": {
+			Name:       "deleted",
+			Validation: "Parameter must be one of exclude, include or only.",
+		},
+		"Sort": {
+			Name:       "sort",
+			Validation: "Parameter must be one of created_at or email, prefixed with - for a descending sort.",
+		},
+		"Limit": {
+			Name:       "limit",
+			Validation: "Parameter must be a number between 1 and 100.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		query := r.URL.Query()
+		req := request{
+			Email:         query.Get("email"),
+			Verified:      query.Get("verified"),
+			Disabled:      query.Get("disabled"),
+			CreatedAfter:  query.Get("created_after"),
+			CreatedBefore: query.Get("created_before"),
+			Deleted:       query.Get("deleted"),
+			Sort:          query.Get("sort"),
+			Limit:         defaultListLimit,
+			Cursor:        query.Get("cursor"),
+		}
+		if limit := query.Get("limit"); limit != "" {
+			// Unparsable limits are left to the validation
+			req.Limit, _ = strconv.Atoi(limit)
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUsers))
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		listQuery := user.ListQuery{
+			Email:   req.Email,
+			Deleted: user.DeletedFilter(req.Deleted),
+			Sort:    user.SortCreatedAt,
+			Limit:   req.Limit,
+		}
+		if sort, ok := strings.CutPrefix(req.Sort, "-"); ok {
+			listQuery.Sort, listQuery.Descending = user.ListSort(sort), true
+		} else if req.Sort != "" {
+			listQuery.Sort = user.ListSort(req.Sort)
+		}
+		// Both were validated already
+		if req.Verified != "" {
+			verified, _ := strconv.ParseBool(req.Verified)
+			listQuery.Verified = &verified
+		}
+		if req.Disabled != "" {
+			disabled, _ := strconv.ParseBool(req.Disabled)
+			listQuery.Disabled = &disabled
+		}
+		if req.CreatedAfter != "" {
+			after, _ := time.Parse(time.RFC3339, req.CreatedAfter)
+			listQuery.CreatedAfter = &after
+		}
+		if req.CreatedBefore != "" {
+			before, _ := time.Parse(time.RFC3339, req.CreatedBefore)
+			listQuery.CreatedBefore = &before
+		}
+
+		listResponse, err := s.userService.List(ctx, user.ListRequest{Query: listQuery, Cursor: req.Cursor})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUsers))
+			span.RecordError(err)
+			switch err {
+			case user.ErrInvalidCursor:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Cursor is invalid or was issued for another sort.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		resp := response{Data: make([]userResponse, 0, len(listResponse.Users))}
+		for _, u := range listResponse.Users {
+			resp.Data = append(resp.Data, s.newUserResponse(u))
+		}
+		if listResponse.NextCursor != "" {
+			resp.NextCursor = &listResponse.NextCursor
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListUsers))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListUsers, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationListUsers)
+	return otelhandler.ServeHTTP
+}
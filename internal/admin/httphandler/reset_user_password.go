@@ -0,0 +1,69 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationResetUserPassword = "reset_user_password"
+	FileResetUserPassword      = OperationResetUserPassword + ".go"
+)
+
+// handleAdminResetUserPassword forces a user to choose a new password through the link emailed to it,
+// such as after its credentials have leaked. The user is signed out everywhere meanwhile.
+func (s *AdminServer) handleAdminResetUserPassword() http.HandlerFunc {
+	const self = "handleAdminResetUserPassword"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetUserPassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		err = s.authService.ForcePasswordReset(ctx, auth.ForcePasswordResetRequest{UserID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetUserPassword))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.adminActionsCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("action", ActionResetPassword)))
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetUserPassword))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileResetUserPassword, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationResetUserPassword)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,59 @@
+package httphandler
+
+import (
+	"time"
+
+	"auth/internal/role"
+
+	"github.com/google/uuid"
+)
+
+type roleResponse struct {
+	Entity      string    `json:"entity"`
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newRoleResponse(r *role.Role) roleResponse {
+	permissions := r.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return roleResponse{
+		Entity:      "roles",
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func newRoleResponses(roles []*role.Role) []roleResponse {
+	data := make([]roleResponse, 0, len(roles))
+	for _, r := range roles {
+		data = append(data, newRoleResponse(r))
+	}
+	return data
+}
+
+type permissionResponse struct {
+	Entity      string    `json:"entity"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newPermissionResponse(p *role.Permission) permissionResponse {
+	return permissionResponse{
+		Entity:      "permissions",
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedAt:   p.CreatedAt,
+	}
+}
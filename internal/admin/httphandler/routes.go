@@ -0,0 +1,48 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"auth/internal/auth/guard"
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *AdminServer) addRoutes() {
+	// Admin routes
+	s.mux.Group(func(r chi.Router) {
+		r.Use(guard.Verifier(s.keyring, s.authRepo, s.db))
+		r.Use(guard.Authenticator)
+
+		// Every route is gated by a permission, which the admin claim grants as well
+		usersRead := r.With(guard.RequirePermission(role.PermissionUsersRead))
+		usersWrite := r.With(guard.RequirePermission(role.PermissionUsersWrite))
+		usersDelete := r.With(guard.RequirePermission(role.PermissionUsersDelete))
+		rolesRead := r.With(guard.RequirePermission(role.PermissionRolesRead))
+		rolesWrite := r.With(guard.RequirePermission(role.PermissionRolesWrite))
+
+		otel.Route(usersRead, http.MethodGet, "/users", s.handleAdminListUsers())
+		otel.Route(usersRead, http.MethodGet, "/users/{userID}", s.handleAdminFindUserByID())
+		otel.Route(usersDelete, http.MethodDelete, "/users/{userID}", s.handleAdminDeleteUser())
+		otel.Route(usersWrite, http.MethodPost, "/users/{userID}/disable", s.handleAdminDisableUser())
+		otel.Route(usersWrite, http.MethodPost, "/users/{userID}/enable", s.handleAdminEnableUser())
+		otel.Route(usersWrite, http.MethodPost, "/users/{userID}/verify-email", s.handleAdminVerifyUserEmail())
+		otel.Route(usersWrite, http.MethodPost, "/users/{userID}/reset-password", s.handleAdminResetUserPassword())
+
+		otel.Route(rolesRead, http.MethodGet, "/users/{userID}/roles", s.handleAdminListUserRoles())
+		otel.Route(rolesWrite, http.MethodPut, "/users/{userID}/roles/{roleID}", s.handleAdminAssignUserRole())
+		otel.Route(rolesWrite, http.MethodDelete, "/users/{userID}/roles/{roleID}", s.handleAdminUnassignUserRole())
+
+		otel.Route(rolesRead, http.MethodGet, "/permissions", s.handleAdminListPermissions())
+		otel.Route(rolesWrite, http.MethodPost, "/permissions", s.handleAdminCreatePermission())
+		otel.Route(rolesWrite, http.MethodDelete, "/permissions/{permission}", s.handleAdminDeletePermission())
+
+		otel.Route(rolesRead, http.MethodGet, "/roles", s.handleAdminListRoles())
+		otel.Route(rolesWrite, http.MethodPost, "/roles", s.handleAdminCreateRole())
+		otel.Route(rolesRead, http.MethodGet, "/roles/{roleID}", s.handleAdminFindRoleByID())
+		otel.Route(rolesWrite, http.MethodPut, "/roles/{roleID}/permissions", s.handleAdminSetRolePermissions())
+		otel.Route(rolesWrite, http.MethodDelete, "/roles/{roleID}", s.handleAdminDeleteRole())
+	})
+}
@@ -0,0 +1,95 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationSetRolePermissions = "set_role_permissions"
+	FileSetRolePermissions      = OperationSetRolePermissions + ".go"
+)
+
+// handleAdminSetRolePermissions replaces the permissions granted by a role.
+// Users holding the role get the new permissions along with their next access token.
+func (s *AdminServer) handleAdminSetRolePermissions() http.HandlerFunc {
+	const self = "handleAdminSetRolePermissions"
+
+	type request struct {
+		Permissions []string `json:"permissions" validate:"required,max=100"`
+	}
+
+	contract := map[string]responder.Field{
+		"Permissions": {
+			Name:       "permissions",
+			Validation: "Field is required and must have at most 100 permissions.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("roleID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSetRolePermissions))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Role ID must be a valid UUID.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSetRolePermissions))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSetRolePermissions))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		setResponse, err := s.roleService.SetRolePermissions(ctx, role.SetRolePermissionsRequest{
+			ID:          id,
+			Permissions: req.Permissions,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSetRolePermissions))
+			span.RecordError(err)
+			switch err {
+			case role.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any role with provided ID.")
+			case role.ErrInvalidPermission, role.ErrPermissionNotFound:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Every permission must be an existing one.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: newRoleResponse(setResponse.Role)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSetRolePermissions))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileSetRolePermissions, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationSetRolePermissions)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,71 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationUnassignUserRole = "unassign_user_role"
+	FileUnassignUserRole      = OperationUnassignUserRole + ".go"
+)
+
+// handleAdminUnassignUserRole takes a role away from a user, which is a no-op when the user does not hold it.
+// Access tokens already issued keep the permissions of the role until they expire.
+func (s *AdminServer) handleAdminUnassignUserRole() http.HandlerFunc {
+	const self = "handleAdminUnassignUserRole"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		userID, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnassignUserRole))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		roleID, err := uuid.Parse(r.PathValue("roleID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnassignUserRole))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Role ID must be a valid UUID.")
+			return
+		}
+
+		err = s.roleService.UnassignUserRole(ctx, role.UnassignUserRoleRequest{UserID: userID, RoleID: roleID})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnassignUserRole))
+			span.RecordError(err)
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		s.adminActionsCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("action", ActionUnassignRole)))
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnassignUserRole))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileUnassignUserRole, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationUnassignUserRole)
+	return otelhandler.ServeHTTP
+}
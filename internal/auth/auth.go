@@ -1,25 +1,514 @@
 package auth
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"auth/internal/client"
+	"auth/internal/mail"
+	"auth/internal/role"
 	"auth/internal/user"
+	"auth/pkg/keys"
+	"auth/pkg/password"
+
+	"github.com/google/uuid"
 )
 
+const Path = "auth/internal/auth"
+
 var (
-	ErrInternal           = errors.New("the auth service encountered an unexpected condition that prevented it from fulfilling the request")
-	ErrInvalidCredentials = errors.New("credentials was not valid")
+	ErrInternal               = errors.New("the auth service encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrInvalidCredentials     = errors.New("credentials was not valid")
+	ErrInvalidRefreshToken    = errors.New("refresh token is invalid, expired or revoked")
+	ErrRefreshTokenReused     = errors.New("refresh token was already used and its family has been revoked")
+	ErrRefreshTokenNotFound   = errors.New("could not find any refresh token with provided value")
+	ErrRefreshTokenNotActive  = errors.New("refresh token was already rotated or revoked")
+	ErrUnsupportedAlgorithm   = errors.New("configured jwt signing algorithm is not supported")
+	ErrInvalidToken           = errors.New("token is malformed, expired or its signature is invalid")
+	ErrInvalidClient          = errors.New("client authentication failed")
+	ErrUnauthorizedClient     = errors.New("client is not allowed to use the grant type")
+	ErrInvalidScope           = errors.New("requested scope is invalid or exceeds the scopes that may be granted")
+	ErrTokenNotIssuedToClient = errors.New("token was issued to another client")
+
+	ErrEmailNotVerified            = errors.New("email address has not been verified")
+	ErrUserDisabled                = errors.New("user has been disabled by an admin")
+	ErrInvalidVerificationCode     = errors.New("verification code is invalid")
+	ErrTooManyVerificationAttempts = errors.New("too many failed verification attempts, a new code must be requested")
+	ErrVerificationNotSent         = errors.New("verification email could not be sent")
+
+	ErrInvalidResetToken = errors.New("password reset token is invalid, expired or was already used")
+
+	ErrEmailUnchanged      = errors.New("new email address is the current one")
+	ErrEmailChangeNotFound = errors.New("could not find any pending email change, it may have expired, been confirmed or canceled")
+
+	ErrLoginLocked = errors.New("too many failed login attempts, logins are temporarily locked")
+
+	ErrMFARequired         = errors.New("a second authentication factor is required")
+	ErrInvalidMFAToken     = errors.New("mfa token is invalid, expired or was already used")
+	ErrInvalidMFACode      = errors.New("one-time password or recovery code is invalid")
+	ErrTooManyMFAAttempts  = errors.New("too many failed one-time passwords, the login must be started over")
+	ErrTooManyTOTPAttempts = errors.New("too many failed one-time passwords, try again later")
+	ErrTOTPNotFound        = errors.New("could not find any totp factor for the user")
+	ErrTOTPNotEnabled      = errors.New("totp has not been enabled")
+	ErrTOTPAlreadyEnabled  = errors.New("totp is already enabled")
+
+	ErrInvalidWebAuthnCeremony    = errors.New("webauthn ceremony is invalid, expired or was already used")
+	ErrInvalidWebAuthnResponse    = errors.New("webauthn response of the authenticator is invalid")
+	ErrWebAuthnCredentialNotFound = errors.New("could not find any webauthn credential with provided id")
+	ErrWebAuthnCredentialExists   = errors.New("webauthn credential is already registered")
+	ErrWebAuthnCredentialCloned   = errors.New("webauthn credential signature counter went backwards, the authenticator may be cloned")
+
+	ErrInvalidRedirectURI       = errors.New("redirect uri is missing or not registered for the client")
+	ErrUnsupportedResponseType  = errors.New("response type is not supported")
+	ErrInvalidCodeChallenge     = errors.New("a S256 pkce code challenge is required")
+	ErrInvalidAuthorizationCode = errors.New("authorization code is invalid, expired or was issued to another client")
+	ErrAuthorizationCodeReused  = errors.New("authorization code was already used and the tokens issued from it have been revoked")
+	ErrInvalidCodeVerifier      = errors.New("pkce code verifier does not match the code challenge")
+	ErrInvalidSession           = errors.New("session is invalid or expired")
+
+	ErrInsufficientScope = errors.New("token was not granted the scope required by the request")
+	ErrReservedClaim     = errors.New("claims enricher cannot set a claim already set by the service")
+
+	ErrInvalidDeviceCode    = errors.New("device code is invalid, was issued to another client or was already used")
+	ErrInvalidUserCode      = errors.New("user code is invalid, expired or was already decided")
+	ErrAuthorizationPending = errors.New("the user has not yet approved the device")
+	ErrSlowDown             = errors.New("device polls the token endpoint faster than its interval")
+	ErrAccessDenied         = errors.New("the user denied the device")
+	ErrDeviceCodeExpired    = errors.New("device code has expired")
 )
 
+// LoginLockedError is returned while logins are locked for an account or a client IP.
+// It unwraps to ErrLoginLocked.
+type LoginLockedError struct {
+	Until time.Time
+}
+
+func (e *LoginLockedError) Error() string {
+	return ErrLoginLocked.Error()
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+// MFARequiredError is returned by the password grant when the user has a second factor enabled.
+// MFAToken identifies the half-completed login and is exchanged, along with a one-time password,
+// for the actual tokens. It unwraps to ErrMFARequired.
+type MFARequiredError struct {
+	MFAToken  string
+	ExpiresIn int
+}
+
+func (e *MFARequiredError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+func (e *MFARequiredError) Unwrap() error {
+	return ErrMFARequired
+}
+
 type JWTConfig struct {
 	Algorithm  string
 	Key        string
+	KeyFile    string
+	KeyDir     string
 	Issuer     string
 	Audience   []string
 	Expiration int
+	// Scopes that users may be granted by the password grant
+	Scopes []string
+	Keys   []keys.Key
+	// Key rotation, in seconds
+	RotationInterval int
+	RotationDelay    int
+	RotationCheck    int
+}
+
+type RefreshConfig struct {
+	Expiration int
+}
+
+type RevocationConfig struct {
+	Purge int
+}
+
+type VerificationConfig struct {
+	// Seconds that a verification code is valid for
+	TTL int
+	// Failed attempts allowed for a single code
+	Attempts int
+	// Seconds between two verification emails
+	Cooldown int
+	// Whether password grant logins are rejected until the email is verified
+	Required bool
+	// Link sent to the current address of an email change for canceling it, the token is appended to it
+	CancelURL string
+}
+
+type ResetConfig struct {
+	// Seconds that a password reset token is valid for
+	TTL int
+	// Link sent by email, the token is appended to it
+	URL string
+}
+
+type LockoutConfig struct {
+	// Failed logins before an account or a client IP is locked (0 disables the check)
+	AccountThreshold int
+	IPThreshold      int
+	// Seconds of the first lockout, doubled after every further failure
+	Backoff int
+	// Upper bound of a lockout, in seconds
+	MaxDuration int
+	// Seconds without failures after which the count starts over
+	Window int
+}
+
+type MFAConfig struct {
+	// Issuer shown by authenticator apps
+	Issuer string
+	// Time steps of clock drift tolerated on each side
+	Skew int
+	// Seconds that an mfa token is valid for
+	TTL int
+	// Failed one-time passwords allowed for a single mfa token
+	Attempts int
+	// Number of recovery codes generated at once
+	RecoveryCodes int
+}
+
+type WebAuthnConfig struct {
+	// Relying party ID, the domain that credentials are scoped to
+	RPID string
+	// Relying party name shown by authenticators
+	RPName string
+	// Fully qualified origins allowed to run the ceremonies
+	Origins []string
+	// Seconds that a ceremony is valid for
+	TTL int
+}
+
+type AuthorizeConfig struct {
+	// Seconds that an authorization code is valid for
+	CodeTTL int
+	// Seconds that a login page session is valid for
+	SessionTTL int
+	// Whether cookies are only sent over HTTPS
+	Secure bool
+}
+
+type DeviceConfig struct {
+	// Seconds that a device code is valid for
+	TTL int
+	// Seconds that devices must wait between two polls of the token endpoint
+	Interval int
+}
+
+type AdminConfig struct {
+	// Email addresses of the users granted the admin claim, once verified
+	Users []string
+}
+
+// Config gathers the configuration of the service, shared by every server built on it.
+type Config struct {
+	JWT            *JWTConfig
+	Refresh        *RefreshConfig
+	Verification   *VerificationConfig
+	Reset          *ResetConfig
+	Lockout        *LockoutConfig
+	MFA            *MFAConfig
+	WebAuthn       *WebAuthnConfig
+	Authorize      *AuthorizeConfig
+	Device         *DeviceConfig
+	Deletion       *user.DeletionConfig
+	Admin          *AdminConfig
+	PasswordPolicy *password.Policy
+	// Optional, adds custom claims to access tokens
+	Claims ClaimsEnricher
+}
+
+// RefreshToken is an opaque, server-side stored credential used to obtain new access tokens.
+// Tokens issued from the same original grant share a FamilyID, so that reuse of an already
+// rotated token can revoke every descendant of that grant.
+type RefreshToken struct {
+	ID       uuid.UUID
+	FamilyID uuid.UUID
+	UserID   uuid.UUID
+	Hash     string
+	// Authentication methods of the original grant, carried over to every rotation
+	AMR []string
+	// Client of tokens issued to a client, empty for first-party logins
+	ClientID string
+	// Scopes of the original grant, carried over to every rotation
+	Scope     []string
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// RevokedToken is a denylist entry for an access token revoked before its expiration.
+// The entry is only meaningful until ExpiresAt, when the token would be rejected anyway.
+type RevokedToken struct {
+	JTI       string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// PasswordResetToken is a single-use credential sent by email that allows setting a new password.
+// Like refresh tokens, only its hash is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Hash      string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// EmailChange is a pending change of the email address of a user. The new address is confirmed
+// with a code sent to it, while the current one is notified with a link that cancels the change.
+// Only the hashes of the code and of the link token are stored.
+type EmailChange struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Email      string
+	CodeHash   string
+	CancelHash string
+	Attempts   int
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+}
+
+// LoginThrottle counts the recent failed logins of an account or a client IP.
+// Keys are prefixed with the kind of subject, e.g. "email:user@example.com" or "ip:192.0.2.1".
+type LoginThrottle struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+	CreatedAt     time.Time
+}
+
+// TOTP is the time-based one-time password factor of a user (RFC 6238).
+// It is pending until ConfirmedAt is set by a first valid code.
+// LastUsedStep is the time step of the last accepted code, which cannot be used twice.
+type TOTP struct {
+	UserID       uuid.UUID
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// RecoveryCode is a single-use code that replaces a one-time password when the authenticator is lost.
+// Only its hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Hash      string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// WebAuthnCredential is a passkey registered by a user.
+// SignCount is the last signature counter reported by the authenticator, which must only grow.
+type WebAuthnCredential struct {
+	ID              []byte
+	UserID          uuid.UUID
+	Name            string
+	PublicKey       []byte
+	AttestationType string
+	AAGUID          []byte
+	SignCount       uint32
+	Transports      []string
+	BackupEligible  bool
+	BackupState     bool
+	LastUsedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+// WebAuthnCeremony is a pending registration or login, holding the challenge sent to the authenticator.
+// Only the hash of its token is stored. UserID is nil for logins, where the passkey identifies the user.
+type WebAuthnCeremony struct {
+	ID        uuid.UUID
+	UserID    *uuid.UUID
+	Hash      string
+	Kind      string
+	Session   []byte
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAChallenge is a login whose password has been checked, waiting for a second factor.
+// Only the hash of its token is stored.
+type MFAChallenge struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	Hash     string
+	Attempts int
+	// Scopes requested by the password grant, granted once the second factor is checked
+	Scope     []string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Session is a login made on the login page of the authorization endpoint, kept in a cookie
+// so that further authorization requests do not ask for credentials again.
+// Only the hash of its token is stored. A password change ends the session,
+// since TokenVersion no longer matches the one of the user.
+type Session struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Hash         string
+	TokenVersion int
+	AMR          []string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// AuthorizationCode is a single-use code issued by the authorization endpoint,
+// bound to the client, the redirect URI and the PKCE challenge of the request.
+// Only its hash is stored. Nonce and AuthTime are carried over to the ID token.
+type AuthorizationCode struct {
+	ID            uuid.UUID
+	ClientID      string
+	UserID        uuid.UUID
+	Hash          string
+	RedirectURI   string
+	Scope         []string
+	CodeChallenge string
+	Nonce         string
+	AMR           []string
+	AuthTime      time.Time
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// Status of a device code, decided by the user on the verification page
+const (
+	DeviceCodePending  = "pending"
+	DeviceCodeApproved = "approved"
+	DeviceCodeDenied   = "denied"
+)
+
+// DeviceCode is a pending device authorization (RFC 8628). The device polls the token endpoint
+// with the device code while the user approves the user code on the verification page.
+// Only the hashes of both codes are stored. NextPollAt is pushed back by every poll,
+// and by PollInterval more seconds when the device polls too fast.
+type DeviceCode struct {
+	ID           uuid.UUID
+	ClientID     string
+	Hash         string
+	UserCodeHash string
+	Scope        []string
+	Status       string
+	// Set once the user decided
+	UserID       *uuid.UUID
+	AMR          []string
+	AuthTime     *time.Time
+	PollInterval int
+	NextPollAt   time.Time
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
 }
 
 type Service struct {
-	JWTConfig *JWTConfig
-	UserRepo  user.Repoer
+	JWTConfig      *JWTConfig
+	RefreshConfig  *RefreshConfig
+	Verification   *VerificationConfig
+	Reset          *ResetConfig
+	Lockout        *LockoutConfig
+	MFA            *MFAConfig
+	WebAuthn       *WebAuthnConfig
+	Authorize      *AuthorizeConfig
+	Device         *DeviceConfig
+	Deletion       *user.DeletionConfig
+	Admin          *AdminConfig
+	PasswordPolicy *password.Policy
+	Keyring        *Keyring
+	Mailer         mail.Mailer
+	Templates      *mail.Templates
+	// Optional, adds custom claims to access tokens
+	Claims     ClaimsEnricher
+	UserRepo   user.Repoer
+	ClientRepo client.Repoer
+	RoleRepo   role.Repoer
+	Repo       Repoer
+}
+
+type Repoer interface {
+	InsertRefreshToken(context.Context, *RefreshToken) error
+	FindRefreshTokenByHash(context.Context, string) (*RefreshToken, error)
+	RotateRefreshToken(context.Context, uuid.UUID) error
+	RevokeRefreshTokenFamily(context.Context, uuid.UUID) error
+	InsertRevokedToken(context.Context, *RevokedToken) error
+	IsTokenRevoked(context.Context, string) (bool, error)
+	DeleteExpiredRevokedTokens(context.Context, time.Time) (int64, error)
+	RevokeUserRefreshTokens(context.Context, uuid.UUID) error
+	InsertPasswordResetToken(context.Context, *PasswordResetToken) error
+	FindPasswordResetTokenByHash(context.Context, string) (*PasswordResetToken, error)
+	UsePasswordResetToken(context.Context, string, time.Time) (*PasswordResetToken, error)
+	DeletePasswordResetTokens(context.Context, uuid.UUID) error
+	InsertEmailChange(context.Context, *EmailChange) error
+	FindEmailChange(ctx context.Context, userID uuid.UUID, now time.Time) (*EmailChange, error)
+	CountEmailChangeAttempt(context.Context, uuid.UUID) (int, error)
+	UseEmailChange(ctx context.Context, id uuid.UUID, now time.Time) error
+	CancelEmailChange(ctx context.Context, cancelHash string, now time.Time) error
+	DeleteEmailChanges(context.Context, uuid.UUID) error
+	FindLoginThrottle(context.Context, string) (*LoginThrottle, error)
+	RecordLoginFailure(context.Context, string, time.Time, time.Time) (*LoginThrottle, error)
+	LockLoginThrottle(context.Context, string, time.Time) error
+	DeleteLoginThrottles(context.Context, ...string) (int64, error)
+	DeleteStaleLoginThrottles(context.Context, time.Time) (int64, error)
+	SaveTOTP(context.Context, *TOTP) error
+	FindTOTP(context.Context, uuid.UUID) (*TOTP, error)
+	// ConfirmTOTP and UseTOTPStep only accept a step newer than the last used one,
+	// so that a code cannot be replayed, even by concurrent requests.
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64, now time.Time) error
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error
+	// CountTOTPAttempt increments the attempts of the confirmed factor of the user and returns the new count.
+	// The count starts over when the last attempt is older than windowStart.
+	CountTOTPAttempt(ctx context.Context, userID uuid.UUID, now, windowStart time.Time) (int, error)
+	ResetTOTPAttempts(context.Context, uuid.UUID) error
+	// DeleteTOTP deletes the factor along with the recovery codes of the user.
+	DeleteTOTP(context.Context, uuid.UUID) error
+	ReplaceRecoveryCodes(context.Context, uuid.UUID, []*RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, now time.Time) error
+	InsertMFAChallenge(context.Context, *MFAChallenge) error
+	FindMFAChallengeByHash(context.Context, string) (*MFAChallenge, error)
+	CountMFAChallengeAttempt(context.Context, uuid.UUID) (int, error)
+	UseMFAChallenge(ctx context.Context, id uuid.UUID, now time.Time) error
+	InsertWebAuthnCredential(context.Context, *WebAuthnCredential) error
+	ListWebAuthnCredentials(context.Context, uuid.UUID) ([]*WebAuthnCredential, error)
+	// UpdateWebAuthnSignCount only accepts a counter greater than the stored one, unless both are zero,
+	// which some authenticators always report.
+	UpdateWebAuthnSignCount(ctx context.Context, id []byte, signCount uint32, backupState bool, now time.Time) error
+	DeleteWebAuthnCredential(ctx context.Context, userID uuid.UUID, id []byte) error
+	InsertWebAuthnCeremony(context.Context, *WebAuthnCeremony) error
+	// UseWebAuthnCeremony marks a pending ceremony of the kind as used and returns it.
+	UseWebAuthnCeremony(ctx context.Context, hash, kind string, now time.Time) (*WebAuthnCeremony, error)
+	InsertSession(context.Context, *Session) error
+	FindSessionByHash(context.Context, string) (*Session, error)
+	InsertAuthorizationCode(context.Context, *AuthorizationCode) error
+	// UseAuthorizationCode marks an unexpired code issued to the client for the redirect URI as used and returns it.
+	// A code that the client already used is returned along with ErrAuthorizationCodeReused,
+	// so that the tokens issued from it can be revoked. Codes of other clients are left untouched.
+	UseAuthorizationCode(ctx context.Context, hash, clientID, redirectURI string, now time.Time) (*AuthorizationCode, error)
+	InsertDeviceCode(context.Context, *DeviceCode) error
+	FindDeviceCodeByHash(context.Context, string) (*DeviceCode, error)
+	FindDeviceCodeByUserCode(ctx context.Context, userCodeHash string) (*DeviceCode, error)
+	// DecideDeviceCode records the decision of the user on a pending, unexpired device code and returns it.
+	DecideDeviceCode(ctx context.Context, userCodeHash string, decided *DeviceCode, now time.Time) (*DeviceCode, error)
+	// PollDeviceCode moves NextPollAt from previous to next. It reports false when a concurrent poll moved it first.
+	PollDeviceCode(ctx context.Context, id uuid.UUID, previous, next time.Time) (bool, error)
+	SlowDownDeviceCode(ctx context.Context, id uuid.UUID, interval int, next time.Time) error
+	// UseDeviceCode marks an approved device code as used, so that it is only exchanged once.
+	UseDeviceCode(ctx context.Context, id uuid.UUID, now time.Time) error
 }
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"auth/internal/client"
+	"auth/pkg/secret"
+)
+
+type AuthenticateClientRequest struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// AuthenticateClient checks the credentials of a resource server against the client registry.
+// Only confidential clients registered with the introspect scope may introspect tokens.
+func (s *Service) AuthenticateClient(ctx context.Context, req AuthenticateClientRequest) error {
+	c, err := s.authenticateRegisteredClient(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !c.CanIntrospect() {
+		return ErrUnauthorizedClient
+	}
+	return nil
+}
+
+// authenticateRegisteredClient checks the credentials of a client of the registry and returns it.
+// Public clients have no secret and are only identified by their ID.
+// An unknown client and a wrong secret are both reported as ErrInvalidClient.
+func (s *Service) authenticateRegisteredClient(ctx context.Context, req AuthenticateClientRequest) (*client.Client, error) {
+	if req.ClientID == "" {
+		return nil, ErrInvalidClient
+	}
+
+	c, err := s.ClientRepo.FindByID(ctx, req.ClientID)
+	if err != nil {
+		if err == client.ErrNotFoundByID {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if c.Public {
+		if req.ClientSecret != "" {
+			return nil, ErrInvalidClient
+		}
+		return c, nil
+	}
+
+	if req.ClientSecret == "" || subtle.ConstantTimeCompare([]byte(c.SecretHash), []byte(secret.Hash(req.ClientSecret))) != 1 {
+		return nil, ErrInvalidClient
+	}
+	return c, nil
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+type BeginWebAuthnLoginResponse struct {
+	CeremonyToken string
+	ExpiresIn     int
+	// Options passed to navigator.credentials.get()
+	Options *protocol.CredentialAssertion
+}
+
+// BeginWebAuthnLogin starts a passwordless login. No user is given, since
+// the passkey picked on the authenticator identifies the user it belongs to.
+func (s *Service) BeginWebAuthnLogin(ctx context.Context) (BeginWebAuthnLoginResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return BeginWebAuthnLoginResponse{}, err
+	}
+
+	options, session, err := rp.BeginDiscoverableLogin()
+	if err != nil {
+		return BeginWebAuthnLoginResponse{}, err
+	}
+
+	token, err := s.startWebAuthnCeremony(ctx, nil, CeremonyLogin, session)
+	if err != nil {
+		return BeginWebAuthnLoginResponse{}, err
+	}
+
+	return BeginWebAuthnLoginResponse{
+		CeremonyToken: token,
+		ExpiresIn:     s.WebAuthn.TTL,
+		Options:       options,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+type BeginWebAuthnRegistrationRequest struct {
+	UserID uuid.UUID
+}
+
+type BeginWebAuthnRegistrationResponse struct {
+	CeremonyToken string
+	ExpiresIn     int
+	// Options passed to navigator.credentials.create()
+	Options *protocol.CredentialCreation
+}
+
+// BeginWebAuthnRegistration starts the registration of a new passkey for the user.
+// Passkeys already registered are excluded, so that an authenticator is not registered twice.
+func (s *Service) BeginWebAuthnRegistration(ctx context.Context, req BeginWebAuthnRegistrationRequest) (BeginWebAuthnRegistrationResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return BeginWebAuthnRegistrationResponse{}, err
+	}
+
+	u, err := s.findWebAuthnUser(ctx, req.UserID)
+	if err != nil {
+		return BeginWebAuthnRegistrationResponse{}, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(u.credentials))
+	for _, c := range u.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	options, session, err := rp.BeginRegistration(u, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return BeginWebAuthnRegistrationResponse{}, err
+	}
+
+	token, err := s.startWebAuthnCeremony(ctx, &u.user.ID, CeremonyRegistration, session)
+	if err != nil {
+		return BeginWebAuthnRegistrationResponse{}, err
+	}
+
+	return BeginWebAuthnRegistrationResponse{
+		CeremonyToken: token,
+		ExpiresIn:     s.WebAuthn.TTL,
+		Options:       options,
+	}, nil
+}
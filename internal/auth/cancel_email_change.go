@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/pkg/secret"
+)
+
+type CancelEmailChangeRequest struct {
+	Token string
+}
+
+// CancelEmailChange cancels a pending email change with the token of the link sent to the current address.
+// It does not require the user to be logged in, since the change may have been started by someone else.
+func (s *Service) CancelEmailChange(ctx context.Context, req CancelEmailChangeRequest) error {
+	if req.Token == "" {
+		return ErrEmailChangeNotFound
+	}
+
+	return s.Repo.CancelEmailChange(ctx, secret.Hash(req.Token), time.Now())
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+
+	"auth/internal/client"
+)
+
+type ClientCredentialsRequest struct {
+	ClientID     string
+	ClientSecret string
+	// Scope requested by the client, every scope of the client when empty
+	Scope []string
+}
+
+type ClientCredentialsResponse struct {
+	AccessToken []byte
+	TokenType   string
+	ExpiresIn   int
+	Scope       []string
+}
+
+// ClientCredentials issues an access token to a client acting on its own behalf (RFC 6749 section 4.4).
+// No refresh token is issued, since the client can authenticate again at any time.
+func (s *Service) ClientCredentials(ctx context.Context, req ClientCredentialsRequest) (ClientCredentialsResponse, error) {
+	c, err := s.authenticateRegisteredClient(ctx, AuthenticateClientRequest{
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+	})
+	if err != nil {
+		return ClientCredentialsResponse{}, err
+	}
+
+	if !c.AllowsGrant(client.GrantClientCredentials) {
+		return ClientCredentialsResponse{}, ErrUnauthorizedClient
+	}
+
+	scope, err := c.GrantScopes(req.Scope)
+	if err != nil {
+		return ClientCredentialsResponse{}, ErrInvalidScope
+	}
+
+	generateTokenResponse, err := s.GenerateToken(ctx, GenerateTokenRequest{
+		Client: c,
+		Scope:  scope,
+	})
+	if err != nil {
+		return ClientCredentialsResponse{}, err
+	}
+
+	return ClientCredentialsResponse{
+		AccessToken: generateTokenResponse.AccessToken,
+		TokenType:   generateTokenResponse.TokenType,
+		ExpiresIn:   generateTokenResponse.ExpiresIn,
+		Scope:       scope,
+	}, nil
+}
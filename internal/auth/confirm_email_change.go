@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type ConfirmEmailChangeRequest struct {
+	UserID uuid.UUID
+	Code   string
+}
+
+type ConfirmEmailChangeResponse struct {
+	User *user.User
+}
+
+// ConfirmEmailChange changes the email address of the user to the pending one, once the code sent to it is confirmed.
+// The new address is verified by the code, so the user stays verified.
+func (s *Service) ConfirmEmailChange(ctx context.Context, req ConfirmEmailChangeRequest) (ConfirmEmailChangeResponse, error) {
+	now := time.Now()
+	change, err := s.Repo.FindEmailChange(ctx, req.UserID, now)
+	if err != nil {
+		return ConfirmEmailChangeResponse{}, err
+	}
+
+	// The attempt is counted before comparing the code, so that concurrent guesses cannot exceed the limit
+	attempts, err := s.Repo.CountEmailChangeAttempt(ctx, change.ID)
+	if err != nil {
+		return ConfirmEmailChangeResponse{}, err
+	}
+	if attempts > s.Verification.Attempts {
+		return ConfirmEmailChangeResponse{}, ErrTooManyVerificationAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret.Hash(req.Code)), []byte(change.CodeHash)) != 1 {
+		return ConfirmEmailChangeResponse{}, ErrInvalidVerificationCode
+	}
+
+	// Using the change in the same statement that checks it makes sure that
+	// it is neither confirmed twice nor confirmed after being canceled
+	if err := s.Repo.UseEmailChange(ctx, change.ID, now); err != nil {
+		return ConfirmEmailChangeResponse{}, err
+	}
+
+	if err := s.UserRepo.UpdateEmail(ctx, req.UserID, change.Email); err != nil {
+		return ConfirmEmailChangeResponse{}, err
+	}
+
+	u, err := s.UserRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return ConfirmEmailChangeResponse{}, err
+	}
+	return ConfirmEmailChangeResponse{u}, nil
+}
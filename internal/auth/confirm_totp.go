@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ConfirmTOTPRequest struct {
+	UserID uuid.UUID
+	Code   string
+}
+
+type ConfirmTOTPResponse struct {
+	RecoveryCodes []string
+}
+
+// ConfirmTOTP enables a pending TOTP factor once the user proves that their authenticator
+// produces valid codes, and returns the recovery codes of the user. They are only shown once.
+func (s *Service) ConfirmTOTP(ctx context.Context, req ConfirmTOTPRequest) (ConfirmTOTPResponse, error) {
+	factor, err := s.Repo.FindTOTP(ctx, req.UserID)
+	if err != nil {
+		if err == ErrTOTPNotFound {
+			return ConfirmTOTPResponse{}, ErrTOTPNotEnabled
+		}
+		return ConfirmTOTPResponse{}, err
+	}
+	if factor.ConfirmedAt != nil {
+		return ConfirmTOTPResponse{}, ErrTOTPAlreadyEnabled
+	}
+
+	now := time.Now()
+	step, ok := matchTOTP(factor.Secret, req.Code, now, s.MFA.Skew)
+	if !ok {
+		return ConfirmTOTPResponse{}, ErrInvalidMFACode
+	}
+
+	// Confirming first makes sure that concurrent requests cannot both hand out recovery codes
+	if err := s.Repo.ConfirmTOTP(ctx, req.UserID, step, now); err != nil {
+		return ConfirmTOTPResponse{}, err
+	}
+
+	values, codes, err := s.generateRecoveryCodes(req.UserID)
+	if err != nil {
+		return ConfirmTOTPResponse{}, err
+	}
+	if err := s.Repo.ReplaceRecoveryCodes(ctx, req.UserID, codes); err != nil {
+		return ConfirmTOTPResponse{}, err
+	}
+
+	return ConfirmTOTPResponse{values}, nil
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type DecideDeviceCodeRequest struct {
+	UserCode string
+	// Session of the signed in user on the verification page
+	Session *Session
+	Approve bool
+}
+
+type DecideDeviceCodeResponse struct {
+	DeviceCode *DeviceCode
+}
+
+// DecideDeviceCode records whether the signed in user approved or denied a device.
+// A user code can only be decided once, the device learns the decision on its next poll.
+func (s *Service) DecideDeviceCode(ctx context.Context, req DecideDeviceCodeRequest) (DecideDeviceCodeResponse, error) {
+	status := DeviceCodeDenied
+	if req.Approve {
+		status = DeviceCodeApproved
+	}
+
+	code, err := s.Repo.DecideDeviceCode(ctx, hashUserCode(req.UserCode), &DeviceCode{
+		Status:   status,
+		UserID:   &req.Session.UserID,
+		AMR:      req.Session.AMR,
+		AuthTime: &req.Session.CreatedAt,
+	}, time.Now())
+	if err != nil {
+		return DecideDeviceCodeResponse{}, err
+	}
+
+	return DecideDeviceCodeResponse{DeviceCode: code}, nil
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type DeleteWebAuthnCredentialRequest struct {
+	UserID       uuid.UUID
+	CredentialID []byte
+}
+
+// DeleteWebAuthnCredential removes a passkey of the user, which can no longer be used to log in.
+func (s *Service) DeleteWebAuthnCredential(ctx context.Context, req DeleteWebAuthnCredentialRequest) error {
+	return s.Repo.DeleteWebAuthnCredential(ctx, req.UserID, req.CredentialID)
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type DisableTOTPRequest struct {
+	UserID uuid.UUID
+	// One-time password or recovery code
+	Code string
+}
+
+// DisableTOTP removes the TOTP factor and the recovery codes of the user.
+// A valid code is required, so that a stolen access token alone cannot turn the second factor off.
+func (s *Service) DisableTOTP(ctx context.Context, req DisableTOTPRequest) error {
+	if _, err := s.verifySecondFactorAttempt(ctx, req.UserID, req.Code); err != nil {
+		return err
+	}
+	return s.Repo.DeleteTOTP(ctx, req.UserID)
+}
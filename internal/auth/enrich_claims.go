@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"auth/internal/client"
+
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+// ClaimsEnricher adds custom claims to the access tokens issued by the service,
+// such as tenant IDs, roles or feature flags. It is called before every access token is signed,
+// including refreshed ones, and an error fails the grant that issues the token.
+type ClaimsEnricher interface {
+	EnrichClaims(context.Context, EnrichClaimsRequest) (EnrichClaimsResponse, error)
+}
+
+type EnrichClaimsRequest struct {
+	// uuid.Nil for tokens issued to a client on its own behalf
+	UserID uuid.UUID
+	// Nil for tokens of the password grant
+	Client *client.Client
+	// Scopes granted to the token
+	Scope []string
+}
+
+type EnrichClaimsResponse struct {
+	// Claims must not collide with the claims set by the service
+	Claims map[string]any
+}
+
+// ClaimsEnricherFunc adapts an ordinary function to a ClaimsEnricher.
+type ClaimsEnricherFunc func(context.Context, EnrichClaimsRequest) (EnrichClaimsResponse, error)
+
+func (f ClaimsEnricherFunc) EnrichClaims(ctx context.Context, req EnrichClaimsRequest) (EnrichClaimsResponse, error) {
+	return f(ctx, req)
+}
+
+// reservedClaims are set by the service and checked by the guard, so they cannot be overridden.
+var reservedClaims = []string{
+	"iss", "sub", "aud", "exp", "nbf", "iat", "jti",
+	ClaimTokenVersion, ClaimAMR, ClaimClientID, ClaimScope, ClaimAdmin, ClaimRoles, ClaimPermissions,
+	ClaimAuthorizedParty, ClaimAuthTime, ClaimNonce,
+}
+
+// enrichClaims adds the claims of the enricher of the service, if any, to the token being built.
+func (s *Service) enrichClaims(ctx context.Context, builder *jwt.Builder, req EnrichClaimsRequest) (*jwt.Builder, error) {
+	if s.Claims == nil {
+		return builder, nil
+	}
+
+	enriched, err := s.Claims.EnrichClaims(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range enriched.Claims {
+		if slices.Contains(reservedClaims, name) {
+			return nil, fmt.Errorf("%w (%q)", ErrReservedClaim, name)
+		}
+		builder = builder.Claim(name, value)
+	}
+	return builder, nil
+}
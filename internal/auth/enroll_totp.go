@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+)
+
+type EnrollTOTPRequest struct {
+	UserID uuid.UUID
+}
+
+type EnrollTOTPResponse struct {
+	Secret string
+	// otpauth:// URI scanned by authenticator apps
+	URI string
+	// QR code of URI, PNG encoded
+	QRCode []byte
+}
+
+// EnrollTOTP generates a new TOTP secret for the user, replacing any pending enrollment.
+// The factor is only enabled once confirmed with a first valid code.
+func (s *Service) EnrollTOTP(ctx context.Context, req EnrollTOTPRequest) (EnrollTOTPResponse, error) {
+	u, err := s.UserRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return EnrollTOTPResponse{}, err
+	}
+
+	existing, err := s.Repo.FindTOTP(ctx, u.ID)
+	if err != nil && err != ErrTOTPNotFound {
+		return EnrollTOTPResponse{}, err
+	}
+	if existing != nil && existing.ConfirmedAt != nil {
+		return EnrollTOTPResponse{}, ErrTOTPAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.MFA.Issuer,
+		AccountName: u.Email,
+		Period:      totpPeriod,
+		Digits:      totpDigits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return EnrollTOTPResponse{}, err
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return EnrollTOTPResponse{}, err
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		return EnrollTOTPResponse{}, err
+	}
+
+	now := time.Now()
+	err = s.Repo.SaveTOTP(ctx, &TOTP{
+		UserID:    u.ID,
+		Secret:    key.Secret(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return EnrollTOTPResponse{}, err
+	}
+
+	return EnrollTOTPResponse{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: qr.Bytes(),
+	}, nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/client"
+	"auth/internal/user"
+	"auth/pkg/secret"
+)
+
+type ExchangeAuthorizationCodeRequest struct {
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	ClientID     string
+	// Empty for public clients
+	ClientSecret string
+}
+
+type ExchangeAuthorizationCodeResponse struct {
+	ClientTokens
+}
+
+// ExchangeAuthorizationCode redeems a code issued by the authorization endpoint (RFC 6749 section 4.1.3).
+// Presenting a code twice revokes the tokens issued from it, since it has leaked (RFC 6749 section 4.1.2).
+// Access tokens cannot be told apart from the other ones of the user, so all of them are invalidated.
+func (s *Service) ExchangeAuthorizationCode(ctx context.Context, req ExchangeAuthorizationCodeRequest) (ExchangeAuthorizationCodeResponse, error) {
+	c, err := s.authenticateRegisteredClient(ctx, AuthenticateClientRequest{
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+	})
+	if err != nil {
+		return ExchangeAuthorizationCodeResponse{}, err
+	}
+
+	if !c.AllowsGrant(client.GrantAuthorizationCode) {
+		return ExchangeAuthorizationCodeResponse{}, ErrUnauthorizedClient
+	}
+
+	code, err := s.Repo.UseAuthorizationCode(ctx, secret.Hash(req.Code), c.ID, req.RedirectURI, time.Now())
+	if err != nil {
+		if err == ErrAuthorizationCodeReused {
+			// Refresh tokens issued from the code belong to a family named after it
+			if err := s.Repo.RevokeRefreshTokenFamily(ctx, code.ID); err != nil {
+				return ExchangeAuthorizationCodeResponse{}, err
+			}
+			if err := s.UserRepo.BumpTokenVersion(ctx, code.UserID); err != nil && err != user.ErrNotFoundByID {
+				return ExchangeAuthorizationCodeResponse{}, err
+			}
+		}
+		return ExchangeAuthorizationCodeResponse{}, err
+	}
+
+	if !verifyCodeChallenge(req.CodeVerifier, code.CodeChallenge) {
+		return ExchangeAuthorizationCodeResponse{}, ErrInvalidCodeVerifier
+	}
+
+	// The user may have been deleted since the code was issued
+	u, err := s.UserRepo.FindByID(ctx, code.UserID)
+	if err != nil {
+		return ExchangeAuthorizationCodeResponse{}, err
+	}
+	// Or disabled
+	if u.DisabledAt != nil {
+		return ExchangeAuthorizationCodeResponse{}, ErrUserDisabled
+	}
+
+	tokens, err := s.issueClientTokens(ctx, issueClientTokensRequest{
+		Client:   c,
+		User:     u,
+		Scope:    code.Scope,
+		AMR:      code.AMR,
+		AuthTime: code.AuthTime,
+		Nonce:    code.Nonce,
+		FamilyID: code.ID,
+	})
+	if err != nil {
+		return ExchangeAuthorizationCodeResponse{}, err
+	}
+
+	return ExchangeAuthorizationCodeResponse{tokens}, nil
+}
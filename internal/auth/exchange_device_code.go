@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/client"
+	"auth/pkg/secret"
+)
+
+type ExchangeDeviceCodeRequest struct {
+	DeviceCode string
+	ClientID   string
+	// Empty for public clients
+	ClientSecret string
+}
+
+type ExchangeDeviceCodeResponse struct {
+	ClientTokens
+}
+
+// ExchangeDeviceCode answers a device polling the token endpoint (RFC 8628 section 3.4).
+// Until the user decides, it fails with ErrAuthorizationPending, or with ErrSlowDown when the device
+// polls faster than its interval, which is then increased by 5 seconds (RFC 8628 section 3.5).
+func (s *Service) ExchangeDeviceCode(ctx context.Context, req ExchangeDeviceCodeRequest) (ExchangeDeviceCodeResponse, error) {
+	c, err := s.authenticateRegisteredClient(ctx, AuthenticateClientRequest{
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+	})
+	if err != nil {
+		return ExchangeDeviceCodeResponse{}, err
+	}
+
+	if !c.AllowsGrant(client.GrantDeviceCode) {
+		return ExchangeDeviceCodeResponse{}, ErrUnauthorizedClient
+	}
+
+	code, err := s.Repo.FindDeviceCodeByHash(ctx, secret.Hash(req.DeviceCode))
+	if err != nil {
+		return ExchangeDeviceCodeResponse{}, err
+	}
+
+	if code.ClientID != c.ID || code.UsedAt != nil {
+		return ExchangeDeviceCodeResponse{}, ErrInvalidDeviceCode
+	}
+
+	now := time.Now()
+	if now.After(code.ExpiresAt) {
+		return ExchangeDeviceCodeResponse{}, ErrDeviceCodeExpired
+	}
+
+	if now.Before(code.NextPollAt) {
+		interval := code.PollInterval + 5
+		if err := s.Repo.SlowDownDeviceCode(ctx, code.ID, interval, now.Add(time.Duration(interval)*time.Second)); err != nil {
+			return ExchangeDeviceCodeResponse{}, err
+		}
+		return ExchangeDeviceCodeResponse{}, ErrSlowDown
+	}
+
+	// Concurrent polls are too fast as well
+	polled, err := s.Repo.PollDeviceCode(ctx, code.ID, code.NextPollAt, now.Add(time.Duration(code.PollInterval)*time.Second))
+	if err != nil {
+		return ExchangeDeviceCodeResponse{}, err
+	}
+	if !polled {
+		return ExchangeDeviceCodeResponse{}, ErrSlowDown
+	}
+
+	switch code.Status {
+	case DeviceCodePending:
+		return ExchangeDeviceCodeResponse{}, ErrAuthorizationPending
+	case DeviceCodeDenied:
+		return ExchangeDeviceCodeResponse{}, ErrAccessDenied
+	}
+
+	if err := s.Repo.UseDeviceCode(ctx, code.ID, now); err != nil {
+		return ExchangeDeviceCodeResponse{}, err
+	}
+
+	// The user may have been deleted since they approved the device
+	u, err := s.UserRepo.FindByID(ctx, *code.UserID)
+	if err != nil {
+		return ExchangeDeviceCodeResponse{}, err
+	}
+	// Or disabled
+	if u.DisabledAt != nil {
+		return ExchangeDeviceCodeResponse{}, ErrUserDisabled
+	}
+
+	tokens, err := s.issueClientTokens(ctx, issueClientTokensRequest{
+		Client:   c,
+		User:     u,
+		Scope:    code.Scope,
+		AMR:      code.AMR,
+		AuthTime: *code.AuthTime,
+		FamilyID: code.ID,
+	})
+	if err != nil {
+		return ExchangeDeviceCodeResponse{}, err
+	}
+
+	return ExchangeDeviceCodeResponse{tokens}, nil
+}
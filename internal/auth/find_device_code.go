@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/client"
+)
+
+type FindDeviceCodeRequest struct {
+	UserCode string
+}
+
+type FindDeviceCodeResponse struct {
+	DeviceCode *DeviceCode
+	// Client shown to the user before they approve the device
+	Client *client.Client
+}
+
+// FindDeviceCode finds the pending device authorization of a user code typed on the verification page.
+func (s *Service) FindDeviceCode(ctx context.Context, req FindDeviceCodeRequest) (FindDeviceCodeResponse, error) {
+	code, err := s.Repo.FindDeviceCodeByUserCode(ctx, hashUserCode(req.UserCode))
+	if err != nil {
+		return FindDeviceCodeResponse{}, err
+	}
+
+	if code.Status != DeviceCodePending || time.Now().After(code.ExpiresAt) {
+		return FindDeviceCodeResponse{}, ErrInvalidUserCode
+	}
+
+	c, err := s.ClientRepo.FindByID(ctx, code.ClientID)
+	if err != nil {
+		if err == client.ErrNotFoundByID {
+			return FindDeviceCodeResponse{}, ErrInvalidUserCode
+		}
+		return FindDeviceCodeResponse{}, err
+	}
+
+	return FindDeviceCodeResponse{DeviceCode: code, Client: c}, nil
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+type FinishWebAuthnLoginRequest struct {
+	CeremonyToken string
+	// JSON encoded PublicKeyCredential returned by navigator.credentials.get()
+	Credential io.Reader
+}
+
+type FinishWebAuthnLoginResponse struct {
+	GenerateTokenResponse
+	RefreshToken string
+}
+
+// FinishWebAuthnLogin verifies the assertion of the authenticator and issues tokens to the owner of the passkey.
+// Passkeys are verified by the authenticator with a biometric or a PIN, so they count as multi-factor
+// and are not followed by a one-time password.
+func (s *Service) FinishWebAuthnLogin(ctx context.Context, req FinishWebAuthnLoginRequest) (FinishWebAuthnLoginResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, err
+	}
+
+	_, session, err := s.useWebAuthnCeremony(ctx, req.CeremonyToken, CeremonyLogin)
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(req.Credential)
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, fmt.Errorf("%w: %w", ErrInvalidWebAuthnResponse, err)
+	}
+
+	var owner *webAuthnUser
+	findOwner := func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		owner, err = s.findWebAuthnUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return owner, nil
+	}
+
+	validated, err := rp.ValidateDiscoverableLogin(findOwner, session, parsed)
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, fmt.Errorf("%w: %w", ErrInvalidWebAuthnResponse, err)
+	}
+	if validated.Authenticator.CloneWarning {
+		return FinishWebAuthnLoginResponse{}, ErrWebAuthnCredentialCloned
+	}
+
+	err = s.Repo.UpdateWebAuthnSignCount(ctx, validated.ID, validated.Authenticator.SignCount, validated.Flags.BackupState, time.Now())
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, err
+	}
+
+	u := owner.user
+	if s.Verification.Required && !u.EmailVerified {
+		return FinishWebAuthnLoginResponse{}, ErrEmailNotVerified
+	}
+	if u.DisabledAt != nil {
+		return FinishWebAuthnLoginResponse{}, ErrUserDisabled
+	}
+
+	// Passkey logins are granted every scope that users may be granted, as a password grant requesting none
+	amr := []string{AMRHardwareKey, AMRMFA}
+	scope := s.JWTConfig.Scopes
+	token, err := s.GenerateToken(ctx, GenerateTokenRequest{UserID: u.ID, TokenVersion: u.TokenVersion, AMR: amr, Scope: scope, Admin: s.isAdmin(u)})
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, err
+	}
+
+	refresh, err := s.IssueRefreshToken(ctx, IssueRefreshTokenRequest{UserID: u.ID, AMR: amr, Scope: scope})
+	if err != nil {
+		return FinishWebAuthnLoginResponse{}, err
+	}
+
+	return FinishWebAuthnLoginResponse{token, refresh.RefreshToken}, nil
+}
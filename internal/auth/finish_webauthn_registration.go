@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+)
+
+// Name of passkeys registered without one
+const defaultWebAuthnCredentialName = "Passkey"
+
+type FinishWebAuthnRegistrationRequest struct {
+	UserID        uuid.UUID
+	CeremonyToken string
+	Name          string
+	// JSON encoded PublicKeyCredential returned by navigator.credentials.create()
+	Credential io.Reader
+}
+
+type FinishWebAuthnRegistrationResponse struct {
+	Credential *WebAuthnCredential
+}
+
+// FinishWebAuthnRegistration verifies the attestation of the authenticator and stores the new passkey.
+func (s *Service) FinishWebAuthnRegistration(ctx context.Context, req FinishWebAuthnRegistrationRequest) (FinishWebAuthnRegistrationResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return FinishWebAuthnRegistrationResponse{}, err
+	}
+
+	ceremony, session, err := s.useWebAuthnCeremony(ctx, req.CeremonyToken, CeremonyRegistration)
+	if err != nil {
+		return FinishWebAuthnRegistrationResponse{}, err
+	}
+	if ceremony.UserID == nil || *ceremony.UserID != req.UserID {
+		return FinishWebAuthnRegistrationResponse{}, ErrInvalidWebAuthnCeremony
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(req.Credential)
+	if err != nil {
+		return FinishWebAuthnRegistrationResponse{}, fmt.Errorf("%w: %w", ErrInvalidWebAuthnResponse, err)
+	}
+
+	u, err := s.findWebAuthnUser(ctx, req.UserID)
+	if err != nil {
+		return FinishWebAuthnRegistrationResponse{}, err
+	}
+
+	created, err := rp.CreateCredential(u, session, parsed)
+	if err != nil {
+		return FinishWebAuthnRegistrationResponse{}, fmt.Errorf("%w: %w", ErrInvalidWebAuthnResponse, err)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultWebAuthnCredentialName
+	}
+
+	transports := make([]string, 0, len(created.Transport))
+	for _, t := range created.Transport {
+		transports = append(transports, string(t))
+	}
+
+	credential := &WebAuthnCredential{
+		ID:              created.ID,
+		UserID:          u.user.ID,
+		Name:            name,
+		PublicKey:       created.PublicKey,
+		AttestationType: created.AttestationType,
+		AAGUID:          created.Authenticator.AAGUID,
+		SignCount:       created.Authenticator.SignCount,
+		Transports:      transports,
+		BackupEligible:  created.Flags.BackupEligible,
+		BackupState:     created.Flags.BackupState,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.Repo.InsertWebAuthnCredential(ctx, credential); err != nil {
+		return FinishWebAuthnRegistrationResponse{}, err
+	}
+
+	return FinishWebAuthnRegistrationResponse{Credential: credential}, nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+
+	"auth/pkg/secret"
+
+	"github.com/alexedwards/argon2id"
+	"github.com/google/uuid"
+)
+
+type ForcePasswordResetRequest struct {
+	UserID uuid.UUID
+}
+
+// ForcePasswordReset replaces the password of the user with a random one that nobody knows,
+// signs the user out everywhere and emails a password reset token,
+// so that the user cannot sign in with a password again until choosing a new one.
+func (s *Service) ForcePasswordReset(ctx context.Context, req ForcePasswordResetRequest) error {
+	u, err := s.UserRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	unknown, err := secret.Generate(32)
+	if err != nil {
+		return err
+	}
+	hashedPasswd, err := argon2id.CreateHash(unknown, argon2id.DefaultParams)
+	if err != nil {
+		return err
+	}
+	u.Password = hashedPasswd
+
+	// Bumps the token version, which invalidates access tokens and sessions
+	if err := s.UserRepo.UpdatePassword(ctx, u); err != nil {
+		return err
+	}
+	if err := s.Repo.RevokeUserRefreshTokens(ctx, u.ID); err != nil {
+		return err
+	}
+
+	return s.sendPasswordReset(ctx, u)
+}
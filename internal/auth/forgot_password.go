@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+)
+
+type ForgotPasswordRequest struct {
+	Email string
+}
+
+// ForgotPassword emails a password reset token to the user, replacing any previous one.
+// Unknown emails are silently ignored so that the outcome does not reveal whether an account exists.
+func (s *Service) ForgotPassword(ctx context.Context, req ForgotPasswordRequest) error {
+	u, err := s.UserRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if err == user.ErrNotFoundByEmail {
+			return nil
+		}
+		return err
+	}
+	return s.sendPasswordReset(ctx, u)
+}
+
+// sendPasswordReset emails a password reset token to the user. Only the most recent one can be used.
+func (s *Service) sendPasswordReset(ctx context.Context, u *user.User) error {
+	if err := s.Repo.DeletePasswordResetTokens(ctx, u.ID); err != nil {
+		return err
+	}
+
+	token, err := secret.Generate(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ttl := time.Duration(s.Reset.TTL) * time.Second
+	err = s.Repo.InsertPasswordResetToken(ctx, &PasswordResetToken{
+		UserID:    u.ID,
+		Hash:      secret.Hash(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.Templates.Render("", "reset_password", u.Email, struct {
+		Token     string
+		Link      string
+		ExpiresIn int
+	}{
+		Token:     token,
+		Link:      tokenLink(s.Reset.URL, token),
+		ExpiresIn: s.Reset.TTL / 60,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(ctx, msg)
+}
+
+// tokenLink appends the token to the configured URL, if there is one.
+func tokenLink(base, token string) string {
+	if base == "" {
+		return ""
+	}
+	return base + token
+}
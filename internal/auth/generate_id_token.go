@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"auth/internal/client"
+	"auth/internal/user"
+
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+// OpenID Connect scopes (OIDC Core section 5.4)
+const (
+	ScopeOpenID  = "openid"
+	ScopeEmail   = "email"
+	ScopeProfile = "profile"
+)
+
+// ID token claims (OIDC Core section 2)
+const (
+	ClaimAuthTime = "auth_time"
+	ClaimNonce    = "nonce"
+	// ClaimAuthorizedParty is only carried by ID tokens,
+	// which is how they are told apart from access tokens.
+	ClaimAuthorizedParty = "azp"
+)
+
+type GenerateIDTokenRequest struct {
+	User     *user.User
+	Client   *client.Client
+	Scope    []string
+	Nonce    string
+	AMR      []string
+	AuthTime time.Time
+}
+
+type GenerateIDTokenResponse struct {
+	IDToken []byte
+}
+
+// GenerateIDToken issues the ID token of an OpenID Connect request, which proves to the client
+// that the user authenticated. It is never accepted as an access token.
+func (s *Service) GenerateIDToken(ctx context.Context, req GenerateIDTokenRequest) (GenerateIDTokenResponse, error) {
+	now := time.Now()
+
+	expiration := s.JWTConfig.Expiration
+	if req.Client.AccessTokenTTL > 0 {
+		expiration = req.Client.AccessTokenTTL
+	}
+
+	builder := jwt.NewBuilder().
+		Issuer(s.JWTConfig.Issuer).
+		Subject(req.User.ID.String()).
+		Audience([]string{req.Client.ID}).
+		Expiration(now.Add(time.Duration(expiration)*time.Second)).
+		IssuedAt(now).
+		Claim(ClaimAuthorizedParty, req.Client.ID).
+		Claim(ClaimAuthTime, req.AuthTime.Unix())
+	if req.Nonce != "" {
+		builder = builder.Claim(ClaimNonce, req.Nonce)
+	}
+	if len(req.AMR) > 0 {
+		builder = builder.Claim(ClaimAMR, req.AMR)
+	}
+	for name, value := range userClaims(req.User, req.Scope) {
+		builder = builder.Claim(name, value)
+	}
+
+	token, err := builder.Build()
+	if err != nil {
+		return GenerateIDTokenResponse{}, err
+	}
+
+	signed, err := s.sign(token)
+	if err != nil {
+		return GenerateIDTokenResponse{}, err
+	}
+
+	return GenerateIDTokenResponse{IDToken: signed}, nil
+}
+
+// userClaims returns the claims about the user released by the granted scopes (OIDC Core section 5.4),
+// shared by ID tokens and the userinfo endpoint. The subject is always released.
+func userClaims(u *user.User, scope []string) map[string]any {
+	claims := map[string]any{"sub": u.ID.String()}
+	if slices.Contains(scope, ScopeEmail) {
+		claims["email"] = u.Email
+		claims["email_verified"] = u.EmailVerified
+	}
+	if slices.Contains(scope, ScopeProfile) {
+		// Profile fields the user has not set are left out rather than released empty
+		profile := map[string]*string{
+			"name":     u.DisplayName,
+			"locale":   u.Locale,
+			"zoneinfo": u.Timezone,
+			"picture":  u.AvatarURL,
+		}
+		for name, value := range profile {
+			if value != nil {
+				claims[name] = *value
+			}
+		}
+		claims["updated_at"] = u.UpdatedAt.Unix()
+	}
+	return claims
+}
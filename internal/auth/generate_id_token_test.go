@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"auth/internal/user"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserClaims(t *testing.T) {
+	name, locale, timezone, avatar := "Kaká", "pt-BR", "America/Sao_Paulo", "https://spfc.com/kaka.png"
+	u := &user.User{
+		ID:            uuid.New(),
+		Email:         "kaka@spfc.com",
+		EmailVerified: true,
+		DisplayName:   &name,
+		Locale:        &locale,
+		Timezone:      &timezone,
+		AvatarURL:     &avatar,
+		UpdatedAt:     time.Unix(1700000000, 0),
+	}
+	unset := &user.User{ID: u.ID, UpdatedAt: u.UpdatedAt}
+
+	tests := []struct {
+		name  string
+		user  *user.User
+		scope []string
+		want  map[string]any
+	}{
+		{
+			name:  "openid",
+			user:  u,
+			scope: []string{ScopeOpenID},
+			want:  map[string]any{"sub": u.ID.String()},
+		},
+		{
+			name:  "email",
+			user:  u,
+			scope: []string{ScopeOpenID, ScopeEmail},
+			want:  map[string]any{"sub": u.ID.String(), "email": "kaka@spfc.com", "email_verified": true},
+		},
+		{
+			name:  "profile",
+			user:  u,
+			scope: []string{ScopeOpenID, ScopeProfile},
+			want: map[string]any{
+				"sub":        u.ID.String(),
+				"name":       "Kaká",
+				"locale":     "pt-BR",
+				"zoneinfo":   "America/Sao_Paulo",
+				"picture":    "https://spfc.com/kaka.png",
+				"updated_at": int64(1700000000),
+			},
+		},
+		{
+			name:  "profile_not_set",
+			user:  unset,
+			scope: []string{ScopeOpenID, ScopeProfile},
+			want:  map[string]any{"sub": u.ID.String(), "updated_at": int64(1700000000)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, userClaims(test.user, test.scope))
+		})
+	}
+}
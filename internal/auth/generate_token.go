@@ -2,15 +2,62 @@ package auth
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
+	"auth/internal/client"
+	"auth/internal/role"
+	"auth/internal/user"
+
 	"github.com/google/uuid"
 	"github.com/lestrrat-go/jwx/v3/jwa"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 )
 
+// ClaimTokenVersion is the private claim carrying the token version of the user,
+// checked by the guard so that a password change invalidates previously issued tokens.
+const ClaimTokenVersion = "ver"
+
+// ClaimAMR lists the methods used to authenticate the user (RFC 8176).
+const ClaimAMR = "amr"
+
+// ClaimClientID is the client that the token was issued to (RFC 9068).
+const ClaimClientID = "client_id"
+
+// ClaimScope is the space separated list of scopes granted to the token (RFC 9068).
+const ClaimScope = "scope"
+
+// ClaimAdmin marks the tokens of admins, which are granted every permission.
+const ClaimAdmin = "admin"
+
+// ClaimRoles lists the names of the roles assigned to the user.
+const ClaimRoles = "roles"
+
+// ClaimPermissions lists the permissions granted to the user by its roles,
+// which stay in the token until it expires even if the roles change meanwhile.
+const ClaimPermissions = "permissions"
+
+// Authentication method references (RFC 8176)
+const (
+	AMRPassword = "pwd"
+	AMROTP      = "otp"
+	AMRMFA      = "mfa"
+	// Proof of possession of a hardware-secured key, such as a passkey
+	AMRHardwareKey = "hwk"
+)
+
 type GenerateTokenRequest struct {
-	UserID uuid.UUID
+	UserID       uuid.UUID
+	TokenVersion int
+	AMR          []string
+	// Client is the client that the token is issued to, whose audiences and lifetime
+	// take precedence over the configured ones. Without a user, the token is issued
+	// on behalf of the client itself, which becomes its subject.
+	Client *client.Client
+	Scope  []string
+	// Admin grants the admin claim, which is never granted to tokens issued to a client
+	Admin bool
 }
 
 type GenerateTokenResponse struct {
@@ -22,20 +69,78 @@ type GenerateTokenResponse struct {
 func (s *Service) GenerateToken(ctx context.Context, req GenerateTokenRequest) (GenerateTokenResponse, error) {
 	now := time.Now()
 
-	token, err := jwt.NewBuilder().
+	subject := req.UserID.String()
+	audience := s.JWTConfig.Audience
+	expiration := s.JWTConfig.Expiration
+	if req.Client != nil {
+		if req.UserID == uuid.Nil {
+			subject = req.Client.ID
+		}
+		if len(req.Client.Audiences) > 0 {
+			audience = req.Client.Audiences
+		}
+		if req.Client.AccessTokenTTL > 0 {
+			expiration = req.Client.AccessTokenTTL
+		}
+	}
+
+	builder := jwt.NewBuilder().
 		Issuer(s.JWTConfig.Issuer).
-		Subject(req.UserID.String()).
-		Audience(s.JWTConfig.Audience).
-		Expiration(now.Add(time.Duration(s.JWTConfig.Expiration) * time.Second)).
+		Subject(subject).
+		Audience(audience).
+		Expiration(now.Add(time.Duration(expiration) * time.Second)).
 		NotBefore(now).
 		IssuedAt(now).
-		JwtID(uuid.NewString()).
-		Build()
+		JwtID(uuid.NewString())
+	// Only user tokens are invalidated by a password change
+	if req.UserID != uuid.Nil {
+		builder = builder.Claim(ClaimTokenVersion, req.TokenVersion)
+	}
+	if len(req.AMR) > 0 {
+		builder = builder.Claim(ClaimAMR, req.AMR)
+	}
+	if req.Client != nil {
+		builder = builder.Claim(ClaimClientID, req.Client.ID)
+	}
+	if len(req.Scope) > 0 {
+		builder = builder.Claim(ClaimScope, strings.Join(req.Scope, " "))
+	}
+	if req.Admin && req.Client == nil {
+		builder = builder.Claim(ClaimAdmin, true)
+	}
+	// Just like the admin claim, roles are never granted to tokens issued to a client
+	if req.UserID != uuid.Nil && req.Client == nil && s.RoleRepo != nil {
+		roles, err := (&role.Service{Repo: s.RoleRepo}).FindUserRoles(ctx, role.FindUserRolesRequest{UserID: req.UserID})
+		if err != nil {
+			return GenerateTokenResponse{}, err
+		}
+		if len(roles.Roles) > 0 {
+			names := make([]string, 0, len(roles.Roles))
+			for _, r := range roles.Roles {
+				names = append(names, r.Name)
+			}
+			builder = builder.Claim(ClaimRoles, names)
+		}
+		if len(roles.Permissions) > 0 {
+			builder = builder.Claim(ClaimPermissions, roles.Permissions)
+		}
+	}
+
+	builder, err := s.enrichClaims(ctx, builder, EnrichClaimsRequest{
+		UserID: req.UserID,
+		Client: req.Client,
+		Scope:  req.Scope,
+	})
+	if err != nil {
+		return GenerateTokenResponse{}, err
+	}
+
+	token, err := builder.Build()
 	if err != nil {
 		return GenerateTokenResponse{}, err
 	}
 
-	signed, err := jwt.Sign(token, jwt.WithKey(jwa.HS256(), []byte(s.JWTConfig.Key)))
+	signed, err := s.sign(token)
 	if err != nil {
 		return GenerateTokenResponse{}, err
 	}
@@ -43,6 +148,33 @@ func (s *Service) GenerateToken(ctx context.Context, req GenerateTokenRequest) (
 	return GenerateTokenResponse{
 		AccessToken: signed,
 		TokenType:   "Bearer",
-		ExpiresIn:   s.JWTConfig.Expiration,
+		ExpiresIn:   expiration,
 	}, nil
 }
+
+// sign signs a token with the active key of the keyring.
+func (s *Service) sign(token jwt.Token) ([]byte, error) {
+	key := s.Keyring.Active()
+	if key == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	alg, ok := jwa.LookupSignatureAlgorithm(key.Algorithm)
+	if !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	// The signing key carries its "kid", which is stamped on the token header
+	return jwt.Sign(token, jwt.WithKey(alg, key.signKey))
+}
+
+// isAdmin tells whether the user is granted the admin claim. The email address must be verified,
+// otherwise anyone could claim it by registering with it first.
+func (s *Service) isAdmin(u *user.User) bool {
+	if s.Admin == nil || !u.EmailVerified {
+		return false
+	}
+	return slices.ContainsFunc(s.Admin.Users, func(email string) bool {
+		return strings.EqualFold(email, u.Email)
+	})
+}
@@ -0,0 +1,238 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+var (
+	ErrNoTokenFound  = errors.New("no token found")
+	ErrUnauthorized  = errors.New("token is unauthorized")
+	ErrTokenRevoked  = errors.New("token has been revoked")
+	ErrTokenOutdated = errors.New("token was issued before the last password change")
+	ErrIDToken       = errors.New("id tokens are not accepted as access tokens")
+)
+
+type contextKey struct{}
+
+// verification is the outcome of Verifier, stored in the request context.
+type verification struct {
+	token  jwt.Token
+	claims map[string]any
+	err    error
+}
+
+// Verifier checks the bearer token of requests against the keyring, selecting the
+// verification key by the "kid" header, and rejects ID tokens, tokens whose "jti"
+// is in the revocation denylist and tokens carrying an older version than the one
+// of their user. The result is stored in the request context, read by FromContext,
+// and it is up to Authenticator or the next handler to reject unauthenticated requests.
+func Verifier(keyring *auth.Keyring, repo auth.Repoer, userRepo user.Repoer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			token, err := verifyRequest(keyring, r)
+			if err == nil {
+				err = checkUse(token)
+			}
+			if err == nil {
+				err = checkRevocation(ctx, repo, token)
+			}
+			if err == nil {
+				err = checkVersion(ctx, userRepo, token)
+			}
+			ctx = newContext(ctx, token, err)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(hfn)
+	}
+}
+
+// Authenticator rejects requests whose token was not verified by Verifier.
+func Authenticator(next http.Handler) http.Handler {
+	hfn := func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := FromContext(r.Context())
+		switch {
+		case err == nil:
+			next.ServeHTTP(w, r)
+		case errors.Is(err, ErrNoTokenFound):
+			responder.RespondMetaMessage(w, r, http.StatusUnauthorized, "No Token Found")
+		case errors.Is(err, ErrUnauthorized):
+			responder.RespondMetaMessage(w, r, http.StatusUnauthorized, "Bearer Token not authorized")
+		default:
+			responder.RespondMetaMessage(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		}
+	}
+	return http.HandlerFunc(hfn)
+}
+
+// FromContext returns the token verified by Verifier along with its claims,
+// or the reason why it was rejected.
+func FromContext(ctx context.Context) (jwt.Token, map[string]any, error) {
+	v, ok := ctx.Value(contextKey{}).(*verification)
+	if !ok {
+		return nil, nil, ErrNoTokenFound
+	}
+	return v.token, v.claims, v.err
+}
+
+// newContext stores the outcome of a verification. Claims are decoded
+// as plain JSON values, so that arrays are []any and numbers float64.
+func newContext(ctx context.Context, token jwt.Token, err error) context.Context {
+	v := &verification{err: err}
+	if err == nil {
+		v.token = token
+		if v.err = decodeClaims(token, &v.claims); v.err != nil {
+			v.token = nil
+		}
+	}
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+func decodeClaims(token jwt.Token, claims *map[string]any) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, claims)
+}
+
+// RequirePermission rejects requests whose access token neither carries the admin claim,
+// which grants every permission, nor the given permission in its permissions claim.
+// It only inspects the verification result stored in the request context,
+// so it must follow Verifier and a middleware rejecting unauthenticated requests.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			_, claims, err := FromContext(r.Context())
+			if err != nil || !HasPermission(claims, permission) {
+				responder.RespondMetaMessage(w, r, http.StatusForbidden, fmt.Sprintf("The %q permission is required.", permission))
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(hfn)
+	}
+}
+
+// HasPermission reports whether the claims of a verified token, as returned by FromContext,
+// carry the admin claim or the given permission.
+func HasPermission(claims map[string]any, permission string) bool {
+	if admin, _ := claims[auth.ClaimAdmin].(bool); admin {
+		return true
+	}
+	granted, _ := claims[auth.ClaimPermissions].([]any)
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Subject returns a function reading the "sub" claim of the verified access token of a request,
+// for middlewares running before Verifier, such as the rate limiter.
+// Revocation is not checked, as the claim only identifies the caller.
+func Subject(keyring *auth.Keyring) func(*http.Request) (string, bool) {
+	return func(r *http.Request) (string, bool) {
+		token, err := verifyRequest(keyring, r)
+		if err != nil {
+			return "", false
+		}
+		sub, ok := token.Subject()
+		return sub, ok && sub != ""
+	}
+}
+
+func verifyRequest(keyring *auth.Keyring, r *http.Request) (jwt.Token, error) {
+	tokenString := tokenFromHeader(r)
+	if tokenString == "" {
+		tokenString = tokenFromCookie(r)
+	}
+	if tokenString == "" {
+		return nil, ErrNoTokenFound
+	}
+
+	// Tokens of clients with custom audiences are meant for other resource servers
+	token, err := keyring.Verify(tokenString, keyring.Intended()...)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	return token, nil
+}
+
+// tokenFromHeader reads a bearer token from the Authorization header.
+func tokenFromHeader(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return token
+}
+
+// tokenFromCookie reads a token from the "jwt" cookie.
+func tokenFromCookie(r *http.Request) string {
+	cookie, err := r.Cookie("jwt")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// checkUse rejects ID tokens, which are signed by the same keys as access tokens
+// but only prove to a client that the user authenticated.
+func checkUse(token jwt.Token) error {
+	if token.Has(auth.ClaimAuthorizedParty) {
+		return ErrIDToken
+	}
+	return nil
+}
+
+func checkRevocation(ctx context.Context, repo auth.Repoer, token jwt.Token) error {
+	jti, _ := token.JwtID()
+	revoked, err := repo.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// checkVersion compares the token version claim with the current version of the user.
+// Tokens issued before versions were introduced carry none and count as version 0.
+func checkVersion(ctx context.Context, userRepo user.Repoer, token jwt.Token) error {
+	sub, _ := token.Subject()
+	id, err := uuid.Parse(sub)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	u, err := userRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == user.ErrNotFoundByID {
+			return ErrUnauthorized
+		}
+		return err
+	}
+
+	var version float64
+	token.Get(auth.ClaimTokenVersion, &version)
+	if int(version) != u.TokenVersion {
+		return ErrTokenOutdated
+	}
+	return nil
+}
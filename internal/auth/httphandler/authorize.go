@@ -0,0 +1,174 @@
+package httphandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationAuthorize = "authorize"
+	FileAuthorize      = OperationAuthorize + ".go"
+)
+
+// authorizationParams are the parameters of an authorization request,
+// carried by the query of the request and then by the hidden fields of the login page.
+var authorizationParams = []string{"client_id", "redirect_uri", "response_type", "scope", "state", "nonce", "code_challenge", "code_challenge_method"}
+
+func (s *AuthServer) handleAuthorize() http.HandlerFunc {
+	const self = "handleAuthorize"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		validated, ok := s.validateAuthorization(w, r)
+		if !ok {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAuthorize))
+			return
+		}
+
+		// Users already signed in are sent straight back to the client
+		session, err := s.service.ResumeSession(ctx, s.cookie(r, cookieSession))
+		switch {
+		case err == nil:
+			s.redirectWithCode(w, r, validated, session)
+			return
+		case errors.Is(err, auth.ErrInvalidSession):
+		default:
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAuthorize))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileAuthorize, self, "failed to resume session", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+			return
+		}
+
+		csrfToken, err := s.csrfToken(w, r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationAuthorize))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileAuthorize, self, "failed to generate csrf token", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+			return
+		}
+
+		s.renderPage(w, r, http.StatusOK, "login.html.tmpl", loginPage{
+			ClientName: validated.Client.Name,
+			CSRFToken:  csrfToken,
+			Params:     pageParams(r),
+		})
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationAuthorize)
+	return otelhandler.ServeHTTP
+}
+
+// validateAuthorization validates the authorization request of the query or of the posted form.
+// An unknown client or redirect URI is shown to the user, while other errors are sent
+// back to the client as in RFC 6749 section 4.1.2.1. It reports whether the request is valid.
+func (s *AuthServer) validateAuthorization(w http.ResponseWriter, r *http.Request) (auth.ValidateAuthorizationResponse, bool) {
+	const self = "validateAuthorization"
+	ctx := r.Context()
+
+	validated, err := s.service.ValidateAuthorization(ctx, auth.ValidateAuthorizationRequest{
+		ClientID:            r.FormValue("client_id"),
+		RedirectURI:         r.FormValue("redirect_uri"),
+		ResponseType:        r.FormValue("response_type"),
+		Scope:               strings.Fields(r.FormValue("scope")),
+		CodeChallenge:       r.FormValue("code_challenge"),
+		CodeChallengeMethod: r.FormValue("code_challenge_method"),
+	})
+	if err == nil {
+		return validated, true
+	}
+	trace.SpanFromContext(ctx).RecordError(err)
+
+	switch err {
+	case auth.ErrInvalidClient:
+		s.renderError(w, r, http.StatusBadRequest, "The application is not registered.")
+	case auth.ErrInvalidRedirectURI:
+		s.renderError(w, r, http.StatusBadRequest, "The redirect URI is not registered for the application.")
+	case auth.ErrUnsupportedResponseType:
+		s.redirectWithError(w, r, "unsupported_response_type", "Only the code response type is supported.")
+	case auth.ErrUnauthorizedClient:
+		s.redirectWithError(w, r, "unauthorized_client", "Client is not allowed to use the authorization_code grant.")
+	case auth.ErrInvalidCodeChallenge:
+		s.redirectWithError(w, r, "invalid_request", "A S256 code_challenge is required.")
+	case auth.ErrInvalidScope:
+		s.redirectWithError(w, r, "invalid_scope", "Requested scope exceeds the scopes of the client.")
+	default:
+		s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileAuthorize, self, "failed to validate authorization request", err))
+		s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+	}
+	return validated, false
+}
+
+// redirectWithCode issues an authorization code to the signed in user and sends it to the client.
+func (s *AuthServer) redirectWithCode(w http.ResponseWriter, r *http.Request, validated auth.ValidateAuthorizationResponse, session *auth.Session) {
+	const self = "redirectWithCode"
+	ctx := r.Context()
+
+	issued, err := s.service.IssueAuthorizationCode(ctx, auth.IssueAuthorizationCodeRequest{
+		Client:        validated.Client,
+		Session:       session,
+		RedirectURI:   r.FormValue("redirect_uri"),
+		Scope:         validated.Scope,
+		CodeChallenge: r.FormValue("code_challenge"),
+		Nonce:         r.FormValue("nonce"),
+	})
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileAuthorize, self, "failed to issue authorization code", err))
+		s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+		return
+	}
+	s.logger.InfoContext(ctx, otel.FormatLog(Path, FileAuthorize, self, fmt.Sprintf("issued authorization code to client %q for user %q", validated.Client.ID, session.UserID.String()), nil))
+
+	s.redirect(w, r, url.Values{"code": {issued.Code}})
+}
+
+func (s *AuthServer) redirectWithError(w http.ResponseWriter, r *http.Request, code, description string) {
+	s.redirect(w, r, url.Values{"error": {code}, "error_description": {description}})
+}
+
+// redirect sends the user back to the validated redirect URI, along with the state of the request.
+// Posted forms are answered with 303 See Other, so that the browser follows with a GET.
+func (s *AuthServer) redirect(w http.ResponseWriter, r *http.Request, values url.Values) {
+	// The redirect URI was registered as an absolute URL, which can still carry a query of its own
+	target, _ := url.Parse(r.FormValue("redirect_uri"))
+	query := target.Query()
+	for name, value := range values {
+		query[name] = value
+	}
+	if state := r.FormValue("state"); state != "" {
+		query.Set("state", state)
+	}
+	target.RawQuery = query.Encode()
+
+	status := http.StatusFound
+	if r.Method == http.MethodPost {
+		status = http.StatusSeeOther
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, target.String(), status)
+}
+
+// pageParams returns the parameters of the authorization request that were actually sent.
+func pageParams(r *http.Request) []pageParam {
+	params := make([]pageParam, 0, len(authorizationParams))
+	for _, name := range authorizationParams {
+		if value := r.FormValue(name); value != "" {
+			params = append(params, pageParam{name, value})
+		}
+	}
+	return params
+}
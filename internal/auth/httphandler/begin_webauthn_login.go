@@ -0,0 +1,63 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationBeginWebAuthnLogin = "begin_webauthn_login"
+	FileBeginWebAuthnLogin      = OperationBeginWebAuthnLogin + ".go"
+)
+
+func (s *AuthServer) handleBeginWebAuthnLogin() http.HandlerFunc {
+	const self = "handleBeginWebAuthnLogin"
+
+	type response struct {
+		CeremonyToken string `json:"ceremony_token"`
+		ExpiresIn     int    `json:"expires_in"`
+		// Passed as is to navigator.credentials.get()
+		PublicKey protocol.PublicKeyCredentialRequestOptions `json:"publicKey"`
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		beginResponse, err := s.service.BeginWebAuthnLogin(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationBeginWebAuthnLogin))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileBeginWebAuthnLogin, self, "failed to begin webauthn login", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		resp := response{
+			CeremonyToken: beginResponse.CeremonyToken,
+			ExpiresIn:     beginResponse.ExpiresIn,
+			PublicKey:     beginResponse.Options.Response,
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationBeginWebAuthnLogin))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileBeginWebAuthnLogin, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationBeginWebAuthnLogin)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,78 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationBeginWebAuthnRegistration = "begin_webauthn_registration"
+	FileBeginWebAuthnRegistration      = OperationBeginWebAuthnRegistration + ".go"
+)
+
+func (s *AuthServer) handleBeginWebAuthnRegistration() http.HandlerFunc {
+	const self = "handleBeginWebAuthnRegistration"
+
+	type response struct {
+		CeremonyToken string `json:"ceremony_token"`
+		ExpiresIn     int    `json:"expires_in"`
+		// Passed as is to navigator.credentials.create()
+		PublicKey protocol.PublicKeyCredentialCreationOptions `json:"publicKey"`
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationBeginWebAuthnRegistration))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		beginResponse, err := s.service.BeginWebAuthnRegistration(ctx, auth.BeginWebAuthnRegistrationRequest{UserID: sub})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationBeginWebAuthnRegistration))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileBeginWebAuthnRegistration, self, "failed to begin webauthn registration", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		resp := response{
+			CeremonyToken: beginResponse.CeremonyToken,
+			ExpiresIn:     beginResponse.ExpiresIn,
+			PublicKey:     beginResponse.Options.Response,
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationBeginWebAuthnRegistration))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileBeginWebAuthnRegistration, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationBeginWebAuthnRegistration)
+	return otelhandler.ServeHTTP
+}
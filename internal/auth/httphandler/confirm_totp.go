@@ -0,0 +1,102 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationConfirmTOTP = "confirm_totp"
+	FileConfirmTOTP      = OperationConfirmTOTP + ".go"
+)
+
+func (s *AuthServer) handleConfirmTOTP() http.HandlerFunc {
+	const self = "handleConfirmTOTP"
+
+	type request struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	type response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	contract := map[string]responder.Field{
+		"Code": {
+			Name:       "code",
+			Validation: "Field value cannot be an empty string.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmTOTP))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmTOTP))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmTOTP))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		confirmTOTPResponse, err := s.service.ConfirmTOTP(ctx, auth.ConfirmTOTPRequest{
+			UserID: sub,
+			Code:   req.Code,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmTOTP))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInvalidMFACode:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid one-time password.")
+			case auth.ErrTOTPNotEnabled:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Two-factor authentication has not been enrolled.")
+			case auth.ErrTOTPAlreadyEnabled:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Two-factor authentication is already enabled.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileConfirmTOTP, self, "failed to confirm totp", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.mfaEnabledCounter.Add(ctx, 1)
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, response{confirmTOTPResponse.RecoveryCodes}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmTOTP))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileConfirmTOTP, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationConfirmTOTP)
+	return otelhandler.ServeHTTP
+}
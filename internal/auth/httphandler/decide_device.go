@@ -0,0 +1,104 @@
+package httphandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDecideDevice = "decide_device"
+	FileDecideDevice      = OperationDecideDevice + ".go"
+)
+
+// handleDecideDevice handles the forms of the verification page: the login page, after which
+// the user is sent back to the page, and the approval or denial of the device.
+func (s *AuthServer) handleDecideDevice() http.HandlerFunc {
+	const self = "handleDecideDevice"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		decision := r.PostFormValue("decision")
+		if decision == "" {
+			if _, ok := s.signIn(w, r, loginPage{ClientName: deviceLoginName}); !ok {
+				return
+			}
+
+			// Post/Redirect/Get, so that reloading the page does not post the credentials again
+			target := url.URL{Path: r.URL.Path}
+			if userCode := r.FormValue("user_code"); userCode != "" {
+				target.RawQuery = url.Values{"user_code": {userCode}}.Encode()
+			}
+			w.Header().Set("Cache-Control", "no-store")
+			http.Redirect(w, r, target.String(), http.StatusSeeOther)
+			return
+		}
+
+		page := devicePage{UserCode: r.PostFormValue("user_code")}
+		fail := func(status int, message string) {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDecideDevice))
+			page.Error = message
+			s.renderDevicePage(w, r, status, page)
+		}
+
+		if !s.checkCSRF(r) {
+			fail(http.StatusForbidden, "Your form has expired. Enter the code again.")
+			return
+		}
+
+		if decision != "approve" && decision != "deny" {
+			fail(http.StatusBadRequest, "Approve or deny the device.")
+			return
+		}
+
+		session, err := s.service.ResumeSession(ctx, s.cookie(r, cookieSession))
+		if err != nil {
+			span.RecordError(err)
+			if errors.Is(err, auth.ErrInvalidSession) {
+				fail(http.StatusUnauthorized, "Your session has expired. Sign in and enter the code again.")
+				return
+			}
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDecideDevice))
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDecideDevice, self, "failed to resume session", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+			return
+		}
+
+		decided, err := s.service.DecideDeviceCode(ctx, auth.DecideDeviceCodeRequest{
+			UserCode: page.UserCode,
+			Session:  session,
+			Approve:  decision == "approve",
+		})
+		if err != nil {
+			span.RecordError(err)
+			if err == auth.ErrInvalidUserCode {
+				fail(http.StatusBadRequest, "This code is invalid, has expired or was already used. Check the code shown on your device.")
+				return
+			}
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDecideDevice))
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDecideDevice, self, "failed to decide device code", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+			return
+		}
+		s.logger.InfoContext(ctx, otel.FormatLog(Path, FileDecideDevice, self, fmt.Sprintf("user %q decided device code of client %q: %s", session.UserID.String(), decided.DeviceCode.ClientID, decided.DeviceCode.Status), nil))
+
+		page = devicePage{Done: "Device approved"}
+		if decision == "deny" {
+			page.Done = "Device denied"
+		}
+		s.renderPage(w, r, http.StatusOK, "device.html.tmpl", page)
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDecideDevice)
+	return otelhandler.ServeHTTP
+}
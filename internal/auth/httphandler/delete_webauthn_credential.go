@@ -0,0 +1,74 @@
+package httphandler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDeleteWebAuthnCredential = "delete_webauthn_credential"
+	FileDeleteWebAuthnCredential      = OperationDeleteWebAuthnCredential + ".go"
+)
+
+func (s *AuthServer) handleDeleteWebAuthnCredential() http.HandlerFunc {
+	const self = "handleDeleteWebAuthnCredential"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteWebAuthnCredential))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		id, err := base64.RawURLEncoding.DecodeString(r.PathValue("credentialID"))
+		if err != nil || len(id) == 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteWebAuthnCredential))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Credential ID must be base64url encoded.")
+			return
+		}
+
+		err = s.service.DeleteWebAuthnCredential(ctx, auth.DeleteWebAuthnCredentialRequest{
+			UserID:       sub,
+			CredentialID: id,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteWebAuthnCredential))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrWebAuthnCredentialNotFound:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any passkey with provided ID.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteWebAuthnCredential, self, "failed to delete webauthn credential", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteWebAuthnCredential))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteWebAuthnCredential, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDeleteWebAuthnCredential)
+	return otelhandler.ServeHTTP
+}
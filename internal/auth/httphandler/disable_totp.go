@@ -0,0 +1,95 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDisableTOTP = "disable_totp"
+	FileDisableTOTP      = OperationDisableTOTP + ".go"
+)
+
+func (s *AuthServer) handleDisableTOTP() http.HandlerFunc {
+	const self = "handleDisableTOTP"
+
+	type request struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	contract := map[string]responder.Field{
+		"Code": {
+			Name:       "code",
+			Validation: "Field value cannot be an empty string.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableTOTP))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableTOTP))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableTOTP))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		err = s.service.DisableTOTP(ctx, auth.DisableTOTPRequest{
+			UserID: sub,
+			Code:   req.Code,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableTOTP))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInvalidMFACode:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid one-time password or recovery code.")
+			case auth.ErrTOTPNotEnabled:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Two-factor authentication is not enabled.")
+			case auth.ErrTooManyTOTPAttempts:
+				responder.RespondMetaMessage(w, r, http.StatusTooManyRequests, "Too many failed attempts. Try again later.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDisableTOTP, self, "failed to disable totp", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.RespondMetaMessage(w, r, http.StatusOK, "Two-factor authentication has been disabled."); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDisableTOTP))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDisableTOTP, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDisableTOTP)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,105 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDiscoverConfiguration = "discover_configuration"
+	FileDiscoverConfiguration      = OperationDiscoverConfiguration + ".go"
+)
+
+// Routes advertised by the discovery document
+const (
+	routeAuthorize     = "/oauth/authorize"
+	routeToken         = "/oauth/token"
+	routeRevoke        = "/oauth/revoke"
+	routeIntrospect    = "/oauth/introspect"
+	routeDeviceAuth    = "/oauth/device_authorization"
+	routeDevice        = "/device"
+	routeUserInfo      = "/userinfo"
+	routeJWKS          = "/.well-known/jwks.json"
+	routeConfiguration = "/.well-known/openid-configuration"
+)
+
+// handleDiscoverConfiguration serves the OpenID Provider metadata (OIDC Discovery section 3).
+// The document is found by appending its route to the issuer, so the issuer must be the public URL
+// of this server. Endpoints, grant types and signing algorithms are read from the running server.
+func (s *AuthServer) handleDiscoverConfiguration() http.HandlerFunc {
+	const self = "handleDiscoverConfiguration"
+
+	type response struct {
+		Issuer                            string   `json:"issuer"`
+		AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+		TokenEndpoint                     string   `json:"token_endpoint"`
+		UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+		RevocationEndpoint                string   `json:"revocation_endpoint"`
+		IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+		DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"`
+		JWKSURI                           string   `json:"jwks_uri"`
+		ScopesSupported                   []string `json:"scopes_supported"`
+		ResponseTypesSupported            []string `json:"response_types_supported"`
+		ResponseModesSupported            []string `json:"response_modes_supported"`
+		GrantTypesSupported               []string `json:"grant_types_supported"`
+		SubjectTypesSupported             []string `json:"subject_types_supported"`
+		IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+		TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+		CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+		ClaimsSupported                   []string `json:"claims_supported"`
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		base := strings.TrimSuffix(s.jwtConfig.Issuer, "/")
+		resp := response{
+			Issuer:                            s.jwtConfig.Issuer,
+			AuthorizationEndpoint:             base + routeAuthorize,
+			TokenEndpoint:                     base + routeToken,
+			UserInfoEndpoint:                  base + routeUserInfo,
+			RevocationEndpoint:                base + routeRevoke,
+			IntrospectionEndpoint:             base + routeIntrospect,
+			DeviceAuthorizationEndpoint:       base + routeDeviceAuth,
+			JWKSURI:                           base + routeJWKS,
+			ScopesSupported:                   []string{auth.ScopeOpenID, auth.ScopeEmail, auth.ScopeProfile},
+			ResponseTypesSupported:            []string{"code"},
+			ResponseModesSupported:            []string{"query"},
+			GrantTypesSupported:               grantTypes,
+			SubjectTypesSupported:             []string{"public"},
+			IDTokenSigningAlgValuesSupported:  s.keyring.Algorithms(),
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+			CodeChallengeMethodsSupported:     []string{auth.CodeChallengeS256},
+			ClaimsSupported: []string{
+				"iss", "sub", "aud", "exp", "iat",
+				auth.ClaimAuthTime, auth.ClaimNonce, auth.ClaimAMR, auth.ClaimAuthorizedParty,
+				"email", "email_verified", "name", "locale", "zoneinfo", "picture", "updated_at",
+			},
+		}
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", s.jwtConfig.RotationCheck))
+
+		// Provider metadata is not wrapped in a data field
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDiscoverConfiguration))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDiscoverConfiguration, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDiscoverConfiguration)
+	return otelhandler.ServeHTTP
+}
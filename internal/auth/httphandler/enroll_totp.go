@@ -0,0 +1,79 @@
+package httphandler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationEnrollTOTP = "enroll_totp"
+	FileEnrollTOTP      = OperationEnrollTOTP + ".go"
+)
+
+func (s *AuthServer) handleEnrollTOTP() http.HandlerFunc {
+	const self = "handleEnrollTOTP"
+
+	type response struct {
+		Secret string `json:"secret"`
+		URI    string `json:"otpauth_uri"`
+		QRCode string `json:"qr_code"`
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationEnrollTOTP))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		enrollTOTPResponse, err := s.service.EnrollTOTP(ctx, auth.EnrollTOTPRequest{UserID: sub})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationEnrollTOTP))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			case auth.ErrTOTPAlreadyEnabled:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Two-factor authentication is already enabled.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileEnrollTOTP, self, "failed to enroll totp", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		resp := response{
+			Secret: enrollTOTPResponse.Secret,
+			URI:    enrollTOTPResponse.URI,
+			QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollTOTPResponse.QRCode),
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationEnrollTOTP))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileEnrollTOTP, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationEnrollTOTP)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,121 @@
+package httphandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationFinishWebAuthnLogin = "finish_webauthn_login"
+	FileFinishWebAuthnLogin      = OperationFinishWebAuthnLogin + ".go"
+)
+
+func (s *AuthServer) handleFinishWebAuthnLogin() http.HandlerFunc {
+	const self = "handleFinishWebAuthnLogin"
+
+	type request struct {
+		CeremonyToken string `json:"ceremony_token" validate:"required"`
+		// PublicKeyCredential returned by navigator.credentials.get()
+		Credential json.RawMessage `json:"credential" validate:"required"`
+	}
+
+	type response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+
+	contract := map[string]responder.Field{
+		"CeremonyToken": {
+			Name:       "ceremony_token",
+			Validation: "Field value cannot be an empty string.",
+		},
+		"Credential": {
+			Name:       "credential",
+			Validation: "Field value must be the credential returned by the authenticator.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnLogin))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnLogin))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		finishResponse, err := s.service.FinishWebAuthnLogin(ctx, auth.FinishWebAuthnLoginRequest{
+			CeremonyToken: req.CeremonyToken,
+			Credential:    bytes.NewReader(req.Credential),
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnLogin))
+			span.RecordError(err)
+			switch {
+			case errors.Is(err, auth.ErrInvalidWebAuthnCeremony):
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid ceremony token.")
+			case errors.Is(err, auth.ErrWebAuthnCredentialCloned):
+				s.logger.WarnContext(ctx, otel.FormatLog(Path, FileFinishWebAuthnLogin, self, "webauthn signature counter went backwards", err))
+				fallthrough
+			case errors.Is(err, auth.ErrInvalidWebAuthnResponse):
+				s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_passkey")))
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid credential.")
+			case errors.Is(err, auth.ErrEmailNotVerified):
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Email address has not been verified.")
+			case errors.Is(err, auth.ErrUserDisabled):
+				responder.RespondMetaMessage(w, r, http.StatusForbidden, "User has been disabled.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFinishWebAuthnLogin, self, "failed to finish webauthn login", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.tokensGeneratedCounter.Add(ctx, 1)
+
+		resp := response{
+			AccessToken:  string(finishResponse.AccessToken),
+			RefreshToken: finishResponse.RefreshToken,
+			TokenType:    finishResponse.TokenType,
+			ExpiresIn:    finishResponse.ExpiresIn,
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnLogin))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFinishWebAuthnLogin, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationFinishWebAuthnLogin)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,114 @@
+package httphandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationFinishWebAuthnRegistration = "finish_webauthn_registration"
+	FileFinishWebAuthnRegistration      = OperationFinishWebAuthnRegistration + ".go"
+)
+
+func (s *AuthServer) handleFinishWebAuthnRegistration() http.HandlerFunc {
+	const self = "handleFinishWebAuthnRegistration"
+
+	type request struct {
+		CeremonyToken string `json:"ceremony_token" validate:"required"`
+		Name          string `json:"name" validate:"max=64"`
+		// PublicKeyCredential returned by navigator.credentials.create()
+		Credential json.RawMessage `json:"credential" validate:"required"`
+	}
+
+	contract := map[string]responder.Field{
+		"CeremonyToken": {
+			Name:       "ceremony_token",
+			Validation: "Field value cannot be an empty string.",
+		},
+		"Name": {
+			Name:       "name",
+			Validation: "Field value must have at most 64 characters.",
+		},
+		"Credential": {
+			Name:       "credential",
+			Validation: "Field value must be the credential returned by the authenticator.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnRegistration))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnRegistration))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnRegistration))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		finishResponse, err := s.service.FinishWebAuthnRegistration(ctx, auth.FinishWebAuthnRegistrationRequest{
+			UserID:        sub,
+			CeremonyToken: req.CeremonyToken,
+			Name:          req.Name,
+			Credential:    bytes.NewReader(req.Credential),
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnRegistration))
+			span.RecordError(err)
+			switch {
+			case errors.Is(err, auth.ErrInvalidWebAuthnCeremony):
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid ceremony token.")
+			case errors.Is(err, auth.ErrInvalidWebAuthnResponse):
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid credential.")
+			case errors.Is(err, auth.ErrWebAuthnCredentialExists):
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Passkey is already registered.")
+			case errors.Is(err, user.ErrNotFoundByID):
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFinishWebAuthnRegistration, self, "failed to finish webauthn registration", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusCreated, newWebAuthnCredentialResponse(finishResponse.Credential)); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFinishWebAuthnRegistration))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFinishWebAuthnRegistration, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationFinishWebAuthnRegistration)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,76 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationForgotPassword = "forgot_password"
+	FileForgotPassword      = OperationForgotPassword + ".go"
+)
+
+func (s *AuthServer) handleForgotPassword() http.HandlerFunc {
+	const self = "handleForgotPassword"
+
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	contract := map[string]responder.Field{
+		"Email": {
+			Name:       "email",
+			Validation: "Field is required and must be a valid email.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationForgotPassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationForgotPassword))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		err = s.service.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: req.Email})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationForgotPassword))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileForgotPassword, self, "failed to send password reset email", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		// The answer is the same whether or not the account exists
+		if err := responder.RespondMetaMessage(w, r, http.StatusAccepted, "If the email address belongs to an account, a password reset link has been sent."); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationForgotPassword))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileForgotPassword, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationForgotPassword)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,13 @@
+package httphandler
+
+import (
+	"mime"
+	"net/http"
+)
+
+// isForm tells whether the body of the request is form encoded, as required by the OAuth endpoints.
+// Parameters of the media type, such as a charset, are allowed.
+func isForm(r *http.Request) bool {
+	mediatype, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediatype == "application/x-www-form-urlencoded"
+}
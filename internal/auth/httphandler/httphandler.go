@@ -1,10 +1,15 @@
 package httphandler
 
 import (
+	"html/template"
 	"log/slog"
 	"net/http"
 
 	"auth/internal/auth"
+	authrepo "auth/internal/auth/repo/gorm"
+	clientrepo "auth/internal/client/repo/gorm"
+	"auth/internal/mail"
+	rolerepo "auth/internal/role/repo/gorm"
 	"auth/internal/user"
 	userrepo "auth/internal/user/repo/gorm"
 
@@ -14,7 +19,6 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 	"gorm.io/gorm"
 )
 
@@ -27,15 +31,21 @@ type AuthServer struct {
 	mux                    *chi.Mux
 	prefix                 string
 	service                *auth.Service
-	auth                   *jwtauth.JWTAuth
+	keyring                *auth.Keyring
 	jwtConfig              *auth.JWTConfig
+	authorizeConfig        *auth.AuthorizeConfig
+	pages                  *template.Template
 	db                     user.Repoer
+	repo                   auth.Repoer
 	inputValidator         *validator.Validate
 	logger                 *slog.Logger
 	tracer                 trace.Tracer
 	meter                  metric.Meter
 	usersCreatedCounter    metric.Int64Counter
+	usersVerifiedCounter   metric.Int64Counter
 	tokensGeneratedCounter metric.Int64Counter
+	loginFailuresCounter   metric.Int64Counter
+	mfaEnabledCounter      metric.Int64Counter
 }
 
 func (s *AuthServer) Prefix() string {
@@ -51,8 +61,10 @@ func (s *AuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func NewServer(
-	jwtauth *jwtauth.JWTAuth,
-	jwtconfig *auth.JWTConfig,
+	keyring *auth.Keyring,
+	config *auth.Config,
+	mailer mail.Mailer,
+	templates *mail.Templates,
 	db *gorm.DB,
 	validtr *validator.Validate,
 	logger *slog.Logger,
@@ -60,18 +72,47 @@ func NewServer(
 	meter metric.Meter,
 ) (composer.Server, error) {
 	s := &AuthServer{
-		entity:         "users",
-		prefix:         "/auth",
-		mux:            chi.NewRouter(),
-		auth:           jwtauth,
-		jwtConfig:      jwtconfig,
-		db:             userrepo.NewRepo(db, logger),
-		inputValidator: validtr,
-		logger:         logger,
-		tracer:         tracer,
-		meter:          meter,
+		entity:          "users",
+		prefix:          "/auth",
+		mux:             chi.NewRouter(),
+		keyring:         keyring,
+		jwtConfig:       config.JWT,
+		authorizeConfig: config.Authorize,
+		db:              userrepo.NewRepo(db, logger),
+		repo:            authrepo.NewRepo(db, logger),
+		inputValidator:  validtr,
+		logger:          logger,
+		tracer:          tracer,
+		meter:           meter,
 	}
-	s.service = &auth.Service{JWTConfig: jwtconfig, UserRepo: s.db}
+	s.service = &auth.Service{
+		JWTConfig:      config.JWT,
+		RefreshConfig:  config.Refresh,
+		Verification:   config.Verification,
+		Reset:          config.Reset,
+		Lockout:        config.Lockout,
+		MFA:            config.MFA,
+		WebAuthn:       config.WebAuthn,
+		Authorize:      config.Authorize,
+		Device:         config.Device,
+		Deletion:       config.Deletion,
+		Admin:          config.Admin,
+		PasswordPolicy: config.PasswordPolicy,
+		Mailer:         mailer,
+		Templates:      templates,
+		Keyring:        keyring,
+		Claims:         config.Claims,
+		UserRepo:       s.db,
+		ClientRepo:     clientrepo.NewRepo(db, logger),
+		RoleRepo:       rolerepo.NewRepo(db, logger),
+		Repo:           s.repo,
+	}
+
+	pages, err := parsePages()
+	if err != nil {
+		return s, err
+	}
+	s.pages = pages
 
 	if err := s.instrument(); err != nil {
 		return s, err
@@ -90,6 +131,14 @@ func (s *AuthServer) instrument() error {
 	}
 	s.usersCreatedCounter = usersCreatedCounter
 
+	usersVerifiedCounter, err := s.meter.Int64Counter("users_verified",
+		metric.WithDescription("How many users has verified their email address."),
+	)
+	if err != nil {
+		return err
+	}
+	s.usersVerifiedCounter = usersVerifiedCounter
+
 	tokensGeneratedCounter, err := s.meter.Int64Counter("tokens_generated",
 		metric.WithDescription("How many tokens was generated after exchange flow."),
 	)
@@ -98,5 +147,21 @@ func (s *AuthServer) instrument() error {
 	}
 	s.tokensGeneratedCounter = tokensGeneratedCounter
 
+	loginFailuresCounter, err := s.meter.Int64Counter("login_failures",
+		metric.WithDescription("How many password grant logins failed, by reason."),
+	)
+	if err != nil {
+		return err
+	}
+	s.loginFailuresCounter = loginFailuresCounter
+
+	mfaEnabledCounter, err := s.meter.Int64Counter("mfa_enabled",
+		metric.WithDescription("How many users has enabled two-factor authentication."),
+	)
+	if err != nil {
+		return err
+	}
+	s.mfaEnabledCounter = mfaEnabledCounter
+
 	return nil
 }
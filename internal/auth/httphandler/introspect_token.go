@@ -0,0 +1,142 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationIntrospectToken = "introspect_token"
+	FileIntrospectToken      = OperationIntrospectToken + ".go"
+)
+
+func (s *AuthServer) handleIntrospectToken() http.HandlerFunc {
+	const self = "handleIntrospectToken"
+
+	type request struct {
+		ClientID      string
+		ClientSecret  string
+		Token         string
+		TokenTypeHint string
+	}
+
+	type response struct {
+		Active    bool     `json:"active"`
+		Scope     string   `json:"scope,omitempty"`
+		ClientID  string   `json:"client_id,omitempty"`
+		TokenType string   `json:"token_type,omitempty"`
+		Exp       int64    `json:"exp,omitempty"`
+		Iat       int64    `json:"iat,omitempty"`
+		Sub       string   `json:"sub,omitempty"`
+		Aud       []string `json:"aud,omitempty"`
+		Iss       string   `json:"iss,omitempty"`
+		Jti       string   `json:"jti,omitempty"`
+	}
+
+	decodeForm := func(r *http.Request) (request, error) {
+		// Content-Type must be "application/x-www-form-urlencoded"
+		if !isForm(r) {
+			return request{}, fmt.Errorf("Content-Type must be application/x-www-form-urlencoded")
+		}
+
+		token := r.FormValue("token")
+		if token == "" {
+			return request{}, fmt.Errorf("token must not be empty")
+		}
+
+		// Missing credentials are reported by the client authentication, as 401 Unauthorized
+		var clientID, clientSecret string
+		if _, _, ok := r.BasicAuth(); ok || r.PostFormValue("client_id") != "" {
+			var err error
+			clientID, clientSecret, err = clientCredentials(r)
+			if err != nil {
+				return request{}, err
+			}
+		}
+
+		return request{
+			ClientID:      clientID,
+			ClientSecret:  clientSecret,
+			Token:         token,
+			TokenTypeHint: r.FormValue("token_type_hint"),
+		}, nil
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := decodeForm(r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationIntrospectToken))
+			span.RecordError(err)
+			respondOAuthError(w, r, http.StatusBadRequest, errInvalidRequest, err.Error())
+			return
+		}
+
+		err = s.service.AuthenticateClient(ctx, auth.AuthenticateClientRequest{
+			ClientID:     req.ClientID,
+			ClientSecret: req.ClientSecret,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationIntrospectToken))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInvalidClient:
+				respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+			case auth.ErrUnauthorizedClient:
+				respondOAuthError(w, r, http.StatusForbidden, errUnauthorizedClient, "Client is not allowed to introspect tokens.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileIntrospectToken, self, "failed to authenticate client", err))
+				respondOAuthServerError(w, r)
+			}
+			return
+		}
+
+		introspectTokenResponse, err := s.service.IntrospectToken(ctx, auth.IntrospectTokenRequest{
+			Token:         req.Token,
+			TokenTypeHint: req.TokenTypeHint,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationIntrospectToken))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileIntrospectToken, self, "failed to introspect token", err))
+			respondOAuthServerError(w, r)
+			return
+		}
+
+		resp := response{Active: introspectTokenResponse.Active}
+		if resp.Active {
+			resp.Scope = introspectTokenResponse.Scope
+			resp.ClientID = introspectTokenResponse.ClientID
+			resp.TokenType = introspectTokenResponse.TokenType
+			resp.Exp = introspectTokenResponse.ExpiresAt.Unix()
+			resp.Iat = introspectTokenResponse.IssuedAt.Unix()
+			resp.Sub = introspectTokenResponse.Subject
+			resp.Aud = introspectTokenResponse.Audience
+			resp.Iss = introspectTokenResponse.Issuer
+			resp.Jti = introspectTokenResponse.JTI
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationIntrospectToken))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileIntrospectToken, self, "failed to encode response", err))
+			respondOAuthServerError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationIntrospectToken)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,52 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationListPublicKeys = "list_public_keys"
+	FileListPublicKeys      = OperationListPublicKeys + ".go"
+)
+
+func (s *AuthServer) handleListPublicKeys() http.HandlerFunc {
+	const self = "handleListPublicKeys"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		listPublicKeysResponse, err := s.service.ListPublicKeys(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListPublicKeys))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListPublicKeys, self, "failed to list public keys", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		// Resource servers cache the set, and refetch it when they see an unknown "kid"
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", s.jwtConfig.RotationCheck))
+
+		// The JWK Set format (RFC 7517) is not wrapped in a data field
+		if err := responder.Respond(w, r, http.StatusOK, listPublicKeysResponse.Set); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListPublicKeys))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListPublicKeys, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationListPublicKeys)
+	return otelhandler.ServeHTTP
+}
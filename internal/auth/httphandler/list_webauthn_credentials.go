@@ -0,0 +1,91 @@
+package httphandler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationListWebAuthnCredentials = "list_webauthn_credentials"
+	FileListWebAuthnCredentials      = OperationListWebAuthnCredentials + ".go"
+)
+
+type webAuthnCredentialResponse struct {
+	// Base64url encoded, as in the PublicKeyCredential of the authenticator
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func newWebAuthnCredentialResponse(c *auth.WebAuthnCredential) webAuthnCredentialResponse {
+	return webAuthnCredentialResponse{
+		ID:             base64.RawURLEncoding.EncodeToString(c.ID),
+		Name:           c.Name,
+		Transports:     c.Transports,
+		BackupEligible: c.BackupEligible,
+		BackupState:    c.BackupState,
+		LastUsedAt:     c.LastUsedAt,
+		CreatedAt:      c.CreatedAt,
+	}
+}
+
+func (s *AuthServer) handleListWebAuthnCredentials() http.HandlerFunc {
+	const self = "handleListWebAuthnCredentials"
+
+	type response struct {
+		Credentials []webAuthnCredentialResponse `json:"credentials"`
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListWebAuthnCredentials))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		listResponse, err := s.service.ListWebAuthnCredentials(ctx, auth.ListWebAuthnCredentialsRequest{UserID: sub})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListWebAuthnCredentials))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListWebAuthnCredentials, self, "failed to list webauthn credentials", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		resp := response{Credentials: make([]webAuthnCredentialResponse, 0, len(listResponse.Credentials))}
+		for _, c := range listResponse.Credentials {
+			resp.Credentials = append(resp.Credentials, newWebAuthnCredentialResponse(c))
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationListWebAuthnCredentials))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileListWebAuthnCredentials, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationListWebAuthnCredentials)
+	return otelhandler.ServeHTTP
+}
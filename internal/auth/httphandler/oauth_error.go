@@ -0,0 +1,44 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/jkitajima/responder"
+)
+
+// Error codes of the OAuth endpoints (RFC 6749 section 5.2 and RFC 8628 section 3.5)
+const (
+	errInvalidRequest       = "invalid_request"
+	errInvalidClient        = "invalid_client"
+	errInvalidGrant         = "invalid_grant"
+	errUnauthorizedClient   = "unauthorized_client"
+	errUnsupportedGrantType = "unsupported_grant_type"
+	errInvalidScope         = "invalid_scope"
+	errServerError          = "server_error"
+	errAuthorizationPending = "authorization_pending"
+	errSlowDown             = "slow_down"
+	errAccessDenied         = "access_denied"
+	errExpiredToken         = "expired_token"
+)
+
+type oauthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// respondOAuthError answers a request to an OAuth endpoint with the error shape of RFC 6749 section 5.2,
+// understood by standard OAuth client libraries. Failed client authentications get the
+// authentication scheme of the endpoint, as required along with 401 Unauthorized.
+func respondOAuthError(w http.ResponseWriter, r *http.Request, status int, code, description string) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="auth"`)
+	}
+	responder.Respond(w, r, status, oauthError{code, description})
+}
+
+// respondOAuthServerError hides the cause of unexpected errors, as RespondInternalError does.
+func respondOAuthServerError(w http.ResponseWriter, r *http.Request) {
+	respondOAuthError(w, r, http.StatusInternalServerError, errServerError, "The server encountered an unexpected condition.")
+}
@@ -0,0 +1,128 @@
+package httphandler
+
+import (
+	"crypto/subtle"
+	"embed"
+	"html/template"
+	"net/http"
+
+	"auth/pkg/otel"
+	"auth/pkg/secret"
+)
+
+const FilePages = "pages.go"
+
+//go:embed pages
+var pagesFS embed.FS
+
+// Cookies of the login page
+const (
+	cookieSession = "auth_session"
+	cookieCSRF    = "auth_csrf"
+)
+
+type pageParam struct {
+	Name  string
+	Value string
+}
+
+type loginPage struct {
+	ClientName string
+	Error      string
+	CSRFToken  string
+	// Parameters of the authorization request, posted back along with the credentials
+	Params []pageParam
+	// Set once the password has been checked and a second factor is required
+	MFAToken string
+	Username string
+}
+
+// devicePage asks for a user code, then for the approval of the device it belongs to.
+type devicePage struct {
+	Error     string
+	CSRFToken string
+	UserCode  string
+	// Set once the user code is found
+	ClientName string
+	Scope      []string
+	// Set once the user has decided
+	Done string
+}
+
+type errorPage struct {
+	Error string
+}
+
+func parsePages() (*template.Template, error) {
+	return template.ParseFS(pagesFS, "pages/*.html.tmpl")
+}
+
+// renderPage writes a page that is never cached nor framed.
+func (s *AuthServer) renderPage(w http.ResponseWriter, r *http.Request, status int, name string, data any) {
+	const self = "renderPage"
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	w.WriteHeader(status)
+
+	if err := s.pages.ExecuteTemplate(w, name, data); err != nil {
+		s.logger.ErrorContext(r.Context(), otel.FormatLog(Path, FilePages, self, "failed to render page", err))
+	}
+}
+
+func (s *AuthServer) renderError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	s.renderPage(w, r, status, "error.html.tmpl", errorPage{Error: message})
+}
+
+// cookieName prefixes cookies with "__Host-" when they are secure,
+// which binds them to the exact host that set them.
+func (s *AuthServer) cookieName(name string) string {
+	if s.authorizeConfig.Secure {
+		return "__Host-" + name
+	}
+	return name
+}
+
+func (s *AuthServer) setCookie(w http.ResponseWriter, name, value string, maxAge int, sameSite http.SameSite) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.cookieName(name),
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   s.authorizeConfig.Secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+}
+
+func (s *AuthServer) cookie(r *http.Request, name string) string {
+	c, err := r.Cookie(s.cookieName(name))
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// csrfToken returns the token of the double-submit cookie, setting a new one if needed.
+// The cookie is strictly same-site, so a cross-site form cannot send it along with the token.
+func (s *AuthServer) csrfToken(w http.ResponseWriter, r *http.Request) (string, error) {
+	if token := s.cookie(r, cookieCSRF); token != "" {
+		return token, nil
+	}
+
+	token, err := secret.Generate(32)
+	if err != nil {
+		return "", err
+	}
+	s.setCookie(w, cookieCSRF, token, 0, http.SameSiteStrictMode)
+	return token, nil
+}
+
+// checkCSRF reports whether the token posted by the form matches the one of the cookie.
+func (s *AuthServer) checkCSRF(r *http.Request) bool {
+	cookie := s.cookie(r, cookieCSRF)
+	form := r.PostFormValue("csrf_token")
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(form)) == 1
+}
@@ -0,0 +1,100 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationRegenerateRecoveryCodes = "regenerate_recovery_codes"
+	FileRegenerateRecoveryCodes      = OperationRegenerateRecoveryCodes + ".go"
+)
+
+func (s *AuthServer) handleRegenerateRecoveryCodes() http.HandlerFunc {
+	const self = "handleRegenerateRecoveryCodes"
+
+	type request struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	type response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	contract := map[string]responder.Field{
+		"Code": {
+			Name:       "code",
+			Validation: "Field value cannot be an empty string.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegenerateRecoveryCodes))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegenerateRecoveryCodes))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegenerateRecoveryCodes))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		regenerateRecoveryCodesResponse, err := s.service.RegenerateRecoveryCodes(ctx, auth.RegenerateRecoveryCodesRequest{
+			UserID: sub,
+			Code:   req.Code,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegenerateRecoveryCodes))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInvalidMFACode:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid one-time password or recovery code.")
+			case auth.ErrTOTPNotEnabled:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "Two-factor authentication is not enabled.")
+			case auth.ErrTooManyTOTPAttempts:
+				responder.RespondMetaMessage(w, r, http.StatusTooManyRequests, "Too many failed attempts. Try again later.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRegenerateRecoveryCodes, self, "failed to regenerate recovery codes", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, response{regenerateRecoveryCodesResponse.RecoveryCodes}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegenerateRecoveryCodes))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRegenerateRecoveryCodes, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationRegenerateRecoveryCodes)
+	return otelhandler.ServeHTTP
+}
@@ -1,6 +1,7 @@
 package httphandler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"auth/internal/auth"
 	"auth/internal/user"
 	"auth/pkg/otel"
+	"auth/pkg/password"
 
 	"github.com/jkitajima/responder"
 
@@ -31,11 +33,12 @@ func (s *AuthServer) handleUserRegister() http.HandlerFunc {
 	}
 
 	type response struct {
-		Entity    string    `json:"entity"`
-		ID        uuid.UUID `json:"id"`
-		Email     string    `json:"email"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+		Entity        string    `json:"entity"`
+		ID            uuid.UUID `json:"id"`
+		Email         string    `json:"email"`
+		EmailVerified bool      `json:"email_verified"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
 	}
 
 	contract := map[string]responder.Field{
@@ -61,10 +64,10 @@ func (s *AuthServer) handleUserRegister() http.HandlerFunc {
 			return
 		}
 
-		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+		if errs := responder.ValidateInput(s.inputValidator, req, contract); len(errs) > 0 {
 			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegisterUser))
 			span.RecordError(err)
-			responder.RespondClientErrors(w, r, errors...)
+			responder.RespondClientErrors(w, r, errs...)
 			return
 		}
 
@@ -72,9 +75,18 @@ func (s *AuthServer) handleUserRegister() http.HandlerFunc {
 			Email:    req.Email,
 			Password: req.Password,
 		})
+		if errors.Is(err, auth.ErrVerificationNotSent) {
+			// The user was created and can request a new code later
+			s.logger.WarnContext(ctx, otel.FormatLog(Path, FileRegisterUser, self, "failed to send verification email", err))
+			err = nil
+		}
 		if err != nil {
 			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRegisterUser))
 			span.RecordError(err)
+			if errs, ok := password.ErrorObjects(err, "password"); ok {
+				responder.RespondClientErrors(w, r, errs...)
+				return
+			}
 			switch err {
 			case user.ErrEmailAlreadyInUse:
 				responder.RespondMetaMessage(w, r, http.StatusConflict, "There is already an user with provided email.")
@@ -89,11 +101,12 @@ func (s *AuthServer) handleUserRegister() http.HandlerFunc {
 		s.usersCreatedCounter.Add(ctx, 1)
 
 		resp := response{
-			Entity:    s.entity,
-			ID:        registerResponse.User.ID,
-			Email:     registerResponse.User.Email,
-			CreatedAt: registerResponse.User.CreatedAt,
-			UpdatedAt: registerResponse.User.UpdatedAt,
+			Entity:        s.entity,
+			ID:            registerResponse.User.ID,
+			Email:         registerResponse.User.Email,
+			EmailVerified: registerResponse.User.EmailVerified,
+			CreatedAt:     registerResponse.User.CreatedAt,
+			UpdatedAt:     registerResponse.User.UpdatedAt,
 		}
 
 		if err := responder.Respond(w, r, http.StatusCreated, &responder.DataField{Data: resp}); err != nil {
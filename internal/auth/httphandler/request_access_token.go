@@ -1,17 +1,27 @@
 package httphandler
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"auth/internal/auth"
+	"auth/internal/client"
 	"auth/internal/user"
 	"auth/pkg/otel"
 
 	"github.com/jkitajima/responder"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -20,50 +30,179 @@ const (
 	FileRequestAccessToken      = OperationRequestAccessToken + ".go"
 )
 
+// GrantMFAOTP exchanges the mfa token returned by the password grant, along with
+// a one-time password or a recovery code, for the actual tokens.
+const GrantMFAOTP = "mfa_otp"
+
+// grantTypes lists the grant types accepted by the token endpoint, as advertised by the discovery document.
+var grantTypes = []string{"password", "refresh_token", GrantMFAOTP, "client_credentials", "authorization_code", client.GrantDeviceCode}
+
+var errUnsupportedGrant = fmt.Errorf("grant_type must be one of %s", strings.Join(grantTypes, ", "))
+
 func (s *AuthServer) handleRequestAccessToken() http.HandlerFunc {
 	const self = "handleRequestAccessToken"
 
 	type request struct {
-		GrantType string
-		Username  string
-		Password  string
+		GrantType    string
+		Username     string
+		Password     string
+		RefreshToken string
+		MFAToken     string
+		OTP          string
+		ClientID     string
+		ClientSecret string
+		Scope        []string
+		Code         string
+		RedirectURI  string
+		CodeVerifier string
+		DeviceCode   string
 	}
 
 	type response struct {
-		AccessToken string `json:"access_token"`
-		// RefreshToken string `json:"refresh_token"`
-		TokenType string `json:"token_type"`
-		ExpiresIn int    `json:"expires_in"`
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+		Scope        string `json:"scope,omitempty"`
+		IDToken      string `json:"id_token,omitempty"`
+	}
+
+	type mfaRequiredResponse struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		MFAToken         string `json:"mfa_token"`
+		ExpiresIn        int    `json:"expires_in"`
 	}
 
 	decodeForm := func(r *http.Request) (request, error) {
 		// Content-Type must be "application/x-www-form-urlencoded"
-		if ctype := r.Header.Get("Content-Type"); ctype != "application/x-www-form-urlencoded" {
+		if !isForm(r) {
 			return request{}, fmt.Errorf("Content-Type must be application/x-www-form-urlencoded")
 		}
 
 		// Now that we know that the Content-Type is correct,
 		// we validate the form values
 		grantType := r.FormValue("grant_type")
-		if grantType != "password" {
-			return request{}, fmt.Errorf("grant_type must be password")
-		}
+		switch grantType {
+		case "password":
+			username := r.FormValue("username")
+			if username == "" {
+				return request{}, fmt.Errorf("username must not be empty")
+			}
 
-		username := r.FormValue("username")
-		if username == "" {
-			return request{}, fmt.Errorf("username must not be empty")
-		}
+			password := r.FormValue("password")
+			if password == "" {
+				return request{}, fmt.Errorf("password must not be empty")
+			}
 
-		password := r.FormValue("password")
-		if password == "" {
-			return request{}, fmt.Errorf("password must not be empty")
-		}
+			return request{
+				GrantType: grantType,
+				Username:  username,
+				Password:  password,
+				Scope:     strings.Fields(r.FormValue("scope")),
+			}, nil
+		case "refresh_token":
+			refreshToken := r.FormValue("refresh_token")
+			if refreshToken == "" {
+				return request{}, fmt.Errorf("refresh_token must not be empty")
+			}
+
+			// Only tokens of the authorization code grant are refreshed by a client
+			var clientID, clientSecret string
+			if _, _, ok := r.BasicAuth(); ok || r.PostFormValue("client_id") != "" {
+				var err error
+				clientID, clientSecret, err = clientCredentials(r)
+				if err != nil {
+					return request{}, err
+				}
+			}
+
+			return request{
+				GrantType:    grantType,
+				RefreshToken: refreshToken,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+				Scope:        strings.Fields(r.FormValue("scope")),
+			}, nil
+		case GrantMFAOTP:
+			mfaToken := r.FormValue("mfa_token")
+			if mfaToken == "" {
+				return request{}, fmt.Errorf("mfa_token must not be empty")
+			}
+
+			otp := r.FormValue("otp")
+			if otp == "" {
+				return request{}, fmt.Errorf("otp must not be empty")
+			}
+
+			return request{
+				GrantType: grantType,
+				MFAToken:  mfaToken,
+				OTP:       otp,
+			}, nil
+		case "client_credentials":
+			clientID, clientSecret, err := clientCredentials(r)
+			if err != nil {
+				return request{}, err
+			}
 
-		return request{
-			GrantType: grantType,
-			Username:  username,
-			Password:  password,
-		}, nil
+			return request{
+				GrantType:    grantType,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+				Scope:        strings.Fields(r.FormValue("scope")),
+			}, nil
+		case "authorization_code":
+			code := r.FormValue("code")
+			if code == "" {
+				return request{}, fmt.Errorf("code must not be empty")
+			}
+
+			redirectURI := r.FormValue("redirect_uri")
+			if redirectURI == "" {
+				return request{}, fmt.Errorf("redirect_uri must not be empty")
+			}
+
+			codeVerifier := r.FormValue("code_verifier")
+			if codeVerifier == "" {
+				return request{}, fmt.Errorf("code_verifier must not be empty")
+			}
+
+			clientID, clientSecret, err := clientCredentials(r)
+			if err != nil {
+				return request{}, err
+			}
+
+			return request{
+				GrantType:    grantType,
+				Code:         code,
+				RedirectURI:  redirectURI,
+				CodeVerifier: codeVerifier,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+			}, nil
+		case client.GrantDeviceCode:
+			deviceCode := r.FormValue("device_code")
+			if deviceCode == "" {
+				return request{}, fmt.Errorf("device_code must not be empty")
+			}
+
+			clientID, clientSecret, err := clientCredentials(r)
+			if err != nil {
+				return request{}, err
+			}
+
+			return request{
+				GrantType:    grantType,
+				DeviceCode:   deviceCode,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+			}, nil
+		case "":
+			return request{}, fmt.Errorf("grant_type must not be empty")
+		default:
+			return request{}, errUnsupportedGrant
+		}
 	}
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
@@ -74,45 +213,284 @@ func (s *AuthServer) handleRequestAccessToken() http.HandlerFunc {
 		if err != nil {
 			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
 			span.RecordError(err)
-			responder.RespondMetaMessage(w, r, http.StatusBadRequest, err.Error())
+			if err == errUnsupportedGrant {
+				respondOAuthError(w, r, http.StatusBadRequest, errUnsupportedGrantType, err.Error())
+				return
+			}
+			respondOAuthError(w, r, http.StatusBadRequest, errInvalidRequest, err.Error())
 			return
 		}
 
-		requestAcessTokenResponse, err := s.service.RequestAccessToken(ctx, auth.AccessTokenRequest{
-			Username: req.Username,
-			Password: req.Password,
-		})
-		if err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
-			span.RecordError(err)
-			switch err {
-			case user.ErrNotFoundByEmail:
-				fallthrough
-			case auth.ErrInvalidCredentials:
-				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid credentials.")
-			case user.ErrInternal:
-				fallthrough
-			case auth.ErrInternal:
-				fallthrough
-			default:
-				responder.RespondInternalError(w, r)
+		var resp response
+		switch req.GrantType {
+		case "password":
+			requestAcessTokenResponse, err := s.service.RequestAccessToken(ctx, auth.AccessTokenRequest{
+				Username: req.Username,
+				Password: req.Password,
+				IP:       clientIP(r),
+				Scope:    req.Scope,
+			})
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
+				span.RecordError(err)
+
+				// The password was right, but a second factor is needed
+				var mfaRequired *auth.MFARequiredError
+				if errors.As(err, &mfaRequired) {
+					w.Header().Set("Cache-Control", "no-store")
+					w.Header().Set("Pragma", "no-cache")
+					responder.Respond(w, r, http.StatusForbidden, mfaRequiredResponse{
+						Error:            "mfa_required",
+						ErrorDescription: "Multi-factor authentication is required.",
+						MFAToken:         mfaRequired.MFAToken,
+						ExpiresIn:        mfaRequired.ExpiresIn,
+					})
+					return
+				}
+
+				var locked *auth.LoginLockedError
+				if errors.As(err, &locked) {
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "locked")))
+					retryAfter := math.Ceil(time.Until(locked.Until).Seconds())
+					w.Header().Set("Retry-After", strconv.Itoa(max(int(retryAfter), 1)))
+					respondOAuthError(w, r, http.StatusTooManyRequests, errInvalidGrant, "Too many failed login attempts. Try again later.")
+					return
+				}
+
+				switch err {
+				case user.ErrNotFoundByEmail:
+					fallthrough
+				case auth.ErrInvalidCredentials:
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_credentials")))
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Invalid credentials.")
+				case auth.ErrEmailNotVerified:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Email address has not been verified.")
+				case auth.ErrUserDisabled:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "User has been disabled.")
+				case auth.ErrInvalidScope:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidScope, "Requested scope exceeds the scopes that users may be granted.")
+				case user.ErrInternal:
+					fallthrough
+				case auth.ErrInternal:
+					fallthrough
+				default:
+					respondOAuthServerError(w, r)
+				}
+				return
 			}
-			return
-		}
 
-		s.tokensGeneratedCounter.Add(ctx, 1)
+			resp = response{
+				AccessToken:  string(requestAcessTokenResponse.AccessToken),
+				RefreshToken: requestAcessTokenResponse.RefreshToken,
+				TokenType:    requestAcessTokenResponse.TokenType,
+				ExpiresIn:    requestAcessTokenResponse.ExpiresIn,
+				Scope:        strings.Join(requestAcessTokenResponse.Scope, " "),
+			}
+		case "refresh_token":
+			refreshAccessTokenResponse, err := s.service.RefreshAccessToken(ctx, auth.RefreshAccessTokenRequest{
+				RefreshToken: req.RefreshToken,
+				ClientID:     req.ClientID,
+				ClientSecret: req.ClientSecret,
+				Scope:        req.Scope,
+			})
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
+				span.RecordError(err)
+				switch err {
+				case auth.ErrRefreshTokenReused:
+					s.logger.WarnContext(ctx, otel.FormatLog(Path, FileRequestAccessToken, self, "refresh token reuse detected", err))
+					fallthrough
+				case auth.ErrInvalidRefreshToken:
+					fallthrough
+				case user.ErrNotFoundByID:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Invalid refresh token.")
+				case auth.ErrInvalidClient:
+					respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+				case auth.ErrInvalidScope:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidScope, "Requested scope exceeds the scope of the refresh token.")
+				case user.ErrInternal:
+					fallthrough
+				case auth.ErrInternal:
+					fallthrough
+				default:
+					respondOAuthServerError(w, r)
+				}
+				return
+			}
+
+			resp = response{
+				AccessToken:  string(refreshAccessTokenResponse.AccessToken),
+				RefreshToken: refreshAccessTokenResponse.RefreshToken,
+				TokenType:    refreshAccessTokenResponse.TokenType,
+				ExpiresIn:    refreshAccessTokenResponse.ExpiresIn,
+				Scope:        strings.Join(refreshAccessTokenResponse.Scope, " "),
+			}
+		case GrantMFAOTP:
+			verifyMFAResponse, err := s.service.VerifyMFA(ctx, auth.VerifyMFARequest{
+				MFAToken: req.MFAToken,
+				Code:     req.OTP,
+			})
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
+				span.RecordError(err)
+				switch err {
+				case auth.ErrInvalidMFACode:
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_otp")))
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Invalid one-time password.")
+				case auth.ErrTooManyMFAAttempts:
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_otp")))
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Too many failed one-time passwords. Log in again.")
+				case auth.ErrUserDisabled:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "User has been disabled.")
+				case auth.ErrInvalidMFAToken:
+					fallthrough
+				case auth.ErrTOTPNotEnabled:
+					fallthrough
+				case user.ErrNotFoundByID:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Invalid mfa token.")
+				case user.ErrInternal:
+					fallthrough
+				case auth.ErrInternal:
+					fallthrough
+				default:
+					respondOAuthServerError(w, r)
+				}
+				return
+			}
+
+			resp = response{
+				AccessToken:  string(verifyMFAResponse.AccessToken),
+				RefreshToken: verifyMFAResponse.RefreshToken,
+				TokenType:    verifyMFAResponse.TokenType,
+				ExpiresIn:    verifyMFAResponse.ExpiresIn,
+				Scope:        strings.Join(verifyMFAResponse.Scope, " "),
+			}
+		case "authorization_code":
+			exchangeResponse, err := s.service.ExchangeAuthorizationCode(ctx, auth.ExchangeAuthorizationCodeRequest{
+				Code:         req.Code,
+				RedirectURI:  req.RedirectURI,
+				CodeVerifier: req.CodeVerifier,
+				ClientID:     req.ClientID,
+				ClientSecret: req.ClientSecret,
+			})
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
+				span.RecordError(err)
+				switch err {
+				case auth.ErrInvalidClient:
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_client")))
+					respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+				case auth.ErrUnauthorizedClient:
+					respondOAuthError(w, r, http.StatusBadRequest, errUnauthorizedClient, "Client is not allowed to use the authorization_code grant.")
+				case auth.ErrAuthorizationCodeReused:
+					s.logger.WarnContext(ctx, otel.FormatLog(Path, FileRequestAccessToken, self, "authorization code reuse detected", err))
+					fallthrough
+				case auth.ErrInvalidAuthorizationCode, auth.ErrInvalidCodeVerifier, user.ErrNotFoundByID:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Invalid authorization code.")
+				case auth.ErrUserDisabled:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "User has been disabled.")
+				default:
+					respondOAuthServerError(w, r)
+				}
+				return
+			}
+
+			resp = response{
+				AccessToken:  string(exchangeResponse.AccessToken),
+				RefreshToken: exchangeResponse.RefreshToken,
+				TokenType:    exchangeResponse.TokenType,
+				ExpiresIn:    exchangeResponse.ExpiresIn,
+				Scope:        strings.Join(exchangeResponse.Scope, " "),
+				IDToken:      exchangeResponse.IDToken,
+			}
+		case client.GrantDeviceCode:
+			exchangeResponse, err := s.service.ExchangeDeviceCode(ctx, auth.ExchangeDeviceCodeRequest{
+				DeviceCode:   req.DeviceCode,
+				ClientID:     req.ClientID,
+				ClientSecret: req.ClientSecret,
+			})
+			if err != nil {
+				span.RecordError(err)
+				// Waiting for the user is the expected outcome of most polls, not a failure
+				switch err {
+				case auth.ErrAuthorizationPending:
+					respondOAuthError(w, r, http.StatusBadRequest, errAuthorizationPending, "The user has not yet approved the device.")
+					return
+				case auth.ErrSlowDown:
+					respondOAuthError(w, r, http.StatusBadRequest, errSlowDown, "Polling too often, the interval was increased by 5 seconds.")
+					return
+				}
+
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
+				switch err {
+				case auth.ErrAccessDenied:
+					respondOAuthError(w, r, http.StatusBadRequest, errAccessDenied, "The user denied the device.")
+				case auth.ErrDeviceCodeExpired:
+					respondOAuthError(w, r, http.StatusBadRequest, errExpiredToken, "The device code has expired. Request a new one.")
+				case auth.ErrInvalidClient:
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_client")))
+					respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+				case auth.ErrUnauthorizedClient:
+					respondOAuthError(w, r, http.StatusBadRequest, errUnauthorizedClient, "Client is not allowed to use the device_code grant.")
+				case auth.ErrInvalidDeviceCode, user.ErrNotFoundByID:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "Invalid device code.")
+				case auth.ErrUserDisabled:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidGrant, "User has been disabled.")
+				default:
+					respondOAuthServerError(w, r)
+				}
+				return
+			}
+
+			resp = response{
+				AccessToken:  string(exchangeResponse.AccessToken),
+				RefreshToken: exchangeResponse.RefreshToken,
+				TokenType:    exchangeResponse.TokenType,
+				ExpiresIn:    exchangeResponse.ExpiresIn,
+				Scope:        strings.Join(exchangeResponse.Scope, " "),
+				IDToken:      exchangeResponse.IDToken,
+			}
+		case "client_credentials":
+			clientCredentialsResponse, err := s.service.ClientCredentials(ctx, auth.ClientCredentialsRequest{
+				ClientID:     req.ClientID,
+				ClientSecret: req.ClientSecret,
+				Scope:        req.Scope,
+			})
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
+				span.RecordError(err)
+				switch err {
+				case auth.ErrInvalidClient:
+					s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_client")))
+					respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+				case auth.ErrUnauthorizedClient:
+					respondOAuthError(w, r, http.StatusBadRequest, errUnauthorizedClient, "Client is not allowed to use the client_credentials grant.")
+				case auth.ErrInvalidScope:
+					respondOAuthError(w, r, http.StatusBadRequest, errInvalidScope, "Requested scope exceeds the scopes of the client.")
+				default:
+					respondOAuthServerError(w, r)
+				}
+				return
+			}
 
-		resp := response{
-			AccessToken: string(requestAcessTokenResponse.AccessToken),
-			TokenType:   requestAcessTokenResponse.TokenType,
-			ExpiresIn:   requestAcessTokenResponse.ExpiresIn,
+			resp = response{
+				AccessToken: string(clientCredentialsResponse.AccessToken),
+				TokenType:   clientCredentialsResponse.TokenType,
+				ExpiresIn:   clientCredentialsResponse.ExpiresIn,
+				Scope:       strings.Join(clientCredentialsResponse.Scope, " "),
+			}
 		}
 
+		s.tokensGeneratedCounter.Add(ctx, 1)
+
+		// Tokens must never be cached (RFC 6749 section 5.1)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
 		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
 			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestAccessToken))
 			span.RecordError(err)
-			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRegisterUser, self, "failed to encode response", err))
-			responder.RespondInternalError(w, r)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRequestAccessToken, self, "failed to encode response", err))
+			respondOAuthServerError(w, r)
 			return
 		}
 	}
@@ -120,3 +498,42 @@ func (s *AuthServer) handleRequestAccessToken() http.HandlerFunc {
 	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationRequestAccessToken)
 	return otelhandler.ServeHTTP
 }
+
+// clientIP returns the address of the client, as resolved from trusted proxies by the server.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientCredentials returns the credentials of a client authenticating either with
+// client_secret_basic or client_secret_post, or of a public client only sending its client_id.
+// Using more than one method at once is rejected, as required by RFC 6749 section 2.3.
+func clientCredentials(r *http.Request) (string, string, error) {
+	formID, formSecret := r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+
+	basicID, basicSecret, ok := r.BasicAuth()
+	if !ok {
+		if formID == "" {
+			return "", "", fmt.Errorf("client credentials must be sent with HTTP Basic or as client_id and client_secret")
+		}
+		return formID, formSecret, nil
+	}
+
+	if formID != "" || formSecret != "" {
+		return "", "", fmt.Errorf("client credentials must not be sent with more than one method")
+	}
+
+	// Basic credentials are form-urlencoded before being base64 encoded (RFC 6749 section 2.3.1)
+	clientID, err := url.QueryUnescape(basicID)
+	if err != nil {
+		return "", "", fmt.Errorf("client_id of HTTP Basic credentials is not form-urlencoded")
+	}
+	clientSecret, err := url.QueryUnescape(basicSecret)
+	if err != nil {
+		return "", "", fmt.Errorf("client_secret of HTTP Basic credentials is not form-urlencoded")
+	}
+	return clientID, clientSecret, nil
+}
@@ -0,0 +1,125 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationRequestDeviceAuthorization = "request_device_authorization"
+	FileRequestDeviceAuthorization      = OperationRequestDeviceAuthorization + ".go"
+)
+
+// handleRequestDeviceAuthorization starts the device authorization grant (RFC 8628 section 3.1)
+// for input-constrained devices such as CLI tools. The user approves the device on the
+// verification page of another browser, while the device polls the token endpoint.
+func (s *AuthServer) handleRequestDeviceAuthorization() http.HandlerFunc {
+	const self = "handleRequestDeviceAuthorization"
+
+	type request struct {
+		ClientID     string
+		ClientSecret string
+		Scope        []string
+	}
+
+	type response struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+		Scope                   string `json:"scope,omitempty"`
+	}
+
+	decodeForm := func(r *http.Request) (request, error) {
+		// Content-Type must be "application/x-www-form-urlencoded"
+		if !isForm(r) {
+			return request{}, fmt.Errorf("Content-Type must be application/x-www-form-urlencoded")
+		}
+
+		clientID, clientSecret, err := clientCredentials(r)
+		if err != nil {
+			return request{}, err
+		}
+
+		return request{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scope:        strings.Fields(r.FormValue("scope")),
+		}, nil
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := decodeForm(r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestDeviceAuthorization))
+			span.RecordError(err)
+			respondOAuthError(w, r, http.StatusBadRequest, errInvalidRequest, err.Error())
+			return
+		}
+
+		deviceAuthorizationResponse, err := s.service.RequestDeviceAuthorization(ctx, auth.RequestDeviceAuthorizationRequest{
+			ClientID:     req.ClientID,
+			ClientSecret: req.ClientSecret,
+			Scope:        req.Scope,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestDeviceAuthorization))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInvalidClient:
+				s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_client")))
+				respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+			case auth.ErrUnauthorizedClient:
+				respondOAuthError(w, r, http.StatusBadRequest, errUnauthorizedClient, "Client is not allowed to use the device_code grant.")
+			case auth.ErrInvalidScope:
+				respondOAuthError(w, r, http.StatusBadRequest, errInvalidScope, "Requested scope exceeds the scopes of the client.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRequestDeviceAuthorization, self, "failed to request device authorization", err))
+				respondOAuthServerError(w, r)
+			}
+			return
+		}
+
+		// The user code is typed by the user, or carried by the complete URI when shown as a QR code
+		verificationURI := strings.TrimSuffix(s.jwtConfig.Issuer, "/") + routeDevice
+		resp := response{
+			DeviceCode:              deviceAuthorizationResponse.DeviceCode,
+			UserCode:                deviceAuthorizationResponse.UserCode,
+			VerificationURI:         verificationURI,
+			VerificationURIComplete: verificationURI + "?" + url.Values{"user_code": {deviceAuthorizationResponse.UserCode}}.Encode(),
+			ExpiresIn:               deviceAuthorizationResponse.ExpiresIn,
+			Interval:                deviceAuthorizationResponse.Interval,
+			Scope:                   strings.Join(deviceAuthorizationResponse.Scope, " "),
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRequestDeviceAuthorization))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRequestDeviceAuthorization, self, "failed to encode response", err))
+			respondOAuthServerError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationRequestDeviceAuthorization)
+	return otelhandler.ServeHTTP
+}
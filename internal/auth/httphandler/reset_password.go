@@ -0,0 +1,93 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+	"auth/pkg/password"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationResetPassword = "reset_password"
+	FileResetPassword      = OperationResetPassword + ".go"
+)
+
+func (s *AuthServer) handleResetPassword() http.HandlerFunc {
+	const self = "handleResetPassword"
+
+	type request struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required"`
+	}
+
+	contract := map[string]responder.Field{
+		"Token": {
+			Name:       "token",
+			Validation: "Field is required.",
+		},
+		"Password": {
+			Name:       "password",
+			Validation: "Field is required.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetPassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetPassword))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		err = s.service.ResetPassword(ctx, auth.ResetPasswordRequest{
+			Token:    req.Token,
+			Password: req.Password,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetPassword))
+			span.RecordError(err)
+			if errs, ok := password.ErrorObjects(err, "password"); ok {
+				responder.RespondClientErrors(w, r, errs...)
+				return
+			}
+			switch err {
+			case auth.ErrInvalidResetToken:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Password reset token is invalid, expired or was already used.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileResetPassword, self, "failed to reset password", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.RespondMetaMessage(w, r, http.StatusOK, "Password has been reset."); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationResetPassword))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileResetPassword, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationResetPassword)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,99 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationRevokeToken = "revoke_token"
+	FileRevokeToken      = OperationRevokeToken + ".go"
+)
+
+func (s *AuthServer) handleRevokeToken() http.HandlerFunc {
+	const self = "handleRevokeToken"
+
+	type request struct {
+		Token         string
+		TokenTypeHint string
+		ClientID      string
+		ClientSecret  string
+	}
+
+	decodeForm := func(r *http.Request) (request, error) {
+		// Content-Type must be "application/x-www-form-urlencoded"
+		if !isForm(r) {
+			return request{}, fmt.Errorf("Content-Type must be application/x-www-form-urlencoded")
+		}
+
+		token := r.FormValue("token")
+		if token == "" {
+			return request{}, fmt.Errorf("token must not be empty")
+		}
+
+		// Tokens of first-party logins are revoked without any client credentials
+		var clientID, clientSecret string
+		if _, _, ok := r.BasicAuth(); ok || r.PostFormValue("client_id") != "" {
+			var err error
+			clientID, clientSecret, err = clientCredentials(r)
+			if err != nil {
+				return request{}, err
+			}
+		}
+
+		return request{
+			Token:         token,
+			TokenTypeHint: r.FormValue("token_type_hint"),
+			ClientID:      clientID,
+			ClientSecret:  clientSecret,
+		}, nil
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := decodeForm(r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRevokeToken))
+			span.RecordError(err)
+			respondOAuthError(w, r, http.StatusBadRequest, errInvalidRequest, err.Error())
+			return
+		}
+
+		err = s.service.RevokeToken(ctx, auth.RevokeTokenRequest{
+			Token:         req.Token,
+			TokenTypeHint: req.TokenTypeHint,
+			ClientID:      req.ClientID,
+			ClientSecret:  req.ClientSecret,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRevokeToken))
+			span.RecordError(err)
+
+			switch err {
+			case auth.ErrInvalidClient:
+				respondOAuthError(w, r, http.StatusUnauthorized, errInvalidClient, "Client authentication failed.")
+			case auth.ErrTokenNotIssuedToClient:
+				respondOAuthError(w, r, http.StatusBadRequest, errUnauthorizedClient, "Token was issued to another client.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRevokeToken, self, "failed to revoke token", err))
+				respondOAuthServerError(w, r)
+			}
+			return
+		}
+
+		// RFC 7009 answers 200 OK with an empty body, whether or not the token was known
+		w.WriteHeader(http.StatusOK)
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationRevokeToken)
+	return otelhandler.ServeHTTP
+}
@@ -3,6 +3,8 @@ package httphandler
 import (
 	"net/http"
 
+	"auth/internal/auth/guard"
+	"auth/internal/role"
 	"auth/pkg/otel"
 
 	"github.com/go-chi/chi/v5"
@@ -10,14 +12,44 @@ import (
 
 func (s *AuthServer) addRoutes() {
 	// Private routes
-	// s.mux.Group(func(r chi.Router) {
-	// 	r.Use(jwtauth.Verifier(s.auth))
-	// 	r.Use(responder.RespondAuth(s.auth))
-	// })
+	s.mux.Group(func(r chi.Router) {
+		r.Use(guard.Verifier(s.keyring, s.repo, s.db))
+		r.Use(guard.Authenticator)
+
+		otel.Route(r, http.MethodPost, "/mfa/totp", s.handleEnrollTOTP())
+		otel.Route(r, http.MethodPost, "/mfa/totp/confirm", s.handleConfirmTOTP())
+		otel.Route(r, http.MethodPost, "/mfa/totp/disable", s.handleDisableTOTP())
+		otel.Route(r, http.MethodPost, "/mfa/recovery-codes", s.handleRegenerateRecoveryCodes())
+		otel.Route(r, http.MethodPost, "/webauthn/register/begin", s.handleBeginWebAuthnRegistration())
+		otel.Route(r, http.MethodPost, "/webauthn/register/finish", s.handleFinishWebAuthnRegistration())
+		otel.Route(r, http.MethodGet, "/webauthn/credentials", s.handleListWebAuthnCredentials())
+		otel.Route(r, http.MethodDelete, "/webauthn/credentials/{credentialID}", s.handleDeleteWebAuthnCredential())
+		otel.Route(r, http.MethodGet, routeUserInfo, s.handleUserInfo())
+		otel.Route(r, http.MethodPost, routeUserInfo, s.handleUserInfo())
+
+		// Operator routes
+		loginsUnlock := r.With(guard.RequirePermission(role.PermissionLoginsUnlock))
+		otel.Route(loginsUnlock, http.MethodPost, "/lockout/unlock", s.handleUnlockLogin())
+	})
 
 	// Public routes
 	s.mux.Group(func(r chi.Router) {
-		otel.Route(r, http.MethodPost, "/oauth/token", s.handleRequestAccessToken())
+		otel.Route(r, http.MethodGet, routeAuthorize, s.handleAuthorize())
+		otel.Route(r, http.MethodPost, routeAuthorize, s.handleSignIn())
+		otel.Route(r, http.MethodPost, routeToken, s.handleRequestAccessToken())
+		otel.Route(r, http.MethodPost, routeRevoke, s.handleRevokeToken())
+		otel.Route(r, http.MethodPost, routeIntrospect, s.handleIntrospectToken())
+		otel.Route(r, http.MethodPost, routeDeviceAuth, s.handleRequestDeviceAuthorization())
+		otel.Route(r, http.MethodGet, routeDevice, s.handleVerifyDevice())
+		otel.Route(r, http.MethodPost, routeDevice, s.handleDecideDevice())
 		otel.Route(r, http.MethodPost, "/register", s.handleUserRegister())
+		otel.Route(r, http.MethodPost, "/verify-email", s.handleVerifyEmail())
+		otel.Route(r, http.MethodPost, "/verify-email/resend", s.handleResendVerification())
+		otel.Route(r, http.MethodPost, "/password/forgot", s.handleForgotPassword())
+		otel.Route(r, http.MethodPost, "/password/reset", s.handleResetPassword())
+		otel.Route(r, http.MethodPost, "/webauthn/login/begin", s.handleBeginWebAuthnLogin())
+		otel.Route(r, http.MethodPost, "/webauthn/login/finish", s.handleFinishWebAuthnLogin())
+		otel.Route(r, http.MethodGet, routeJWKS, s.handleListPublicKeys())
+		otel.Route(r, http.MethodGet, routeConfiguration, s.handleDiscoverConfiguration())
 	})
 }
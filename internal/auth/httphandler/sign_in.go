@@ -0,0 +1,145 @@
+package httphandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationSignIn = "sign_in"
+	FileSignIn      = OperationSignIn + ".go"
+)
+
+// handleSignIn checks the credentials posted by the login page of the authorization endpoint,
+// then sends the user back to the client with an authorization code.
+func (s *AuthServer) handleSignIn() http.HandlerFunc {
+	const self = "handleSignIn"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		validated, ok := s.validateAuthorization(w, r)
+		if !ok {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSignIn))
+			return
+		}
+
+		session, ok := s.signIn(w, r, loginPage{
+			ClientName: validated.Client.Name,
+			Params:     pageParams(r),
+		})
+		if !ok {
+			return
+		}
+		s.redirectWithCode(w, r, validated, session)
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationSignIn)
+	return otelhandler.ServeHTTP
+}
+
+// signIn checks the credentials posted by a login page and starts a session, kept in a cookie.
+// Failures render the login page again, along with the error or asking for a second factor.
+// It reports whether the user is signed in.
+func (s *AuthServer) signIn(w http.ResponseWriter, r *http.Request, page loginPage) (*auth.Session, bool) {
+	const self = "signIn"
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	page.Username = r.PostFormValue("username")
+	fail := func(status int, message string) {
+		if status >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSignIn))
+		}
+		csrfToken, err := s.csrfToken(w, r)
+		if err != nil {
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileSignIn, self, "failed to generate csrf token", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+			return
+		}
+		page.CSRFToken = csrfToken
+		page.Error = message
+		s.renderPage(w, r, status, "login.html.tmpl", page)
+	}
+
+	if !s.checkCSRF(r) {
+		fail(http.StatusForbidden, "Your sign in form has expired. Try again.")
+		return nil, false
+	}
+
+	var (
+		signInResponse auth.SignInResponse
+		err            error
+	)
+	if mfaToken := r.PostFormValue("mfa_token"); mfaToken != "" {
+		signInResponse, err = s.service.SignInMFA(ctx, auth.SignInMFARequest{
+			MFAToken: mfaToken,
+			Code:     r.PostFormValue("otp"),
+		})
+	} else {
+		signInResponse, err = s.service.SignIn(ctx, auth.SignInRequest{
+			Username: r.PostFormValue("username"),
+			Password: r.PostFormValue("password"),
+			IP:       clientIP(r),
+		})
+	}
+	if err != nil {
+		span.RecordError(err)
+
+		// The password was right, but a second factor is needed
+		var mfaRequired *auth.MFARequiredError
+		if errors.As(err, &mfaRequired) {
+			// Not a failure, the same page asks for the second factor
+			page.MFAToken = mfaRequired.MFAToken
+			fail(http.StatusOK, "")
+			return nil, false
+		}
+
+		if errors.Is(err, auth.ErrLoginLocked) {
+			s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "locked")))
+			fail(http.StatusTooManyRequests, "Too many failed sign in attempts. Try again later.")
+			return nil, false
+		}
+
+		switch err {
+		case user.ErrNotFoundByEmail, auth.ErrInvalidCredentials:
+			s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_credentials")))
+			fail(http.StatusUnauthorized, "Invalid email or password.")
+		case auth.ErrEmailNotVerified:
+			fail(http.StatusForbidden, "Your email address has not been verified.")
+		case auth.ErrUserDisabled:
+			fail(http.StatusForbidden, "Your account has been disabled.")
+		case auth.ErrInvalidMFACode:
+			s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_otp")))
+			page.MFAToken = r.PostFormValue("mfa_token")
+			fail(http.StatusUnauthorized, "Invalid one-time password.")
+		case auth.ErrTooManyMFAAttempts:
+			s.loginFailuresCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_otp")))
+			fail(http.StatusUnauthorized, "Too many failed one-time passwords. Sign in again.")
+		case auth.ErrInvalidMFAToken, auth.ErrTOTPNotEnabled, user.ErrNotFoundByID:
+			fail(http.StatusUnauthorized, "Your sign in has expired. Sign in again.")
+		default:
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationSignIn))
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileSignIn, self, "failed to sign in", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+		}
+		return nil, false
+	}
+
+	// The session cookie is sent along with the navigation from the client to the authorization endpoint
+	s.setCookie(w, cookieSession, signInResponse.SessionToken, signInResponse.ExpiresIn, http.SameSiteLaxMode)
+	return signInResponse.Session, true
+}
@@ -0,0 +1,33 @@
+package httphandler
+
+import (
+	"context"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/internal/auth/guard"
+
+	"github.com/google/uuid"
+)
+
+// subjectFromContext returns the user identified by the access token verified by the guard.
+func subjectFromContext(ctx context.Context) (uuid.UUID, error) {
+	_, claims, err := guard.FromContext(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	return uuid.Parse(sub)
+}
+
+// scopeFromContext returns the scopes granted to the access token verified by the guard.
+func scopeFromContext(ctx context.Context) []string {
+	_, claims, err := guard.FromContext(ctx)
+	if err != nil {
+		return nil
+	}
+
+	scope, _ := claims[auth.ClaimScope].(string)
+	return strings.Fields(scope)
+}
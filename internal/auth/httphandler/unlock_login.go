@@ -0,0 +1,90 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/auth/guard"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationUnlockLogin = "unlock_login"
+	FileUnlockLogin      = OperationUnlockLogin + ".go"
+)
+
+func (s *AuthServer) handleUnlockLogin() http.HandlerFunc {
+	const self = "handleUnlockLogin"
+
+	type request struct {
+		Email string `json:"email" validate:"required_without=IP,omitempty,email"`
+		IP    string `json:"ip" validate:"required_without=Email,omitempty,ip"`
+	}
+
+	type response struct {
+		Unlocked int64 `json:"unlocked"`
+	}
+
+	contract := map[string]responder.Field{
+		"Email": {
+			Name:       "email",
+			Validation: "Field is required when ip is empty and must be a valid email.",
+		},
+		"IP": {
+			Name:       "ip",
+			Validation: "Field is required when email is empty and must be a valid IP address.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnlockLogin))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnlockLogin))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		unlockLoginResponse, err := s.service.UnlockLogin(ctx, auth.UnlockLoginRequest{
+			Email: req.Email,
+			IP:    req.IP,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnlockLogin))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileUnlockLogin, self, "failed to unlock logins", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+		_, claims, _ := guard.FromContext(ctx)
+		s.logger.InfoContext(ctx, otel.FormatLog(Path, FileUnlockLogin, self, fmt.Sprintf("user %q unlocked logins of email %q and ip %q", claims["sub"], req.Email, req.IP), nil))
+
+		if err := responder.Respond(w, r, http.StatusOK, response{unlockLoginResponse.Unlocked}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUnlockLogin))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileUnlockLogin, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationUnlockLogin)
+	return otelhandler.ServeHTTP
+}
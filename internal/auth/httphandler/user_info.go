@@ -0,0 +1,73 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationUserInfo = "user_info"
+	FileUserInfo      = OperationUserInfo + ".go"
+)
+
+func (s *AuthServer) handleUserInfo() http.HandlerFunc {
+	const self = "handleUserInfo"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		sub, err := subjectFromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUserInfo))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		userInfoResponse, err := s.service.UserInfo(ctx, auth.UserInfoRequest{
+			UserID: sub,
+			Scope:  scopeFromContext(ctx),
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUserInfo))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInsufficientScope:
+				// RFC 6750 section 3.1
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, auth.ScopeOpenID))
+				responder.RespondMetaMessage(w, r, http.StatusForbidden, "Access token was not granted the openid scope.")
+			case user.ErrNotFoundByID:
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				responder.RespondMetaMessage(w, r, http.StatusUnauthorized, "Access token is invalid.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileUserInfo, self, "failed to find user claims", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		// Claims are returned as a plain JSON object, not wrapped in a data field (OIDC Core section 5.3.2)
+		w.Header().Set("Cache-Control", "no-store")
+		if err := responder.Respond(w, r, http.StatusOK, userInfoResponse.Claims); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUserInfo))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileUserInfo, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationUserInfo)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,115 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationVerifyDevice = "verify_device"
+	FileVerifyDevice      = OperationVerifyDevice + ".go"
+)
+
+// handleVerifyDevice serves the verification page of the device authorization grant (RFC 8628 section 3.3).
+// Users sign in first, then enter the user code shown by the device, unless the device sent them here
+// with it, and are finally asked to approve or deny the device.
+func (s *AuthServer) handleVerifyDevice() http.HandlerFunc {
+	const self = "handleVerifyDevice"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		_, err := s.service.ResumeSession(ctx, s.cookie(r, cookieSession))
+		switch {
+		case err == nil:
+		case errors.Is(err, auth.ErrInvalidSession):
+			// The login page posts back to this page, along with the user code of the query
+			csrfToken, err := s.csrfToken(w, r)
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyDevice))
+				span.RecordError(err)
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileVerifyDevice, self, "failed to generate csrf token", err))
+				s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+				return
+			}
+			s.renderPage(w, r, http.StatusOK, "login.html.tmpl", loginPage{
+				ClientName: deviceLoginName,
+				CSRFToken:  csrfToken,
+			})
+			return
+		default:
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyDevice))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileVerifyDevice, self, "failed to resume session", err))
+			s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+			return
+		}
+
+		page := devicePage{UserCode: r.URL.Query().Get("user_code")}
+		if page.UserCode == "" {
+			s.renderDevicePage(w, r, http.StatusOK, page)
+			return
+		}
+
+		status, err := s.findDevice(ctx, &page)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyDevice))
+			span.RecordError(err)
+			if status == http.StatusInternalServerError {
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileVerifyDevice, self, "failed to find device code", err))
+				s.renderError(w, r, status, "The server encountered an unexpected condition.")
+				return
+			}
+		}
+		s.renderDevicePage(w, r, status, page)
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationVerifyDevice)
+	return otelhandler.ServeHTTP
+}
+
+// deviceLoginName completes the title of the login page shown on the verification page,
+// where the client is not known until the user code is entered.
+const deviceLoginName = "your account"
+
+// findDevice fills the page with the client and the scope of the pending device authorization
+// of the user code. Unknown user codes are shown to the user, along with the returned status.
+func (s *AuthServer) findDevice(ctx context.Context, page *devicePage) (int, error) {
+	found, err := s.service.FindDeviceCode(ctx, auth.FindDeviceCodeRequest{UserCode: page.UserCode})
+	if err != nil {
+		if err == auth.ErrInvalidUserCode {
+			page.Error = "This code is invalid or has expired. Check the code shown on your device."
+			return http.StatusBadRequest, err
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	page.ClientName = found.Client.Name
+	page.Scope = found.DeviceCode.Scope
+	return http.StatusOK, nil
+}
+
+// renderDevicePage renders the verification page along with a csrf token.
+func (s *AuthServer) renderDevicePage(w http.ResponseWriter, r *http.Request, status int, page devicePage) {
+	const self = "renderDevicePage"
+
+	csrfToken, err := s.csrfToken(w, r)
+	if err != nil {
+		trace.SpanFromContext(r.Context()).RecordError(err)
+		s.logger.ErrorContext(r.Context(), otel.FormatLog(Path, FileVerifyDevice, self, "failed to generate csrf token", err))
+		s.renderError(w, r, http.StatusInternalServerError, "The server encountered an unexpected condition.")
+		return
+	}
+	page.CSRFToken = csrfToken
+	s.renderPage(w, r, status, "device.html.tmpl", page)
+}
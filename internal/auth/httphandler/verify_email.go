@@ -0,0 +1,114 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"auth/internal/auth"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationVerifyEmail = "verify_email"
+	FileVerifyEmail      = OperationVerifyEmail + ".go"
+)
+
+func (s *AuthServer) handleVerifyEmail() http.HandlerFunc {
+	const self = "handleVerifyEmail"
+
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+		Code  string `json:"code" validate:"required,numeric,len=6"`
+	}
+
+	type response struct {
+		Entity        string    `json:"entity"`
+		ID            uuid.UUID `json:"id"`
+		Email         string    `json:"email"`
+		EmailVerified bool      `json:"email_verified"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+	}
+
+	contract := map[string]responder.Field{
+		"Email": {
+			Name:       "email",
+			Validation: "Field is required and must be a valid email.",
+		},
+		"Code": {
+			Name:       "code",
+			Validation: "Field is required and must be a 6 digit code.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyEmail))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyEmail))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		verifyEmailResponse, err := s.service.VerifyEmail(ctx, auth.VerifyEmailRequest{
+			Email: req.Email,
+			Code:  req.Code,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyEmail))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrInvalidVerificationCode:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid or expired verification code.")
+			case auth.ErrTooManyVerificationAttempts:
+				responder.RespondMetaMessage(w, r, http.StatusTooManyRequests, "Too many failed attempts, please request a new verification code.")
+			case user.ErrInternal:
+				fallthrough
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.usersVerifiedCounter.Add(ctx, 1)
+
+		resp := response{
+			Entity:        s.entity,
+			ID:            verifyEmailResponse.User.ID,
+			Email:         verifyEmailResponse.User.Email,
+			EmailVerified: verifyEmailResponse.User.EmailVerified,
+			CreatedAt:     verifyEmailResponse.User.CreatedAt,
+			UpdatedAt:     verifyEmailResponse.User.UpdatedAt,
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: resp}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationVerifyEmail))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileVerifyEmail, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationVerifyEmail)
+	return otelhandler.ServeHTTP
+}
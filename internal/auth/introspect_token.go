@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"auth/internal/client"
+	"auth/internal/user"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type IntrospectTokenRequest struct {
+	Token string
+	// TokenTypeHint is either "access_token" or "refresh_token".
+	// Any other value is ignored, as allowed by RFC 7662.
+	TokenTypeHint string
+}
+
+// IntrospectTokenResponse describes a token as defined by RFC 7662.
+// Every field other than Active is empty for inactive tokens.
+type IntrospectTokenResponse struct {
+	Active    bool
+	TokenType string
+	Subject   string
+	ClientID  string
+	Audience  []string
+	Issuer    string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+	JTI       string
+	Scope     string
+}
+
+// IntrospectToken reports whether a token is currently active.
+// Besides its own validity, a token is inactive once revoked,
+// once the user or the client it was issued to no longer exists,
+// once the user has been disabled or, for access tokens, once the user has changed their password.
+func (s *Service) IntrospectToken(ctx context.Context, req IntrospectTokenRequest) (IntrospectTokenResponse, error) {
+	lookups := []func(context.Context, string) (IntrospectTokenResponse, error){s.introspectAccessToken, s.introspectRefreshToken}
+	if req.TokenTypeHint == "refresh_token" {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		resp, err := lookup(ctx, req.Token)
+		if err != nil {
+			return IntrospectTokenResponse{}, err
+		}
+		if resp.Active {
+			return resp, nil
+		}
+	}
+	return IntrospectTokenResponse{Active: false}, nil
+}
+
+func (s *Service) introspectAccessToken(ctx context.Context, token string) (IntrospectTokenResponse, error) {
+	parsed, err := s.Keyring.Verify(token)
+	if err != nil {
+		return IntrospectTokenResponse{Active: false}, nil
+	}
+
+	// ID tokens are signed by the same keys, but never grant access
+	if parsed.Has(ClaimAuthorizedParty) {
+		return IntrospectTokenResponse{Active: false}, nil
+	}
+
+	jti, _ := parsed.JwtID()
+	revoked, err := s.Repo.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return IntrospectTokenResponse{}, err
+	}
+	if revoked {
+		return IntrospectTokenResponse{Active: false}, nil
+	}
+
+	sub, _ := parsed.Subject()
+	var clientID string
+	parsed.Get(ClaimClientID, &clientID)
+
+	// Tokens of the client credentials grant are issued to the client itself
+	if clientID != "" && clientID == sub {
+		if _, err := s.ClientRepo.FindByID(ctx, clientID); err != nil {
+			if err == client.ErrNotFoundByID {
+				return IntrospectTokenResponse{Active: false}, nil
+			}
+			return IntrospectTokenResponse{}, err
+		}
+	} else {
+		u, err := s.findSubject(ctx, sub)
+		if err != nil || u == nil {
+			return IntrospectTokenResponse{Active: false}, err
+		}
+
+		var version float64
+		parsed.Get(ClaimTokenVersion, &version)
+		if int(version) != u.TokenVersion {
+			return IntrospectTokenResponse{Active: false}, nil
+		}
+	}
+
+	resp := IntrospectTokenResponse{
+		Active:    true,
+		TokenType: "access_token",
+		Subject:   sub,
+		ClientID:  clientID,
+		JTI:       jti,
+	}
+	resp.Audience, _ = parsed.Audience()
+	resp.Issuer, _ = parsed.Issuer()
+	resp.ExpiresAt, _ = parsed.Expiration()
+	resp.IssuedAt, _ = parsed.IssuedAt()
+
+	var scope string
+	if err := parsed.Get(ClaimScope, &scope); err == nil {
+		resp.Scope = scope
+	}
+
+	return resp, nil
+}
+
+func (s *Service) introspectRefreshToken(ctx context.Context, token string) (IntrospectTokenResponse, error) {
+	stored, err := s.Repo.FindRefreshTokenByHash(ctx, secret.Hash(token))
+	if err != nil {
+		if err == ErrRefreshTokenNotFound {
+			return IntrospectTokenResponse{Active: false}, nil
+		}
+		return IntrospectTokenResponse{}, err
+	}
+
+	if stored.RevokedAt != nil || stored.RotatedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return IntrospectTokenResponse{Active: false}, nil
+	}
+
+	if u, err := s.findSubject(ctx, stored.UserID.String()); err != nil || u == nil {
+		return IntrospectTokenResponse{Active: false}, err
+	}
+
+	return IntrospectTokenResponse{
+		Active:    true,
+		TokenType: "refresh_token",
+		Subject:   stored.UserID.String(),
+		ClientID:  stored.ClientID,
+		Scope:     strings.Join(stored.Scope, " "),
+		Audience:  s.JWTConfig.Audience,
+		Issuer:    s.JWTConfig.Issuer,
+		ExpiresAt: stored.ExpiresAt,
+		IssuedAt:  stored.CreatedAt,
+		JTI:       stored.ID.String(),
+	}, nil
+}
+
+// findSubject returns the user that the subject of a token refers to,
+// or nil if it is no longer a registered user or has been disabled.
+func (s *Service) findSubject(ctx context.Context, sub string) (*user.User, error) {
+	id, err := uuid.Parse(sub)
+	if err != nil {
+		return nil, nil
+	}
+
+	u, err := s.UserRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == user.ErrNotFoundByID {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if u.DisabledAt != nil {
+		return nil, nil
+	}
+	return u, nil
+}
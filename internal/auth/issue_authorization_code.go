@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/client"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type IssueAuthorizationCodeRequest struct {
+	Client        *client.Client
+	Session       *Session
+	RedirectURI   string
+	Scope         []string
+	CodeChallenge string
+	// Nonce of OpenID Connect requests, echoed in the ID token
+	Nonce string
+}
+
+type IssueAuthorizationCodeResponse struct {
+	Code string
+}
+
+// IssueAuthorizationCode issues a single-use code to the signed in user of a validated authorization request.
+func (s *Service) IssueAuthorizationCode(ctx context.Context, req IssueAuthorizationCodeRequest) (IssueAuthorizationCodeResponse, error) {
+	code, err := secret.Generate(32)
+	if err != nil {
+		return IssueAuthorizationCodeResponse{}, err
+	}
+
+	now := time.Now()
+	err = s.Repo.InsertAuthorizationCode(ctx, &AuthorizationCode{
+		ID:            uuid.New(),
+		ClientID:      req.Client.ID,
+		UserID:        req.Session.UserID,
+		Hash:          secret.Hash(code),
+		RedirectURI:   req.RedirectURI,
+		Scope:         req.Scope,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		AMR:           req.Session.AMR,
+		AuthTime:      req.Session.CreatedAt,
+		ExpiresAt:     now.Add(time.Duration(s.Authorize.CodeTTL) * time.Second),
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return IssueAuthorizationCodeResponse{}, err
+	}
+
+	return IssueAuthorizationCodeResponse{Code: code}, nil
+}
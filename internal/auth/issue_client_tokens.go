@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"auth/internal/client"
+	"auth/internal/user"
+
+	"github.com/google/uuid"
+)
+
+// ClientTokens are the tokens issued to a client acting on behalf of a user.
+type ClientTokens struct {
+	GenerateTokenResponse
+	// Only issued to clients registered with the refresh_token grant
+	RefreshToken string
+	// Only issued to OpenID Connect requests
+	IDToken string
+	Scope   []string
+}
+
+type issueClientTokensRequest struct {
+	Client   *client.Client
+	User     *user.User
+	Scope    []string
+	AMR      []string
+	AuthTime time.Time
+	Nonce    string
+	// Refresh tokens are named after the grant they were issued from, so that they can be revoked along with it
+	FamilyID uuid.UUID
+}
+
+// issueClientTokens issues the tokens of a grant that a user approved for a client.
+func (s *Service) issueClientTokens(ctx context.Context, req issueClientTokensRequest) (ClientTokens, error) {
+	token, err := s.GenerateToken(ctx, GenerateTokenRequest{
+		UserID:       req.User.ID,
+		TokenVersion: req.User.TokenVersion,
+		AMR:          req.AMR,
+		Client:       req.Client,
+		Scope:        req.Scope,
+	})
+	if err != nil {
+		return ClientTokens{}, err
+	}
+
+	tokens := ClientTokens{GenerateTokenResponse: token, Scope: req.Scope}
+	if req.Client.AllowsGrant(client.GrantRefreshToken) {
+		refresh, err := s.IssueRefreshToken(ctx, IssueRefreshTokenRequest{
+			UserID:   req.User.ID,
+			FamilyID: req.FamilyID,
+			AMR:      req.AMR,
+			Client:   req.Client,
+			Scope:    req.Scope,
+		})
+		if err != nil {
+			return ClientTokens{}, err
+		}
+		tokens.RefreshToken = refresh.RefreshToken
+	}
+
+	if slices.Contains(req.Scope, ScopeOpenID) {
+		idToken, err := s.GenerateIDToken(ctx, GenerateIDTokenRequest{
+			User:     req.User,
+			Client:   req.Client,
+			Scope:    req.Scope,
+			Nonce:    req.Nonce,
+			AMR:      req.AMR,
+			AuthTime: req.AuthTime,
+		})
+		if err != nil {
+			return ClientTokens{}, err
+		}
+		tokens.IDToken = string(idToken.IDToken)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/client"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type IssueRefreshTokenRequest struct {
+	UserID uuid.UUID
+	// FamilyID groups rotated tokens together.
+	// A zero value starts a new family.
+	FamilyID uuid.UUID
+	AMR      []string
+	// Client whose refresh token lifetime takes precedence over the configured one
+	Client *client.Client
+	Scope  []string
+}
+
+type IssueRefreshTokenResponse struct {
+	RefreshToken string
+	ExpiresIn    int
+}
+
+func (s *Service) IssueRefreshToken(ctx context.Context, req IssueRefreshTokenRequest) (IssueRefreshTokenResponse, error) {
+	value, err := secret.Generate(32)
+	if err != nil {
+		return IssueRefreshTokenResponse{}, err
+	}
+
+	familyID := req.FamilyID
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+
+	expiration := s.RefreshConfig.Expiration
+	var clientID string
+	if req.Client != nil {
+		clientID = req.Client.ID
+		if req.Client.RefreshTokenTTL > 0 {
+			expiration = req.Client.RefreshTokenTTL
+		}
+	}
+
+	now := time.Now()
+	token := &RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    req.UserID,
+		Hash:      secret.Hash(value),
+		AMR:       req.AMR,
+		ClientID:  clientID,
+		Scope:     req.Scope,
+		ExpiresAt: now.Add(time.Duration(expiration) * time.Second),
+		CreatedAt: now,
+	}
+	if err := s.Repo.InsertRefreshToken(ctx, token); err != nil {
+		return IssueRefreshTokenResponse{}, err
+	}
+
+	return IssueRefreshTokenResponse{
+		RefreshToken: value,
+		ExpiresIn:    expiration,
+	}, nil
+}
@@ -0,0 +1,402 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"auth/pkg/keys"
+	"auth/pkg/otel"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+const FileKeyring = "keyring.go"
+
+// symmetricKeyID is the "kid" stamped on HMAC signed tokens.
+// HMAC secrets are never published, so there is no need to derive it from the key.
+const symmetricKeyID = "hmac"
+
+var ErrNoSigningKey = errors.New("keyring does not hold any signing key")
+
+// SigningKey is a key of the keyring along with its lifecycle.
+//
+// A key is "next" while ActivatesAt is in the future: it is already published
+// so that resource servers can cache it, but nothing is signed with it yet.
+// Once it activates it becomes the signing key and its predecessor is retired.
+// Retired keys are still accepted until ExpiresAt, which is when the last token
+// they signed expires, and are dropped from the keyring afterwards.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	ActivatesAt time.Time
+	RetiredAt   *time.Time
+	ExpiresAt   *time.Time
+	// File name of keys loaded from the key directory, without its extension
+	file      string
+	signKey   jwk.Key
+	verifyKey any
+	symmetric bool
+}
+
+// VerifyKey returns the raw key used for verifying signatures,
+// which is the public key for asymmetric algorithms and the secret for HMAC.
+func (k *SigningKey) VerifyKey() any {
+	return k.verifyKey
+}
+
+// Keyring holds the keys used for signing and verifying tokens.
+//
+// When a key directory is configured, the keyring is rebuilt from it periodically
+// and the lifecycle of each key is derived from the file modification time plus
+// the rotation delay. Every replica sharing the directory therefore agrees on
+// which key is next, active or retired, and survives restarts without extra state.
+// New keys are named after the time their rotation was due, so replicas rotating
+// at the same time race for the same file name and only one of them writes it.
+type Keyring struct {
+	mu     sync.RWMutex
+	config *JWTConfig
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+func NewKeyring(config *JWTConfig) (*Keyring, error) {
+	k := &Keyring{config: config}
+
+	if keys.IsSymmetric(config.Algorithm) {
+		secret := []byte(config.Key)
+		signKey, err := jwk.Import(secret)
+		if err != nil {
+			return nil, err
+		}
+		key, err := newSigningKey(config.Algorithm, symmetricKeyID, signKey, secret, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		key.symmetric = true
+		k.active = key
+		k.keys = map[string]*SigningKey{key.ID: key}
+		return k, nil
+	}
+
+	if err := k.refresh(config.Keys, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := k.rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Active returns the key that new tokens must be signed with.
+func (k *Keyring) Active() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Lookup finds a key that is still accepted for verification.
+// Tokens without a "kid" header predate key identifiers and are checked against the active key.
+func (k *Keyring) Lookup(kid string) (*SigningKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" {
+		return k.active, k.active != nil
+	}
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// Verify parses tokenString, checks its signature with the key named by its "kid" header
+// and validates the time based registered claims, along with any claims required by options.
+func (k *Keyring) Verify(tokenString string, options ...jwt.ParseOption) (jwt.Token, error) {
+	msg, err := jws.Parse([]byte(tokenString))
+	if err != nil || len(msg.Signatures()) != 1 {
+		return nil, ErrInvalidToken
+	}
+
+	kid, _ := msg.Signatures()[0].ProtectedHeaders().KeyID()
+	key, ok := k.Lookup(kid)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	alg, ok := jwa.LookupSignatureAlgorithm(key.Algorithm)
+	if !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	token, err := jwt.Parse([]byte(tokenString), append([]jwt.ParseOption{jwt.WithKey(alg, key.verifyKey)}, options...)...)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return token, nil
+}
+
+// Intended returns the options that require a token to be issued by this service for its configured audience,
+// which access tokens minted for the custom audiences of a client are not.
+func (k *Keyring) Intended() []jwt.ParseOption {
+	options := []jwt.ParseOption{jwt.WithIssuer(k.config.Issuer)}
+	for _, aud := range k.config.Audience {
+		options = append(options, jwt.WithAudience(aud))
+	}
+	return options
+}
+
+// Published returns the public keys that resource servers may use,
+// which are every next, active and retired but not yet expired asymmetric key.
+func (k *Keyring) Published() ([]jwk.Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	published := make([]jwk.Key, 0, len(k.keys))
+	for _, key := range k.sorted() {
+		if key.symmetric {
+			continue
+		}
+
+		public, err := jwk.PublicKeyOf(key.signKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := public.Set(jwk.KeyUsageKey, jwk.ForSignature); err != nil {
+			return nil, err
+		}
+		published = append(published, public)
+	}
+	return published, nil
+}
+
+// Algorithms returns the signature algorithms of the keys that tokens may be signed with.
+func (k *Keyring) Algorithms() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	algorithms := make([]string, 0, 1)
+	for _, key := range k.sorted() {
+		if !slices.Contains(algorithms, key.Algorithm) {
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+	return algorithms
+}
+
+// Reload rebuilds the keyring from the key directory and rotates keys if needed.
+func (k *Keyring) Reload(now time.Time) error {
+	if k.config.KeyDir == "" {
+		return nil
+	}
+
+	loaded, err := keys.LoadDir(k.config.KeyDir)
+	if err != nil && !errors.Is(err, keys.ErrEmptyKeyDir) {
+		return err
+	}
+
+	if err := k.refresh(loaded, now); err != nil {
+		return err
+	}
+	return k.rotate(now)
+}
+
+// Run reloads the keyring periodically until ctx is done.
+func (k *Keyring) Run(ctx context.Context, logger *slog.Logger) {
+	const self = "Run"
+
+	if k.config.KeyDir == "" || k.config.RotationCheck <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(k.config.RotationCheck) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			previous := k.Active()
+			if err := k.Reload(now); err != nil {
+				logger.ErrorContext(ctx, otel.FormatLog(Path, FileKeyring, self, "failed to reload signing keys", err))
+				continue
+			}
+			if active := k.Active(); previous == nil || active.ID != previous.ID {
+				logger.InfoContext(ctx, otel.FormatLog(Path, FileKeyring, self, fmt.Sprintf("signing key %q is now active", active.ID), nil))
+			}
+		}
+	}
+}
+
+// refresh recomputes the lifecycle of every loaded key.
+func (k *Keyring) refresh(loaded []keys.Key, now time.Time) error {
+	delay := time.Duration(k.config.RotationDelay) * time.Second
+	expiration := time.Duration(k.config.Expiration) * time.Second
+
+	candidates := make([]*SigningKey, 0, len(loaded))
+	for _, l := range loaded {
+		if err := keys.Check(k.config.Algorithm, l.Private); err != nil {
+			return fmt.Errorf("%w (key %q)", err, l.ID)
+		}
+
+		signKey, err := jwk.Import(l.Private)
+		if err != nil {
+			return err
+		}
+		thumbprint, err := signKey.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return err
+		}
+
+		// Only keys written by the rotation policy need to wait before signing,
+		// keys provided up front by a single key file are active right away
+		activatesAt := l.ModTime
+		if k.config.KeyDir != "" {
+			activatesAt = activatesAt.Add(delay)
+		}
+
+		key, err := newSigningKey(k.config.Algorithm, base64.RawURLEncoding.EncodeToString(thumbprint), signKey, l.Private.Public(), activatesAt)
+		if err != nil {
+			return err
+		}
+		key.file = l.ID
+		candidates = append(candidates, key)
+	}
+
+	if len(candidates) == 0 {
+		if k.config.KeyDir == "" {
+			return ErrNoSigningKey
+		}
+		// An empty key directory is bootstrapped by the rotation policy
+		k.mu.Lock()
+		k.active, k.keys = nil, map[string]*SigningKey{}
+		k.mu.Unlock()
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].ActivatesAt.Equal(candidates[j].ActivatesAt) {
+			return candidates[i].ID < candidates[j].ID
+		}
+		return candidates[i].ActivatesAt.Before(candidates[j].ActivatesAt)
+	})
+
+	// The active key is the most recently activated one.
+	// If none has activated yet, the oldest key signs so that there is always one.
+	active := 0
+	for i, key := range candidates {
+		if !key.ActivatesAt.After(now) {
+			active = i
+		}
+	}
+
+	set := make(map[string]*SigningKey, len(candidates))
+	for i, key := range candidates {
+		if i < active {
+			retiredAt := candidates[i+1].ActivatesAt
+			expiresAt := retiredAt.Add(expiration)
+			// Expired keys no longer verify anything, so their files are deleted as well
+			if !expiresAt.After(now) {
+				if k.config.KeyDir != "" {
+					if err := keys.Remove(k.config.KeyDir, key.file); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			key.RetiredAt = &retiredAt
+			key.ExpiresAt = &expiresAt
+		}
+		set[key.ID] = key
+	}
+
+	k.mu.Lock()
+	k.active, k.keys = candidates[active], set
+	k.mu.Unlock()
+	return nil
+}
+
+// rotate writes a new key to the key directory once the active key
+// is about to reach the rotation interval and no next key is pending.
+// The new key is published right away and starts signing after the rotation delay.
+// When another replica already wrote the key due at the same time, it is loaded instead.
+func (k *Keyring) rotate(now time.Time) error {
+	if k.config.KeyDir == "" || k.config.RotationInterval <= 0 {
+		return nil
+	}
+
+	k.mu.RLock()
+	active := k.active
+	pending := false
+	for _, key := range k.keys {
+		if key.ActivatesAt.After(now) && key != active {
+			pending = true
+		}
+	}
+	k.mu.RUnlock()
+
+	// An empty directory is bootstrapped with a key named after the current interval
+	interval := time.Duration(k.config.RotationInterval) * time.Second
+	due := now.Truncate(interval)
+	if active != nil {
+		delay := time.Duration(k.config.RotationDelay) * time.Second
+		due = active.ActivatesAt.Add(interval - delay)
+		if pending || now.Before(due) {
+			return nil
+		}
+	}
+
+	private, err := keys.Generate(k.config.Algorithm)
+	if err != nil {
+		return err
+	}
+	if _, err := keys.Write(k.config.KeyDir, due.UTC().Format("20060102T150405Z"), private); err != nil && !errors.Is(err, keys.ErrKeyExists) {
+		return err
+	}
+
+	loaded, err := keys.LoadDir(k.config.KeyDir)
+	if err != nil {
+		return err
+	}
+	return k.refresh(loaded, now)
+}
+
+// sorted returns the keys ordered by activation time.
+// The caller must hold the lock.
+func (k *Keyring) sorted() []*SigningKey {
+	sorted := make([]*SigningKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ActivatesAt.Before(sorted[j].ActivatesAt)
+	})
+	return sorted
+}
+
+func newSigningKey(alg, kid string, signKey jwk.Key, verifyKey any, activatesAt time.Time) (*SigningKey, error) {
+	if err := signKey.Set(jwk.KeyIDKey, kid); err != nil {
+		return nil, err
+	}
+	if err := signKey.Set(jwk.AlgorithmKey, alg); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:          kid,
+		Algorithm:   alg,
+		ActivatesAt: activatesAt,
+		signKey:     signKey,
+		verifyKey:   verifyKey,
+	}, nil
+}
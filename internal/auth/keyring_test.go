@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"auth/pkg/keys"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(dir string) *JWTConfig {
+	return &JWTConfig{
+		Algorithm:        "ES256",
+		KeyDir:           dir,
+		Expiration:       300,
+		RotationInterval: 3600,
+		RotationDelay:    600,
+	}
+}
+
+// writeKey writes a new key to dir as if it was written at modTime.
+func writeKey(t *testing.T, dir, id string, modTime time.Time) {
+	t.Helper()
+
+	private, err := keys.Generate("ES256")
+	require.NoError(t, err)
+	_, err = keys.Write(dir, id, private)
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, id+".pem"), modTime, modTime))
+}
+
+func keyFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	require.NoError(t, err)
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	return names
+}
+
+func TestKeyringBootstrap(t *testing.T) {
+	dir := t.TempDir()
+
+	keyring, err := NewKeyring(newTestConfig(dir))
+	require.NoError(t, err)
+	require.Len(t, keyFiles(t, dir), 1)
+
+	// The bootstrapped key signs right away, even before its activation delay
+	active := keyring.Active()
+	require.NotNil(t, active)
+	published, err := keyring.Published()
+	require.NoError(t, err)
+	require.Len(t, published, 1)
+	kid, _ := published[0].KeyID()
+	require.Equal(t, active.ID, kid)
+
+	// Another replica bootstrapping the same directory ends up with the same key
+	replica, err := NewKeyring(newTestConfig(dir))
+	require.NoError(t, err)
+	require.Len(t, keyFiles(t, dir), 1)
+	require.Equal(t, active.ID, replica.Active().ID)
+}
+
+func TestKeyringLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeKey(t, dir, "old", now.Add(-2*time.Hour))
+	writeKey(t, dir, "new", now.Add(-time.Minute))
+
+	keyring := &Keyring{config: newTestConfig(dir)}
+	require.NoError(t, keyring.Reload(now))
+
+	// The new key is published during its activation delay, but the old one still signs
+	published, err := keyring.Published()
+	require.NoError(t, err)
+	require.Len(t, published, 2)
+	old := keyring.Active()
+	require.Nil(t, old.RetiredAt)
+	require.Len(t, keyFiles(t, dir), 2)
+
+	// Once the new key activates, the old one is retired but still verifies
+	activated := now.Add(10 * time.Minute)
+	require.NoError(t, keyring.Reload(activated))
+	require.NotEqual(t, old.ID, keyring.Active().ID)
+	retired, ok := keyring.Lookup(old.ID)
+	require.True(t, ok)
+	require.NotNil(t, retired.RetiredAt)
+	require.Equal(t, retired.RetiredAt.Add(300*time.Second), *retired.ExpiresAt)
+
+	// After the last token it signed has expired, it is dropped along with its file
+	require.NoError(t, keyring.Reload(retired.ExpiresAt.Add(time.Second)))
+	_, ok = keyring.Lookup(old.ID)
+	require.False(t, ok)
+	require.Equal(t, []string{"new.pem"}, keyFiles(t, dir))
+}
+
+func TestKeyringRotate(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	t.Run("not_due", func(t *testing.T) {
+		writeKey(t, dir, "current", now.Add(-30*time.Minute))
+
+		keyring := &Keyring{config: newTestConfig(dir)}
+		require.NoError(t, keyring.Reload(now))
+		require.Len(t, keyFiles(t, dir), 1)
+	})
+
+	// Replicas that find the rotation due at the same time write a single key
+	t.Run("due", func(t *testing.T) {
+		current := now.Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "current.pem"), current, current))
+
+		loaded, err := keys.LoadDir(dir)
+		require.NoError(t, err)
+		first := &Keyring{config: newTestConfig(dir)}
+		second := &Keyring{config: newTestConfig(dir)}
+		require.NoError(t, first.refresh(loaded, now))
+		require.NoError(t, second.refresh(loaded, now))
+
+		require.NoError(t, first.rotate(now))
+		require.NoError(t, second.rotate(now))
+		require.Len(t, keyFiles(t, dir), 2)
+
+		firstKeys, err := first.Published()
+		require.NoError(t, err)
+		secondKeys, err := second.Published()
+		require.NoError(t, err)
+		require.Len(t, firstKeys, 2)
+		require.Equal(t, firstKeys, secondKeys)
+		require.Equal(t, first.Active().ID, second.Active().ID)
+	})
+
+	// A pending key is not rotated again
+	t.Run("pending", func(t *testing.T) {
+		keyring := &Keyring{config: newTestConfig(dir)}
+		require.NoError(t, keyring.Reload(now.Add(time.Minute)))
+		require.Len(t, keyFiles(t, dir), 2)
+	})
+}
+
+func TestKeyringVerify(t *testing.T) {
+	sign := func(t *testing.T, key *SigningKey) string {
+		t.Helper()
+
+		token, err := jwt.NewBuilder().Subject("rogerio").Expiration(time.Now().Add(time.Minute)).Build()
+		require.NoError(t, err)
+		alg, ok := jwa.LookupSignatureAlgorithm(key.Algorithm)
+		require.True(t, ok)
+		signed, err := jwt.Sign(token, jwt.WithKey(alg, key.signKey))
+		require.NoError(t, err)
+		return string(signed)
+	}
+
+	t.Run("asymmetric", func(t *testing.T) {
+		keyring, err := NewKeyring(newTestConfig(t.TempDir()))
+		require.NoError(t, err)
+
+		token, err := keyring.Verify(sign(t, keyring.Active()))
+		require.NoError(t, err)
+		sub, _ := token.Subject()
+		require.Equal(t, "rogerio", sub)
+
+		// Keys of another keyring are unknown
+		other, err := NewKeyring(newTestConfig(t.TempDir()))
+		require.NoError(t, err)
+		_, err = keyring.Verify(sign(t, other.Active()))
+		require.ErrorIs(t, err, ErrInvalidToken)
+
+		_, err = keyring.Verify("not a token")
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	// HMAC secrets verify tokens but are never published
+	t.Run("symmetric", func(t *testing.T) {
+		keyring, err := NewKeyring(&JWTConfig{Algorithm: "HS256", Key: "secret"})
+		require.NoError(t, err)
+		require.Equal(t, symmetricKeyID, keyring.Active().ID)
+
+		_, err = keyring.Verify(sign(t, keyring.Active()))
+		require.NoError(t, err)
+
+		published, err := keyring.Published()
+		require.NoError(t, err)
+		require.Empty(t, published)
+	})
+
+	// Tokens for other issuers or audiences are only rejected when intended claims are required
+	t.Run("intended", func(t *testing.T) {
+		config := &JWTConfig{Algorithm: "HS256", Key: "secret", Issuer: "http://localhost:8111/auth", Audience: []string{"http://localhost:8111/"}}
+		keyring, err := NewKeyring(config)
+		require.NoError(t, err)
+
+		signed := func(issuer string, audience ...string) string {
+			token, err := jwt.NewBuilder().Issuer(issuer).Audience(audience).Expiration(time.Now().Add(time.Minute)).Build()
+			require.NoError(t, err)
+			signed, err := jwt.Sign(token, jwt.WithKey(jwa.HS256(), keyring.Active().signKey))
+			require.NoError(t, err)
+			return string(signed)
+		}
+
+		_, err = keyring.Verify(signed(config.Issuer, config.Audience...), keyring.Intended()...)
+		require.NoError(t, err)
+
+		_, err = keyring.Verify(signed(config.Issuer, "billing-api"), keyring.Intended()...)
+		require.ErrorIs(t, err, ErrInvalidToken)
+		_, err = keyring.Verify(signed("http://evil.com", config.Audience...), keyring.Intended()...)
+		require.ErrorIs(t, err, ErrInvalidToken)
+
+		_, err = keyring.Verify(signed(config.Issuer, "billing-api"))
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+type ListPublicKeysResponse struct {
+	Set jwk.Set
+}
+
+func (s *Service) ListPublicKeys(ctx context.Context) (ListPublicKeysResponse, error) {
+	published, err := s.Keyring.Published()
+	if err != nil {
+		return ListPublicKeysResponse{}, err
+	}
+
+	set := jwk.NewSet()
+	for _, key := range published {
+		if err := set.AddKey(key); err != nil {
+			return ListPublicKeysResponse{}, err
+		}
+	}
+
+	return ListPublicKeysResponse{set}, nil
+}
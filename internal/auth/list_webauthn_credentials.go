@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ListWebAuthnCredentialsRequest struct {
+	UserID uuid.UUID
+}
+
+type ListWebAuthnCredentialsResponse struct {
+	Credentials []*WebAuthnCredential
+}
+
+func (s *Service) ListWebAuthnCredentials(ctx context.Context, req ListWebAuthnCredentialsRequest) (ListWebAuthnCredentialsResponse, error) {
+	credentials, err := s.Repo.ListWebAuthnCredentials(ctx, req.UserID)
+	if err != nil {
+		return ListWebAuthnCredentialsResponse{}, err
+	}
+
+	return ListWebAuthnCredentialsResponse{Credentials: credentials}, nil
+}
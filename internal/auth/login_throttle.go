@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	throttleKeyEmail = "email:"
+	throttleKeyIP    = "ip:"
+)
+
+// loginThrottleKeys returns the keys that a login attempt is counted against.
+// Unknown emails are counted as well, so that lockouts do not reveal whether an account exists.
+func loginThrottleKeys(email, ip string) (account, client string) {
+	account = throttleKeyEmail + strings.ToLower(strings.TrimSpace(email))
+	if ip != "" {
+		client = throttleKeyIP + ip
+	}
+	return account, client
+}
+
+// checkLoginLock fails with a *LoginLockedError while either the account or the client IP is locked.
+func (s *Service) checkLoginLock(ctx context.Context, email, ip string, now time.Time) error {
+	if s.Lockout == nil {
+		return nil
+	}
+
+	account, client := loginThrottleKeys(email, ip)
+	var until time.Time
+	for _, key := range []string{account, client} {
+		if key == "" {
+			continue
+		}
+		throttle, err := s.Repo.FindLoginThrottle(ctx, key)
+		if err != nil {
+			return err
+		}
+		if throttle != nil && throttle.LockedUntil != nil && throttle.LockedUntil.After(until) {
+			until = *throttle.LockedUntil
+		}
+	}
+
+	if until.After(now) {
+		return &LoginLockedError{Until: until}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the account and the client IP,
+// locking each of them once its threshold is reached.
+func (s *Service) recordLoginFailure(ctx context.Context, email, ip string, now time.Time) error {
+	if s.Lockout == nil {
+		return nil
+	}
+
+	account, client := loginThrottleKeys(email, ip)
+	thresholds := map[string]int{account: s.Lockout.AccountThreshold}
+	if client != "" {
+		thresholds[client] = s.Lockout.IPThreshold
+	}
+
+	windowStart := now.Add(-time.Duration(s.Lockout.Window) * time.Second)
+	for key, threshold := range thresholds {
+		if threshold <= 0 {
+			continue
+		}
+
+		throttle, err := s.Repo.RecordLoginFailure(ctx, key, now, windowStart)
+		if err != nil {
+			return err
+		}
+		if throttle.Failures < threshold {
+			continue
+		}
+
+		lock := s.lockoutDuration(throttle.Failures - threshold)
+		if err := s.Repo.LockLoginThrottle(ctx, key, now.Add(lock)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockoutDuration doubles the base backoff for every failure past the threshold,
+// up to the configured maximum.
+func (s *Service) lockoutDuration(excess int) time.Duration {
+	lock := time.Duration(s.Lockout.Backoff) * time.Second
+	limit := time.Duration(s.Lockout.MaxDuration) * time.Second
+	for range excess {
+		if lock >= limit {
+			return limit
+		}
+		lock *= 2
+	}
+	return min(lock, limit)
+}
+
+// resetLoginFailures forgets the failed logins of an account after a successful one.
+// Failures of the client IP are kept, as a single valid account must not clear a credential stuffing run.
+func (s *Service) resetLoginFailures(ctx context.Context, email string) error {
+	if s.Lockout == nil {
+		return nil
+	}
+
+	account, _ := loginThrottleKeys(email, "")
+	_, err := s.Repo.DeleteLoginThrottles(ctx, account)
+	return err
+}
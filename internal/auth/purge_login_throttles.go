@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type PurgeLoginThrottlesResponse struct {
+	Purged int64
+}
+
+// PurgeLoginThrottles removes failed login counters that are neither locked nor recent enough to be counted.
+func (s *Service) PurgeLoginThrottles(ctx context.Context) (PurgeLoginThrottlesResponse, error) {
+	windowStart := time.Now().Add(-time.Duration(s.Lockout.Window) * time.Second)
+	purged, err := s.Repo.DeleteStaleLoginThrottles(ctx, windowStart)
+	if err != nil {
+		return PurgeLoginThrottlesResponse{}, err
+	}
+	return PurgeLoginThrottlesResponse{purged}, nil
+}
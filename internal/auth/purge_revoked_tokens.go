@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type PurgeRevokedTokensResponse struct {
+	Purged int64
+}
+
+// PurgeRevokedTokens removes denylist entries of tokens that have expired since they were revoked.
+func (s *Service) PurgeRevokedTokens(ctx context.Context) (PurgeRevokedTokensResponse, error) {
+	purged, err := s.Repo.DeleteExpiredRevokedTokens(ctx, time.Now())
+	if err != nil {
+		return PurgeRevokedTokensResponse{}, err
+	}
+	return PurgeRevokedTokensResponse{purged}, nil
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"auth/internal/client"
+	"auth/pkg/secret"
+)
+
+type RefreshAccessTokenRequest struct {
+	RefreshToken string
+	// Credentials of the client, only checked for tokens of the authorization code grant
+	ClientID     string
+	ClientSecret string
+	// Narrows the scope of the access token, every scope of the refresh token when empty
+	Scope []string
+}
+
+type RefreshAccessTokenResponse struct {
+	GenerateTokenResponse
+	RefreshToken string
+	Scope        []string
+}
+
+func (s *Service) RefreshAccessToken(ctx context.Context, req RefreshAccessTokenRequest) (RefreshAccessTokenResponse, error) {
+	stored, err := s.Repo.FindRefreshTokenByHash(ctx, secret.Hash(req.RefreshToken))
+	if err != nil {
+		if err == ErrRefreshTokenNotFound {
+			return RefreshAccessTokenResponse{}, ErrInvalidRefreshToken
+		}
+		return RefreshAccessTokenResponse{}, err
+	}
+
+	if stored.RevokedAt != nil {
+		return RefreshAccessTokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	// A rotated token being presented again means that it has leaked,
+	// so every token descending from the same grant is revoked
+	if stored.RotatedAt != nil {
+		if err := s.Repo.RevokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+			return RefreshAccessTokenResponse{}, err
+		}
+		return RefreshAccessTokenResponse{}, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return RefreshAccessTokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	// Tokens issued to a client can only be refreshed by that client
+	var c *client.Client
+	if stored.ClientID != "" {
+		c, err = s.authenticateRegisteredClient(ctx, AuthenticateClientRequest{
+			ClientID:     req.ClientID,
+			ClientSecret: req.ClientSecret,
+		})
+		if err != nil {
+			return RefreshAccessTokenResponse{}, err
+		}
+		if c.ID != stored.ClientID {
+			return RefreshAccessTokenResponse{}, ErrInvalidRefreshToken
+		}
+	}
+
+	// The refresh token keeps its scope, only the access token is narrowed (RFC 6749 section 6)
+	scope := stored.Scope
+	if len(req.Scope) > 0 {
+		for _, requested := range req.Scope {
+			if !slices.Contains(stored.Scope, requested) {
+				return RefreshAccessTokenResponse{}, ErrInvalidScope
+			}
+		}
+		scope = req.Scope
+	}
+
+	// Rotation is conditional on the token still being active,
+	// which guards against two concurrent requests redeeming it
+	if err := s.Repo.RotateRefreshToken(ctx, stored.ID); err != nil {
+		if err == ErrRefreshTokenNotActive {
+			if err := s.Repo.RevokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+				return RefreshAccessTokenResponse{}, err
+			}
+			return RefreshAccessTokenResponse{}, ErrRefreshTokenReused
+		}
+		return RefreshAccessTokenResponse{}, err
+	}
+
+	// The user may have been deleted since the token was issued
+	u, err := s.UserRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return RefreshAccessTokenResponse{}, err
+	}
+	// Nor can it have been disabled, which revokes its refresh tokens unless that failed halfway
+	if u.DisabledAt != nil {
+		return RefreshAccessTokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	token, err := s.GenerateToken(ctx, GenerateTokenRequest{
+		UserID:       u.ID,
+		TokenVersion: u.TokenVersion,
+		AMR:          stored.AMR,
+		Client:       c,
+		Scope:        scope,
+		Admin:        s.isAdmin(u),
+	})
+	if err != nil {
+		return RefreshAccessTokenResponse{}, err
+	}
+
+	refresh, err := s.IssueRefreshToken(ctx, IssueRefreshTokenRequest{
+		UserID:   stored.UserID,
+		FamilyID: stored.FamilyID,
+		AMR:      stored.AMR,
+		Client:   c,
+		Scope:    stored.Scope,
+	})
+	if err != nil {
+		return RefreshAccessTokenResponse{}, err
+	}
+
+	return RefreshAccessTokenResponse{token, refresh.RefreshToken, scope}, nil
+}
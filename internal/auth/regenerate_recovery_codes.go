@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RegenerateRecoveryCodesRequest struct {
+	UserID uuid.UUID
+	// One-time password or recovery code
+	Code string
+}
+
+type RegenerateRecoveryCodesResponse struct {
+	RecoveryCodes []string
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user with new ones.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, req RegenerateRecoveryCodesRequest) (RegenerateRecoveryCodesResponse, error) {
+	if _, err := s.verifySecondFactorAttempt(ctx, req.UserID, req.Code); err != nil {
+		return RegenerateRecoveryCodesResponse{}, err
+	}
+
+	values, codes, err := s.generateRecoveryCodes(req.UserID)
+	if err != nil {
+		return RegenerateRecoveryCodesResponse{}, err
+	}
+	if err := s.Repo.ReplaceRecoveryCodes(ctx, req.UserID, codes); err != nil {
+		return RegenerateRecoveryCodesResponse{}, err
+	}
+
+	return RegenerateRecoveryCodesResponse{values}, nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 
 	"auth/internal/user"
 
@@ -17,8 +18,13 @@ type RegisterResponse struct {
 	User *user.User
 }
 
+// Register creates a new user and sends it a verification code.
+// If only the email delivery fails, the user is still returned
+// along with ErrVerificationNotSent, since a new code can be requested later.
 func (s *Service) Register(ctx context.Context, req RegisterRequest) (RegisterResponse, error) {
-	// TODO: Password strength policy validation
+	if err := s.PasswordPolicy.Validate(req.Password, req.Email); err != nil {
+		return RegisterResponse{nil}, err
+	}
 
 	// Hash password
 	hashedPasswd, err := argon2id.CreateHash(req.Password, argon2id.DefaultParams)
@@ -26,37 +32,24 @@ func (s *Service) Register(ctx context.Context, req RegisterRequest) (RegisterRe
 		return RegisterResponse{nil}, err
 	}
 
-	// Generate OTP and send email for validation
-	// otp, err := generateOTP()
-	// if err != nil {
-	// 	return RegisterResponse{nil}, err
-	// }
-	// otpExpiration := int(time.Now().Add(5 * time.Minute).Unix())
-
 	user := &user.User{
 		Email:    req.Email,
 		Password: hashedPasswd,
-		// VerificationCode:           &otp,
-		// VerificationCodeExpiration: &otpExpiration,
 	}
-	err = s.UserRepo.Insert(ctx, user)
+
+	// Generate OTP for email verification
+	otp, err := s.setVerificationCode(user)
 	if err != nil {
 		return RegisterResponse{nil}, err
 	}
-	return RegisterResponse{user}, err
-}
-
-// func generateOTP() (otp string, err error) {
-// 	length := 6
-// 	maxInt := big.NewInt(9)
 
-// 	for i := 0; i < length; i++ {
-// 		number, err := rand.Int(rand.Reader, maxInt)
-// 		if err != nil {
-// 			return "", err
-// 		}
-// 		otp += number.String()
-// 	}
+	err = s.UserRepo.Insert(ctx, user)
+	if err != nil {
+		return RegisterResponse{nil}, err
+	}
 
-// 	return otp, nil
-// }
+	if err := s.sendVerificationCode(ctx, user, otp); err != nil {
+		return RegisterResponse{user}, errors.Join(ErrVerificationNotSent, err)
+	}
+	return RegisterResponse{user}, nil
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+
+	"auth/internal/client"
+	"auth/internal/user"
+)
+
+type RenewSessionRequest struct {
+	User *user.User
+	// Authentication methods of the current session, kept by the renewed one
+	AMR []string
+	// Scopes and client of the current session, kept by the renewed one so that it is not widened
+	Scope    []string
+	ClientID string
+}
+
+type RenewSessionResponse struct {
+	GenerateTokenResponse
+	RefreshToken string
+}
+
+// RenewSession signs the user out of every other session by revoking all of their refresh tokens,
+// and issues a new token pair for the current one. It is meant to follow a password change,
+// after which the user already carries its new token version.
+func (s *Service) RenewSession(ctx context.Context, req RenewSessionRequest) (RenewSessionResponse, error) {
+	if err := s.Repo.RevokeUserRefreshTokens(ctx, req.User.ID); err != nil {
+		return RenewSessionResponse{}, err
+	}
+
+	var c *client.Client
+	if req.ClientID != "" {
+		var err error
+		if c, err = s.ClientRepo.FindByID(ctx, req.ClientID); err != nil {
+			return RenewSessionResponse{}, err
+		}
+	}
+
+	token, err := s.GenerateToken(ctx, GenerateTokenRequest{
+		UserID:       req.User.ID,
+		TokenVersion: req.User.TokenVersion,
+		AMR:          req.AMR,
+		Client:       c,
+		Scope:        req.Scope,
+		Admin:        s.isAdmin(req.User),
+	})
+	if err != nil {
+		return RenewSessionResponse{}, err
+	}
+
+	refresh, err := s.IssueRefreshToken(ctx, IssueRefreshTokenRequest{UserID: req.User.ID, AMR: req.AMR, Client: c, Scope: req.Scope})
+	if err != nil {
+		return RenewSessionResponse{}, err
+	}
+
+	return RenewSessionResponse{token, refresh.RefreshToken}, nil
+}
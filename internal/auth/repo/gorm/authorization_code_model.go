@@ -0,0 +1,52 @@
+package gorm
+
+import (
+	"strings"
+	"time"
+
+	"auth/internal/auth"
+	clientrepo "auth/internal/client/repo/gorm"
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type AuthorizationCodeModel struct {
+	ID            uuid.UUID               `gorm:"type:uuid;default:uuid_generate_v4()"`
+	ClientID      string                  `gorm:"not null;index"`
+	Client        *clientrepo.ClientModel `gorm:"constraint:OnDelete:CASCADE"`
+	UserID        uuid.UUID               `gorm:"type:uuid;not null;index"`
+	User          *userrepo.UserModel     `gorm:"constraint:OnDelete:CASCADE"`
+	Hash          string                  `gorm:"not null;unique"`
+	RedirectURI   string                  `gorm:"not null"`
+	Scope         string                  `gorm:"not null;default:''"`
+	CodeChallenge string                  `gorm:"not null"`
+	Nonce         string                  `gorm:"not null;default:''"`
+	AMR           string                  `gorm:"not null;default:''"`
+	AuthTime      time.Time               `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	ExpiresAt     time.Time               `gorm:"not null"`
+	UsedAt        *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (*AuthorizationCodeModel) TableName() string {
+	return "AuthorizationCode"
+}
+
+func (model *AuthorizationCodeModel) toAuthorizationCode() *auth.AuthorizationCode {
+	return &auth.AuthorizationCode{
+		ID:            model.ID,
+		ClientID:      model.ClientID,
+		UserID:        model.UserID,
+		Hash:          model.Hash,
+		RedirectURI:   model.RedirectURI,
+		Scope:         strings.Fields(model.Scope),
+		CodeChallenge: model.CodeChallenge,
+		Nonce:         model.Nonce,
+		AMR:           strings.Fields(model.AMR),
+		AuthTime:      model.AuthTime,
+		ExpiresAt:     model.ExpiresAt,
+		UsedAt:        model.UsedAt,
+		CreatedAt:     model.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileCancelEmailChange = "cancel_email_change.go"
+
+func (db *DB) CancelEmailChange(ctx context.Context, cancelHash string, now time.Time) error {
+	const self = "CancelEmailChange"
+
+	result := db.Where("cancel_hash = ? AND expires_at > ?", cancelHash, now).Delete(&EmailChangeModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileCancelEmailChange, self, "failed to cancel email change", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrEmailChangeNotFound
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileCancelEmailChange, self, "canceled email change", nil))
+
+	return nil
+}
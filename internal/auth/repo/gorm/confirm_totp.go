@@ -0,0 +1,38 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileConfirmTOTP = "confirm_totp.go"
+
+func (db *DB) ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64, now time.Time) error {
+	const self = "ConfirmTOTP"
+
+	result := db.
+		Model(&TOTPModel{}).
+		Where("user_id = ? AND confirmed_at IS NULL AND last_used_step < ?", userID, step).
+		Updates(map[string]any{
+			"confirmed_at":   now,
+			"last_used_step": step,
+			"updated_at":     now,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileConfirmTOTP, self, "failed to confirm totp", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrTOTPAlreadyEnabled
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileConfirmTOTP, self, fmt.Sprintf("enabled totp of user %q", userID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileCountEmailChangeAttempt = "count_email_change_attempt.go"
+
+// CountEmailChangeAttempt increments the attempts of the email change and returns the new count.
+func (db *DB) CountEmailChangeAttempt(ctx context.Context, id uuid.UUID) (int, error) {
+	const self = "CountEmailChangeAttempt"
+
+	var models []EmailChangeModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileCountEmailChangeAttempt, self, "failed to count email change attempt", result.Error))
+		return 0, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return 0, auth.ErrEmailChangeNotFound
+	}
+
+	return models[0].Attempts, nil
+}
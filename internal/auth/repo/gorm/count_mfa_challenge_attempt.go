@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileCountMFAChallengeAttempt = "count_mfa_challenge_attempt.go"
+
+// CountMFAChallengeAttempt increments the attempts of the challenge and returns the new count.
+func (db *DB) CountMFAChallengeAttempt(ctx context.Context, id uuid.UUID) (int, error) {
+	const self = "CountMFAChallengeAttempt"
+
+	var models []MFAChallengeModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileCountMFAChallengeAttempt, self, "failed to count mfa attempt", result.Error))
+		return 0, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return 0, auth.ErrInvalidMFAToken
+	}
+
+	return models[0].Attempts, nil
+}
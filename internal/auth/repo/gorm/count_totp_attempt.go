@@ -0,0 +1,39 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileCountTOTPAttempt = "count_totp_attempt.go"
+
+func (db *DB) CountTOTPAttempt(ctx context.Context, userID uuid.UUID, now, windowStart time.Time) (int, error) {
+	const self = "CountTOTPAttempt"
+
+	var models []TOTPModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL", userID).
+		Updates(map[string]any{
+			"attempts":        gorm.Expr("CASE WHEN last_attempt_at IS NULL OR last_attempt_at < ? THEN 1 ELSE attempts + 1 END", windowStart),
+			"last_attempt_at": now,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileCountTOTPAttempt, self, "failed to count totp attempt", result.Error))
+		return 0, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return 0, auth.ErrTOTPNotEnabled
+	}
+
+	return models[0].Attempts, nil
+}
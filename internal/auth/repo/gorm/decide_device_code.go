@@ -0,0 +1,44 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm/clause"
+)
+
+const FileDecideDeviceCode = "decide_device_code.go"
+
+func (db *DB) DecideDeviceCode(ctx context.Context, userCodeHash string, decided *auth.DeviceCode, now time.Time) (*auth.DeviceCode, error) {
+	const self = "DecideDeviceCode"
+
+	// Only a pending code is updated, so that a decision cannot be overturned
+	var models []DeviceCodeModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("user_code_hash = ? AND status = ? AND expires_at > ?", userCodeHash, auth.DeviceCodePending, now).
+		Updates(map[string]any{
+			"status":    decided.Status,
+			"user_id":   decided.UserID,
+			"amr":       strings.Join(decided.AMR, " "),
+			"auth_time": decided.AuthTime,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDecideDeviceCode, self, "failed to decide device code", result.Error))
+		return nil, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return nil, auth.ErrInvalidUserCode
+	}
+	model := models[0]
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDecideDeviceCode, self, fmt.Sprintf("device code with id %q was %s", model.ID.String(), model.Status), nil))
+
+	return model.toDeviceCode(), nil
+}
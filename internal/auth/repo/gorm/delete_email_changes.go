@@ -0,0 +1,26 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileDeleteEmailChanges = "delete_email_changes.go"
+
+func (db *DB) DeleteEmailChanges(ctx context.Context, userID uuid.UUID) error {
+	const self = "DeleteEmailChanges"
+
+	result := db.Where("user_id = ?", userID).Delete(&EmailChangeModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteEmailChanges, self, "failed to delete email changes", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteEmailChanges, self, fmt.Sprintf("deleted %d email changes of user_id %q", result.RowsAffected, userID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileDeleteExpiredRevokedTokens = "delete_expired_revoked_tokens.go"
+
+func (db *DB) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error) {
+	const self = "DeleteExpiredRevokedTokens"
+
+	result := db.Where("expires_at <= ?", now).Delete(&RevokedTokenModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteExpiredRevokedTokens, self, "failed to delete expired revoked tokens", result.Error))
+		return 0, auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteExpiredRevokedTokens, self, fmt.Sprintf("deleted %d expired revoked tokens", result.RowsAffected), nil))
+
+	return result.RowsAffected, nil
+}
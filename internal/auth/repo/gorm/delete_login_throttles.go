@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileDeleteLoginThrottles = "delete_login_throttles.go"
+
+func (db *DB) DeleteLoginThrottles(ctx context.Context, keys ...string) (int64, error) {
+	const self = "DeleteLoginThrottles"
+
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	result := db.Where("key IN ?", keys).Delete(&LoginThrottleModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteLoginThrottles, self, "failed to delete login throttles", result.Error))
+		return 0, auth.ErrInternal
+	}
+	if result.RowsAffected > 0 {
+		db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteLoginThrottles, self, fmt.Sprintf("cleared failed logins of %q", keys), nil))
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,26 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileDeletePasswordResetTokens = "delete_password_reset_tokens.go"
+
+func (db *DB) DeletePasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
+	const self = "DeletePasswordResetTokens"
+
+	result := db.Where("user_id = ?", userID).Delete(&PasswordResetTokenModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeletePasswordResetTokens, self, "failed to delete password reset tokens", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeletePasswordResetTokens, self, fmt.Sprintf("deleted %d password reset tokens of user_id %q", result.RowsAffected, userID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileDeleteStaleLoginThrottles = "delete_stale_login_throttles.go"
+
+func (db *DB) DeleteStaleLoginThrottles(ctx context.Context, before time.Time) (int64, error) {
+	const self = "DeleteStaleLoginThrottles"
+
+	result := db.
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, before).
+		Delete(&LoginThrottleModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteStaleLoginThrottles, self, "failed to delete stale login throttles", result.Error))
+		return 0, auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteStaleLoginThrottles, self, fmt.Sprintf("deleted %d stale login throttles", result.RowsAffected), nil))
+
+	return result.RowsAffected, nil
+}
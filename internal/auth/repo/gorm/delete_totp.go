@@ -0,0 +1,32 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileDeleteTOTP = "delete_totp.go"
+
+func (db *DB) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	const self = "DeleteTOTP"
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&TOTPModel{}).Error
+	})
+	if err != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteTOTP, self, "failed to delete totp", err))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteTOTP, self, fmt.Sprintf("disabled totp of user %q", userID.String()), nil))
+
+	return nil
+}
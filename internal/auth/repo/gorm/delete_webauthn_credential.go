@@ -0,0 +1,31 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileDeleteWebAuthnCredential = "delete_webauthn_credential.go"
+
+func (db *DB) DeleteWebAuthnCredential(ctx context.Context, userID uuid.UUID, id []byte) error {
+	const self = "DeleteWebAuthnCredential"
+
+	// Scoping by user keeps users from deleting the passkeys of others
+	result := db.Where("id = ? AND user_id = ?", id, userID).Delete(&WebAuthnCredentialModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteWebAuthnCredential, self, "failed to delete webauthn credential", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrWebAuthnCredentialNotFound
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteWebAuthnCredential, self, fmt.Sprintf("deleted webauthn credential of user %q", userID.String()), nil))
+
+	return nil
+}
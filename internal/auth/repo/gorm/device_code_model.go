@@ -0,0 +1,54 @@
+package gorm
+
+import (
+	"strings"
+	"time"
+
+	"auth/internal/auth"
+	clientrepo "auth/internal/client/repo/gorm"
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type DeviceCodeModel struct {
+	ID           uuid.UUID               `gorm:"type:uuid;default:uuid_generate_v4()"`
+	ClientID     string                  `gorm:"not null;index"`
+	Client       *clientrepo.ClientModel `gorm:"constraint:OnDelete:CASCADE"`
+	Hash         string                  `gorm:"not null;unique"`
+	UserCodeHash string                  `gorm:"not null;unique"`
+	Scope        string                  `gorm:"not null;default:''"`
+	Status       string                  `gorm:"not null;default:'pending'"`
+	UserID       *uuid.UUID              `gorm:"type:uuid;index"`
+	User         *userrepo.UserModel     `gorm:"constraint:OnDelete:CASCADE"`
+	AMR          string                  `gorm:"not null;default:''"`
+	AuthTime     *time.Time
+	PollInterval int       `gorm:"not null"`
+	NextPollAt   time.Time `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	UsedAt       *time.Time
+	CreatedAt    time.Time `gorm:"not null"`
+}
+
+func (*DeviceCodeModel) TableName() string {
+	return "DeviceCode"
+}
+
+func (model *DeviceCodeModel) toDeviceCode() *auth.DeviceCode {
+	return &auth.DeviceCode{
+		ID:           model.ID,
+		ClientID:     model.ClientID,
+		Hash:         model.Hash,
+		UserCodeHash: model.UserCodeHash,
+		Scope:        strings.Fields(model.Scope),
+		Status:       model.Status,
+		UserID:       model.UserID,
+		AMR:          strings.Fields(model.AMR),
+		AuthTime:     model.AuthTime,
+		PollInterval: model.PollInterval,
+		NextPollAt:   model.NextPollAt,
+		ExpiresAt:    model.ExpiresAt,
+		UsedAt:       model.UsedAt,
+		CreatedAt:    model.CreatedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type EmailChangeModel struct {
+	ID         uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()"`
+	UserID     uuid.UUID           `gorm:"type:uuid;not null;index"`
+	User       *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Email      string              `gorm:"not null"`
+	CodeHash   string              `gorm:"not null"`
+	CancelHash string              `gorm:"not null;unique"`
+	Attempts   int                 `gorm:"not null;default:0"`
+	ExpiresAt  time.Time           `gorm:"not null"`
+	CreatedAt  time.Time           `gorm:"not null"`
+}
+
+func (*EmailChangeModel) TableName() string {
+	return "EmailChange"
+}
@@ -0,0 +1,35 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindDeviceCodeByHash = "find_device_code_by_hash.go"
+
+func (db *DB) FindDeviceCodeByHash(ctx context.Context, hash string) (*auth.DeviceCode, error) {
+	const self = "FindDeviceCodeByHash"
+	span := trace.SpanFromContext(ctx)
+
+	var model DeviceCodeModel
+	result := db.Where("hash = ?", hash).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrInvalidDeviceCode
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindDeviceCodeByHash, self, auth.ErrInvalidDeviceCode.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned device_code_id %q", model.ID.String()))
+
+	return model.toDeviceCode(), nil
+}
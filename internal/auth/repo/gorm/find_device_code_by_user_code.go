@@ -0,0 +1,35 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindDeviceCodeByUserCode = "find_device_code_by_user_code.go"
+
+func (db *DB) FindDeviceCodeByUserCode(ctx context.Context, userCodeHash string) (*auth.DeviceCode, error) {
+	const self = "FindDeviceCodeByUserCode"
+	span := trace.SpanFromContext(ctx)
+
+	var model DeviceCodeModel
+	result := db.Where("user_code_hash = ?", userCodeHash).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrInvalidUserCode
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindDeviceCodeByUserCode, self, auth.ErrInvalidUserCode.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned device_code_id %q", model.ID.String()))
+
+	return model.toDeviceCode(), nil
+}
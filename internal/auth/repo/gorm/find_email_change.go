@@ -0,0 +1,42 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileFindEmailChange = "find_email_change.go"
+
+// FindEmailChange returns the pending email change of the user, unless it has expired.
+func (db *DB) FindEmailChange(ctx context.Context, userID uuid.UUID, now time.Time) (*auth.EmailChange, error) {
+	const self = "FindEmailChange"
+
+	var model EmailChangeModel
+	result := db.Where("user_id = ? AND expires_at > ?", userID, now).Order("created_at DESC").First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrEmailChangeNotFound
+		default:
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindEmailChange, self, auth.ErrEmailChangeNotFound.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+
+	return &auth.EmailChange{
+		ID:         model.ID,
+		UserID:     model.UserID,
+		Email:      model.Email,
+		CodeHash:   model.CodeHash,
+		CancelHash: model.CancelHash,
+		Attempts:   model.Attempts,
+		ExpiresAt:  model.ExpiresAt,
+		CreatedAt:  model.CreatedAt,
+	}, nil
+}
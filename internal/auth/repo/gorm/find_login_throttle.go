@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm"
+)
+
+const FileFindLoginThrottle = "find_login_throttle.go"
+
+// FindLoginThrottle returns nil when no failed login has been recorded for the key.
+func (db *DB) FindLoginThrottle(ctx context.Context, key string) (*auth.LoginThrottle, error) {
+	const self = "FindLoginThrottle"
+
+	var model LoginThrottleModel
+	result := db.Where("key = ?", key).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, nil
+		default:
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindLoginThrottle, self, "failed to query login throttle", result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+
+	return &auth.LoginThrottle{
+		Key:           model.Key,
+		Failures:      model.Failures,
+		LastFailureAt: model.LastFailureAt,
+		LockedUntil:   model.LockedUntil,
+		CreatedAt:     model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindMFAChallengeByHash = "find_mfa_challenge_by_hash.go"
+
+func (db *DB) FindMFAChallengeByHash(ctx context.Context, hash string) (*auth.MFAChallenge, error) {
+	const self = "FindMFAChallengeByHash"
+	span := trace.SpanFromContext(ctx)
+
+	var model MFAChallengeModel
+	result := db.Where("hash = ?", hash).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrInvalidMFAToken
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindMFAChallengeByHash, self, auth.ErrInvalidMFAToken.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned mfa_challenge_id %q", model.ID.String()))
+
+	return &auth.MFAChallenge{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Hash:      model.Hash,
+		Attempts:  model.Attempts,
+		Scope:     strings.Fields(model.Scope),
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindPasswordResetTokenByHash = "find_password_reset_token_by_hash.go"
+
+func (db *DB) FindPasswordResetTokenByHash(ctx context.Context, hash string) (*auth.PasswordResetToken, error) {
+	const self = "FindPasswordResetTokenByHash"
+	span := trace.SpanFromContext(ctx)
+
+	var model PasswordResetTokenModel
+	result := db.Where("hash = ?", hash).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrInvalidResetToken
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindPasswordResetTokenByHash, self, auth.ErrInvalidResetToken.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned password_reset_token_id %q", model.ID.String()))
+
+	return &auth.PasswordResetToken{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Hash:      model.Hash,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindRefreshTokenByHash = "find_refresh_token_by_hash.go"
+
+func (db *DB) FindRefreshTokenByHash(ctx context.Context, hash string) (*auth.RefreshToken, error) {
+	const self = "FindRefreshTokenByHash"
+	span := trace.SpanFromContext(ctx)
+
+	var model RefreshTokenModel
+	result := db.Where("hash = ?", hash).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrRefreshTokenNotFound
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindRefreshTokenByHash, self, auth.ErrRefreshTokenNotFound.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned refresh_token_id %q", model.ID.String()))
+
+	return &auth.RefreshToken{
+		ID:        model.ID,
+		FamilyID:  model.FamilyID,
+		UserID:    model.UserID,
+		Hash:      model.Hash,
+		AMR:       strings.Fields(model.AMR),
+		ClientID:  model.ClientID,
+		Scope:     strings.Fields(model.Scope),
+		ExpiresAt: model.ExpiresAt,
+		RotatedAt: model.RotatedAt,
+		RevokedAt: model.RevokedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
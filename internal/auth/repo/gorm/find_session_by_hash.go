@@ -0,0 +1,44 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindSessionByHash = "find_session_by_hash.go"
+
+func (db *DB) FindSessionByHash(ctx context.Context, hash string) (*auth.Session, error) {
+	const self = "FindSessionByHash"
+	span := trace.SpanFromContext(ctx)
+
+	var model SessionModel
+	result := db.Where("hash = ?", hash).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrInvalidSession
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindSessionByHash, self, auth.ErrInvalidSession.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned session_id %q", model.ID.String()))
+
+	return &auth.Session{
+		ID:           model.ID,
+		UserID:       model.UserID,
+		Hash:         model.Hash,
+		TokenVersion: model.TokenVersion,
+		AMR:          strings.Fields(model.AMR),
+		ExpiresAt:    model.ExpiresAt,
+		CreatedAt:    model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileFindTOTP = "find_totp.go"
+
+func (db *DB) FindTOTP(ctx context.Context, userID uuid.UUID) (*auth.TOTP, error) {
+	const self = "FindTOTP"
+
+	var model TOTPModel
+	result := db.Where("user_id = ?", userID).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrTOTPNotFound
+		default:
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindTOTP, self, auth.ErrTOTPNotFound.Error(), result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+
+	return &auth.TOTP{
+		UserID:       model.UserID,
+		Secret:       model.Secret,
+		ConfirmedAt:  model.ConfirmedAt,
+		LastUsedStep: model.LastUsedStep,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package gorm
+
+import (
+	"log/slog"
+
+	"auth/internal/auth"
+
+	"gorm.io/gorm"
+)
+
+const (
+	Path string = "auth/internal/auth/repo/gorm"
+)
+
+type DB struct {
+	*gorm.DB
+	logger *slog.Logger
+}
+
+func NewRepo(db *gorm.DB, logger *slog.Logger) auth.Repoer {
+	return &DB{db, logger}
+}
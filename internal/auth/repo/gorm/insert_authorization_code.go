@@ -0,0 +1,43 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertAuthorizationCode = "insert_authorization_code.go"
+
+func (db *DB) InsertAuthorizationCode(ctx context.Context, c *auth.AuthorizationCode) error {
+	const self = "InsertAuthorizationCode"
+
+	model := &AuthorizationCodeModel{
+		ID:            c.ID,
+		ClientID:      c.ClientID,
+		UserID:        c.UserID,
+		Hash:          c.Hash,
+		RedirectURI:   c.RedirectURI,
+		Scope:         strings.Join(c.Scope, " "),
+		CodeChallenge: c.CodeChallenge,
+		Nonce:         c.Nonce,
+		AMR:           strings.Join(c.AMR, " "),
+		AuthTime:      c.AuthTime,
+		ExpiresAt:     c.ExpiresAt,
+		UsedAt:        c.UsedAt,
+		CreatedAt:     c.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertAuthorizationCode, self, "failed to create authorization code", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertAuthorizationCode, self, fmt.Sprintf("created authorization code with id %q for client %q", model.ID.String(), model.ClientID), nil))
+
+	c.ID = model.ID
+
+	return nil
+}
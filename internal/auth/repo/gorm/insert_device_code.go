@@ -0,0 +1,44 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertDeviceCode = "insert_device_code.go"
+
+func (db *DB) InsertDeviceCode(ctx context.Context, c *auth.DeviceCode) error {
+	const self = "InsertDeviceCode"
+
+	model := &DeviceCodeModel{
+		ID:           c.ID,
+		ClientID:     c.ClientID,
+		Hash:         c.Hash,
+		UserCodeHash: c.UserCodeHash,
+		Scope:        strings.Join(c.Scope, " "),
+		Status:       c.Status,
+		UserID:       c.UserID,
+		AMR:          strings.Join(c.AMR, " "),
+		AuthTime:     c.AuthTime,
+		PollInterval: c.PollInterval,
+		NextPollAt:   c.NextPollAt,
+		ExpiresAt:    c.ExpiresAt,
+		UsedAt:       c.UsedAt,
+		CreatedAt:    c.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertDeviceCode, self, "failed to create device code", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertDeviceCode, self, fmt.Sprintf("created device code with id %q for client %q", model.ID.String(), model.ClientID), nil))
+
+	c.ID = model.ID
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertEmailChange = "insert_email_change.go"
+
+func (db *DB) InsertEmailChange(ctx context.Context, c *auth.EmailChange) error {
+	const self = "InsertEmailChange"
+
+	model := &EmailChangeModel{
+		UserID:     c.UserID,
+		Email:      c.Email,
+		CodeHash:   c.CodeHash,
+		CancelHash: c.CancelHash,
+		ExpiresAt:  c.ExpiresAt,
+		CreatedAt:  c.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertEmailChange, self, "failed to insert email change", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertEmailChange, self, fmt.Sprintf("started email change for user_id %q", model.UserID.String()), nil))
+
+	c.ID = model.ID
+	return nil
+}
@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertMFAChallenge = "insert_mfa_challenge.go"
+
+func (db *DB) InsertMFAChallenge(ctx context.Context, c *auth.MFAChallenge) error {
+	const self = "InsertMFAChallenge"
+
+	model := &MFAChallengeModel{
+		UserID:    c.UserID,
+		Hash:      c.Hash,
+		Attempts:  c.Attempts,
+		Scope:     strings.Join(c.Scope, " "),
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		CreatedAt: c.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertMFAChallenge, self, "failed to create mfa challenge", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertMFAChallenge, self, fmt.Sprintf("created mfa challenge with id %q for user %q", model.ID.String(), model.UserID.String()), nil))
+
+	c.ID = model.ID
+	return nil
+}
@@ -0,0 +1,32 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertPasswordResetToken = "insert_password_reset_token.go"
+
+func (db *DB) InsertPasswordResetToken(ctx context.Context, t *auth.PasswordResetToken) error {
+	const self = "InsertPasswordResetToken"
+
+	model := &PasswordResetTokenModel{
+		UserID:    t.UserID,
+		Hash:      t.Hash,
+		ExpiresAt: t.ExpiresAt,
+		CreatedAt: t.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertPasswordResetToken, self, "failed to insert password reset token", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertPasswordResetToken, self, fmt.Sprintf("issued password reset token for user_id %q", model.UserID.String()), nil))
+
+	t.ID = model.ID
+	return nil
+}
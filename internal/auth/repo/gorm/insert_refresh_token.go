@@ -0,0 +1,42 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertRefreshToken = "insert_refresh_token.go"
+
+func (db *DB) InsertRefreshToken(ctx context.Context, t *auth.RefreshToken) error {
+	const self = "InsertRefreshToken"
+
+	model := &RefreshTokenModel{
+		ID:        t.ID,
+		FamilyID:  t.FamilyID,
+		UserID:    t.UserID,
+		Hash:      t.Hash,
+		AMR:       strings.Join(t.AMR, " "),
+		ClientID:  t.ClientID,
+		Scope:     strings.Join(t.Scope, " "),
+		ExpiresAt: t.ExpiresAt,
+		RotatedAt: t.RotatedAt,
+		RevokedAt: t.RevokedAt,
+		CreatedAt: t.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertRefreshToken, self, "failed to create refresh token", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertRefreshToken, self, fmt.Sprintf("created refresh token with id %q in family %q", model.ID.String(), model.FamilyID.String()), nil))
+
+	t.ID = model.ID
+	t.CreatedAt = model.CreatedAt
+
+	return nil
+}
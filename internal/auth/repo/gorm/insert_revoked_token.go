@@ -0,0 +1,33 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm/clause"
+)
+
+const FileInsertRevokedToken = "insert_revoked_token.go"
+
+func (db *DB) InsertRevokedToken(ctx context.Context, t *auth.RevokedToken) error {
+	const self = "InsertRevokedToken"
+
+	model := &RevokedTokenModel{
+		JTI:       t.JTI,
+		ExpiresAt: t.ExpiresAt,
+		CreatedAt: t.CreatedAt,
+	}
+
+	// Revoking a token twice is not an error
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertRevokedToken, self, "failed to revoke token", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertRevokedToken, self, fmt.Sprintf("revoked token with jti %q", model.JTI), nil))
+
+	return nil
+}
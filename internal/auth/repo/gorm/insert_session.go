@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertSession = "insert_session.go"
+
+func (db *DB) InsertSession(ctx context.Context, s *auth.Session) error {
+	const self = "InsertSession"
+
+	model := &SessionModel{
+		ID:           s.ID,
+		UserID:       s.UserID,
+		Hash:         s.Hash,
+		TokenVersion: s.TokenVersion,
+		AMR:          strings.Join(s.AMR, " "),
+		ExpiresAt:    s.ExpiresAt,
+		CreatedAt:    s.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertSession, self, "failed to create session", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertSession, self, fmt.Sprintf("created session with id %q for user %q", model.ID.String(), model.UserID.String()), nil))
+
+	s.ID = model.ID
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileInsertWebAuthnCeremony = "insert_webauthn_ceremony.go"
+
+func (db *DB) InsertWebAuthnCeremony(ctx context.Context, c *auth.WebAuthnCeremony) error {
+	const self = "InsertWebAuthnCeremony"
+
+	model := &WebAuthnCeremonyModel{
+		UserID:    c.UserID,
+		Hash:      c.Hash,
+		Kind:      c.Kind,
+		Session:   c.Session,
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		CreatedAt: c.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertWebAuthnCeremony, self, "failed to create webauthn ceremony", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertWebAuthnCeremony, self, fmt.Sprintf("created webauthn %s ceremony with id %q", model.Kind, model.ID.String()), nil))
+
+	c.ID = model.ID
+	return nil
+}
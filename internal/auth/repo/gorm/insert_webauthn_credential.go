@@ -0,0 +1,47 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const FileInsertWebAuthnCredential = "insert_webauthn_credential.go"
+
+func (db *DB) InsertWebAuthnCredential(ctx context.Context, c *auth.WebAuthnCredential) error {
+	const self = "InsertWebAuthnCredential"
+
+	model := &WebAuthnCredentialModel{
+		ID:              c.ID,
+		UserID:          c.UserID,
+		Name:            c.Name,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		AAGUID:          c.AAGUID,
+		SignCount:       int64(c.SignCount),
+		Transports:      strings.Join(c.Transports, " "),
+		BackupEligible:  c.BackupEligible,
+		BackupState:     c.BackupState,
+		LastUsedAt:      c.LastUsedAt,
+		CreatedAt:       c.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertWebAuthnCredential, self, "failed to create webauthn credential", result.Error))
+		var pgErr *pgconn.PgError
+		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
+			return auth.ErrWebAuthnCredentialExists
+		}
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertWebAuthnCredential, self, fmt.Sprintf("created webauthn credential for user %q", model.UserID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileIsTokenRevoked = "is_token_revoked.go"
+
+func (db *DB) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	const self = "IsTokenRevoked"
+
+	var count int64
+	result := db.Model(&RevokedTokenModel{}).Where("jti = ? AND expires_at > ?", jti, time.Now()).Count(&count)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileIsTokenRevoked, self, "failed to query token denylist", result.Error))
+		return false, auth.ErrInternal
+	}
+
+	return count > 0, nil
+}
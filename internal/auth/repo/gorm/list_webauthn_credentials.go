@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const FileListWebAuthnCredentials = "list_webauthn_credentials.go"
+
+func (db *DB) ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]*auth.WebAuthnCredential, error) {
+	const self = "ListWebAuthnCredentials"
+	span := trace.SpanFromContext(ctx)
+
+	var models []WebAuthnCredentialModel
+	result := db.Where("user_id = ?", userID).Order("created_at").Find(&models)
+	if result.Error != nil {
+		span.AddEvent("db query failed")
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileListWebAuthnCredentials, self, "failed to list webauthn credentials", result.Error))
+		return nil, auth.ErrInternal
+	}
+	span.AddEvent(fmt.Sprintf("db query returned %d webauthn credentials", len(models)))
+
+	credentials := make([]*auth.WebAuthnCredential, 0, len(models))
+	for i := range models {
+		credentials = append(credentials, models[i].toCredential())
+	}
+	return credentials, nil
+}
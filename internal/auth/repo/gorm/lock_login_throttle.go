@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileLockLoginThrottle = "lock_login_throttle.go"
+
+func (db *DB) LockLoginThrottle(ctx context.Context, key string, until time.Time) error {
+	const self = "LockLoginThrottle"
+
+	result := db.Model(&LoginThrottleModel{}).Where("key = ?", key).Update("locked_until", until)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileLockLoginThrottle, self, "failed to lock logins", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileLockLoginThrottle, self, fmt.Sprintf("locked logins of %q until %s", key, until.Format(time.RFC3339)), nil))
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package gorm
+
+import (
+	"time"
+)
+
+type LoginThrottleModel struct {
+	Key           string    `gorm:"primaryKey"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null;index"`
+	LockedUntil   *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (*LoginThrottleModel) TableName() string {
+	return "LoginThrottle"
+}
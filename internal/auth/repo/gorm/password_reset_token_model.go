@@ -0,0 +1,23 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetTokenModel struct {
+	ID        uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()"`
+	UserID    uuid.UUID           `gorm:"type:uuid;not null;index"`
+	User      *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Hash      string              `gorm:"not null;unique"`
+	ExpiresAt time.Time           `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (*PasswordResetTokenModel) TableName() string {
+	return "PasswordResetToken"
+}
@@ -0,0 +1,29 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FilePollDeviceCode = "poll_device_code.go"
+
+func (db *DB) PollDeviceCode(ctx context.Context, id uuid.UUID, previous, next time.Time) (bool, error) {
+	const self = "PollDeviceCode"
+
+	// Checking the previous value makes concurrent polls count as a single one
+	result := db.
+		Model(&DeviceCodeModel{}).
+		Where("id = ? AND next_poll_at = ?", id, previous).
+		Update("next_poll_at", next)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FilePollDeviceCode, self, "failed to poll device code", result.Error))
+		return false, auth.ErrInternal
+	}
+
+	return result.RowsAffected > 0, nil
+}
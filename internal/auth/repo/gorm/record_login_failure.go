@@ -0,0 +1,50 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileRecordLoginFailure = "record_login_failure.go"
+
+func (db *DB) RecordLoginFailure(ctx context.Context, key string, now, windowStart time.Time) (*auth.LoginThrottle, error) {
+	const self = "RecordLoginFailure"
+
+	model := &LoginThrottleModel{
+		Key:           key,
+		Failures:      1,
+		LastFailureAt: now,
+		CreatedAt:     now,
+	}
+
+	// Incrementing in the upsert keeps the count right across replicas.
+	// The count starts over once the last failure and lockout are older than the window.
+	result := db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"failures":        gorm.Expr(`CASE WHEN GREATEST("LoginThrottle".last_failure_at, COALESCE("LoginThrottle".locked_until, "LoginThrottle".last_failure_at)) < ? THEN 1 ELSE "LoginThrottle".failures + 1 END`, windowStart),
+				"last_failure_at": now,
+			}),
+		},
+		clause.Returning{},
+	).Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileRecordLoginFailure, self, "failed to record login failure", result.Error))
+		return nil, auth.ErrInternal
+	}
+
+	return &auth.LoginThrottle{
+		Key:           model.Key,
+		Failures:      model.Failures,
+		LastFailureAt: model.LastFailureAt,
+		LockedUntil:   model.LockedUntil,
+		CreatedAt:     model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type RecoveryCodeModel struct {
+	ID        uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()"`
+	UserID    uuid.UUID           `gorm:"type:uuid;not null;index"`
+	User      *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Hash      string              `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (*RecoveryCodeModel) TableName() string {
+	return "RecoveryCode"
+}
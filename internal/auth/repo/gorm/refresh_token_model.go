@@ -0,0 +1,28 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type RefreshTokenModel struct {
+	ID        uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()"`
+	FamilyID  uuid.UUID           `gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID           `gorm:"type:uuid;not null;index"`
+	User      *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Hash      string              `gorm:"not null;unique"`
+	AMR       string              `gorm:"not null;default:''"`
+	ClientID  string              `gorm:"not null;default:''"`
+	Scope     string              `gorm:"not null;default:''"`
+	ExpiresAt time.Time           `gorm:"not null"`
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (*RefreshTokenModel) TableName() string {
+	return "RefreshToken"
+}
@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileReplaceRecoveryCodes = "replace_recovery_codes.go"
+
+func (db *DB) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []*auth.RecoveryCode) error {
+	const self = "ReplaceRecoveryCodes"
+
+	models := make([]*RecoveryCodeModel, 0, len(codes))
+	for _, c := range codes {
+		models = append(models, &RecoveryCodeModel{
+			ID:        c.ID,
+			UserID:    userID,
+			Hash:      c.Hash,
+			UsedAt:    c.UsedAt,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		return tx.Create(&models).Error
+	})
+	if err != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileReplaceRecoveryCodes, self, "failed to replace recovery codes", err))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileReplaceRecoveryCodes, self, fmt.Sprintf("generated %d recovery codes for user %q", len(models), userID.String()), nil))
+
+	return nil
+}
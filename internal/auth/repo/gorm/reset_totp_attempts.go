@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileResetTOTPAttempts = "reset_totp_attempts.go"
+
+func (db *DB) ResetTOTPAttempts(ctx context.Context, userID uuid.UUID) error {
+	const self = "ResetTOTPAttempts"
+
+	result := db.
+		Model(&TOTPModel{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"attempts":        0,
+			"last_attempt_at": nil,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileResetTOTPAttempts, self, "failed to reset totp attempts", result.Error))
+		return auth.ErrInternal
+	}
+
+	return nil
+}
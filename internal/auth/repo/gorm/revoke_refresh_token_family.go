@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileRevokeRefreshTokenFamily = "revoke_refresh_token_family.go"
+
+func (db *DB) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	const self = "RevokeRefreshTokenFamily"
+
+	result := db.
+		Model(&RefreshTokenModel{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileRevokeRefreshTokenFamily, self, "failed to revoke refresh token family", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.WarnContext(ctx, otel.FormatLog(Path, FileRevokeRefreshTokenFamily, self, fmt.Sprintf("revoked %d refresh tokens from family %q", result.RowsAffected, familyID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileRevokeUserRefreshTokens = "revoke_user_refresh_tokens.go"
+
+func (db *DB) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	const self = "RevokeUserRefreshTokens"
+
+	result := db.
+		Model(&RefreshTokenModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileRevokeUserRefreshTokens, self, "failed to revoke refresh tokens of user", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileRevokeUserRefreshTokens, self, fmt.Sprintf("revoked %d refresh tokens of user_id %q", result.RowsAffected, userID.String()), nil))
+
+	return nil
+}
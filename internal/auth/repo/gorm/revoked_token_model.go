@@ -0,0 +1,15 @@
+package gorm
+
+import (
+	"time"
+)
+
+type RevokedTokenModel struct {
+	JTI       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (*RevokedTokenModel) TableName() string {
+	return "RevokedToken"
+}
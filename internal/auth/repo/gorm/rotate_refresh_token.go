@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileRotateRefreshToken = "rotate_refresh_token.go"
+
+func (db *DB) RotateRefreshToken(ctx context.Context, id uuid.UUID) error {
+	const self = "RotateRefreshToken"
+
+	result := db.
+		Model(&RefreshTokenModel{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", time.Now())
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileRotateRefreshToken, self, "failed to rotate refresh token", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrRefreshTokenNotActive
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileRotateRefreshToken, self, fmt.Sprintf("rotated refresh token with id %q", id.String()), nil))
+
+	return nil
+}
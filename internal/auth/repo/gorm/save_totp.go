@@ -0,0 +1,45 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm/clause"
+)
+
+const FileSaveTOTP = "save_totp.go"
+
+// SaveTOTP creates the factor of the user, or replaces it while it is still pending.
+func (db *DB) SaveTOTP(ctx context.Context, t *auth.TOTP) error {
+	const self = "SaveTOTP"
+
+	model := &TOTPModel{
+		UserID:       t.UserID,
+		Secret:       t.Secret,
+		ConfirmedAt:  t.ConfirmedAt,
+		LastUsedStep: t.LastUsedStep,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "created_at", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: `"TOTP".confirmed_at IS NULL`}}},
+	}).Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileSaveTOTP, self, "failed to save totp", result.Error))
+		return auth.ErrInternal
+	}
+
+	// A concurrent confirmation makes the conflicting row immutable
+	if result.RowsAffected == 0 {
+		return auth.ErrTOTPAlreadyEnabled
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileSaveTOTP, self, fmt.Sprintf("enrolled totp of user %q", model.UserID.String()), nil))
+
+	return nil
+}
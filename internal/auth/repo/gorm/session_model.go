@@ -0,0 +1,24 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type SessionModel struct {
+	ID           uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()"`
+	UserID       uuid.UUID           `gorm:"type:uuid;not null;index"`
+	User         *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Hash         string              `gorm:"not null;unique"`
+	TokenVersion int                 `gorm:"not null;default:0"`
+	AMR          string              `gorm:"not null;default:''"`
+	ExpiresAt    time.Time           `gorm:"not null"`
+	CreatedAt    time.Time           `gorm:"not null"`
+}
+
+func (*SessionModel) TableName() string {
+	return "Session"
+}
@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileSlowDownDeviceCode = "slow_down_device_code.go"
+
+func (db *DB) SlowDownDeviceCode(ctx context.Context, id uuid.UUID, interval int, next time.Time) error {
+	const self = "SlowDownDeviceCode"
+
+	result := db.
+		Model(&DeviceCodeModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"poll_interval": interval, "next_poll_at": next})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileSlowDownDeviceCode, self, "failed to slow down device code", result.Error))
+		return auth.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileSlowDownDeviceCode, self, fmt.Sprintf("device code with id %q now polls every %d seconds", id.String(), interval), nil))
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type TOTPModel struct {
+	UserID       uuid.UUID           `gorm:"type:uuid;primaryKey"`
+	User         *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Secret       string              `gorm:"not null"`
+	ConfirmedAt  *time.Time
+	LastUsedStep int64 `gorm:"not null;default:0"`
+	// Codes checked outside of an mfa challenge, such as to disable the factor
+	Attempts      int `gorm:"not null;default:0"`
+	LastAttemptAt *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+func (*TOTPModel) TableName() string {
+	return "TOTP"
+}
@@ -0,0 +1,35 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+)
+
+const FileUpdateWebAuthnSignCount = "update_webauthn_sign_count.go"
+
+func (db *DB) UpdateWebAuthnSignCount(ctx context.Context, id []byte, signCount uint32, backupState bool, now time.Time) error {
+	const self = "UpdateWebAuthnSignCount"
+
+	// Checking the counter in the same statement that updates it makes sure that
+	// concurrent logins cannot replay the same assertion
+	result := db.
+		Model(&WebAuthnCredentialModel{}).
+		Where("id = ? AND (sign_count < ? OR (sign_count = 0 AND ? = 0))", id, int64(signCount), int64(signCount)).
+		Updates(map[string]any{
+			"sign_count":   int64(signCount),
+			"backup_state": backupState,
+			"last_used_at": now,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUpdateWebAuthnSignCount, self, "failed to update webauthn sign count", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrWebAuthnCredentialCloned
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileUseAuthorizationCode = "use_authorization_code.go"
+
+func (db *DB) UseAuthorizationCode(ctx context.Context, hash, clientID, redirectURI string, now time.Time) (*auth.AuthorizationCode, error) {
+	const self = "UseAuthorizationCode"
+
+	// Marking the code as used in the same statement that checks it makes sure that
+	// concurrent requests cannot use it twice, and that another client cannot burn it
+	var models []AuthorizationCodeModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("hash = ? AND client_id = ? AND redirect_uri = ? AND used_at IS NULL AND expires_at > ?", hash, clientID, redirectURI, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseAuthorizationCode, self, "failed to use authorization code", result.Error))
+		return nil, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		// Tell a code presented twice apart from an unknown or expired one
+		var used AuthorizationCodeModel
+		result := db.Where("hash = ? AND client_id = ? AND used_at IS NOT NULL", hash, clientID).First(&used)
+		switch result.Error {
+		case nil:
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseAuthorizationCode, self, fmt.Sprintf("authorization code with id %q was presented again", used.ID.String()), auth.ErrAuthorizationCodeReused))
+			return used.toAuthorizationCode(), auth.ErrAuthorizationCodeReused
+		case gorm.ErrRecordNotFound:
+			return nil, auth.ErrInvalidAuthorizationCode
+		default:
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseAuthorizationCode, self, "failed to find used authorization code", result.Error))
+			return nil, auth.ErrInternal
+		}
+	}
+	model := models[0]
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUseAuthorizationCode, self, fmt.Sprintf("used authorization code with id %q", model.ID.String()), nil))
+
+	return model.toAuthorizationCode(), nil
+}
@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileUseDeviceCode = "use_device_code.go"
+
+func (db *DB) UseDeviceCode(ctx context.Context, id uuid.UUID, now time.Time) error {
+	const self = "UseDeviceCode"
+
+	result := db.
+		Model(&DeviceCodeModel{}).
+		Where("id = ? AND status = ? AND used_at IS NULL", id, auth.DeviceCodeApproved).
+		Update("used_at", now)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseDeviceCode, self, "failed to use device code", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrInvalidDeviceCode
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUseDeviceCode, self, fmt.Sprintf("used device code with id %q", id.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileUseEmailChange = "use_email_change.go"
+
+// UseEmailChange deletes the email change being confirmed, unless it has expired or was canceled meanwhile.
+func (db *DB) UseEmailChange(ctx context.Context, id uuid.UUID, now time.Time) error {
+	const self = "UseEmailChange"
+
+	result := db.Where("id = ? AND expires_at > ?", id, now).Delete(&EmailChangeModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseEmailChange, self, "failed to use email change", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrEmailChangeNotFound
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUseEmailChange, self, fmt.Sprintf("used email change with id %q", id.String()), nil))
+
+	return nil
+}
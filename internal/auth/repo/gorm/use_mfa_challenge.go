@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileUseMFAChallenge = "use_mfa_challenge.go"
+
+func (db *DB) UseMFAChallenge(ctx context.Context, id uuid.UUID, now time.Time) error {
+	const self = "UseMFAChallenge"
+
+	result := db.
+		Model(&MFAChallengeModel{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseMFAChallenge, self, "failed to use mfa challenge", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrInvalidMFAToken
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUseMFAChallenge, self, fmt.Sprintf("completed mfa challenge with id %q", id.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm/clause"
+)
+
+const FileUsePasswordResetToken = "use_password_reset_token.go"
+
+func (db *DB) UsePasswordResetToken(ctx context.Context, hash string, now time.Time) (*auth.PasswordResetToken, error) {
+	const self = "UsePasswordResetToken"
+
+	// Marking the token as used in the same statement that checks it
+	// makes sure that concurrent requests cannot use it twice
+	var models []PasswordResetTokenModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUsePasswordResetToken, self, "failed to use password reset token", result.Error))
+		return nil, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return nil, auth.ErrInvalidResetToken
+	}
+	model := models[0]
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUsePasswordResetToken, self, fmt.Sprintf("used password reset token with id %q", model.ID.String()), nil))
+
+	return &auth.PasswordResetToken{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Hash:      model.Hash,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileUseRecoveryCode = "use_recovery_code.go"
+
+func (db *DB) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, now time.Time) error {
+	const self = "UseRecoveryCode"
+
+	// Marking the code as used in the same statement that checks it
+	// makes sure that concurrent requests cannot use it twice
+	result := db.
+		Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", now)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseRecoveryCode, self, "failed to use recovery code", result.Error))
+		return auth.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return auth.ErrInvalidMFACode
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUseRecoveryCode, self, fmt.Sprintf("used a recovery code of user %q", userID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileUseTOTPStep = "use_totp_step.go"
+
+func (db *DB) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	const self = "UseTOTPStep"
+
+	result := db.
+		Model(&TOTPModel{}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseTOTPStep, self, "failed to use totp step", result.Error))
+		return auth.ErrInternal
+	}
+
+	// The code, or a later one, has already been used
+	if result.RowsAffected == 0 {
+		return auth.ErrInvalidMFACode
+	}
+
+	return nil
+}
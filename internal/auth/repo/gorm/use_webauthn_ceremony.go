@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm/clause"
+)
+
+const FileUseWebAuthnCeremony = "use_webauthn_ceremony.go"
+
+func (db *DB) UseWebAuthnCeremony(ctx context.Context, hash, kind string, now time.Time) (*auth.WebAuthnCeremony, error) {
+	const self = "UseWebAuthnCeremony"
+
+	// Marking the ceremony as used in the same statement that checks it
+	// makes sure that concurrent requests cannot finish it twice
+	var models []WebAuthnCeremonyModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("hash = ? AND kind = ? AND used_at IS NULL AND expires_at > ?", hash, kind, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUseWebAuthnCeremony, self, "failed to use webauthn ceremony", result.Error))
+		return nil, auth.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return nil, auth.ErrInvalidWebAuthnCeremony
+	}
+	model := models[0]
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUseWebAuthnCeremony, self, fmt.Sprintf("used webauthn %s ceremony with id %q", model.Kind, model.ID.String()), nil))
+
+	return &auth.WebAuthnCeremony{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Hash:      model.Hash,
+		Kind:      model.Kind,
+		Session:   model.Session,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
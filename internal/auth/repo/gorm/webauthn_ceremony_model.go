@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"time"
+
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type WebAuthnCeremonyModel struct {
+	ID        uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()"`
+	UserID    *uuid.UUID          `gorm:"type:uuid;index"`
+	User      *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Hash      string              `gorm:"not null;unique"`
+	Kind      string              `gorm:"not null"`
+	Session   []byte              `gorm:"type:jsonb;not null"`
+	ExpiresAt time.Time           `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (*WebAuthnCeremonyModel) TableName() string {
+	return "WebAuthnCeremony"
+}
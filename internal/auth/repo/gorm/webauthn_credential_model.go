@@ -0,0 +1,49 @@
+package gorm
+
+import (
+	"strings"
+	"time"
+
+	"auth/internal/auth"
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type WebAuthnCredentialModel struct {
+	ID              []byte              `gorm:"type:bytea;primaryKey"`
+	UserID          uuid.UUID           `gorm:"type:uuid;not null;index"`
+	User            *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Name            string              `gorm:"not null"`
+	PublicKey       []byte              `gorm:"type:bytea;not null"`
+	AttestationType string              `gorm:"not null"`
+	AAGUID          []byte              `gorm:"type:bytea"`
+	SignCount       int64               `gorm:"not null;default:0"`
+	// Space separated, as reported by the authenticator
+	Transports     string `gorm:"not null;default:''"`
+	BackupEligible bool   `gorm:"not null;default:false"`
+	BackupState    bool   `gorm:"not null;default:false"`
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time `gorm:"not null"`
+}
+
+func (*WebAuthnCredentialModel) TableName() string {
+	return "WebAuthnCredential"
+}
+
+func (m *WebAuthnCredentialModel) toCredential() *auth.WebAuthnCredential {
+	return &auth.WebAuthnCredential{
+		ID:              m.ID,
+		UserID:          m.UserID,
+		Name:            m.Name,
+		PublicKey:       m.PublicKey,
+		AttestationType: m.AttestationType,
+		AAGUID:          m.AAGUID,
+		SignCount:       uint32(m.SignCount),
+		Transports:      strings.Fields(m.Transports),
+		BackupEligible:  m.BackupEligible,
+		BackupState:     m.BackupState,
+		LastUsedAt:      m.LastUsedAt,
+		CreatedAt:       m.CreatedAt,
+	}
+}
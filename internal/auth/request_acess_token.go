@@ -2,45 +2,138 @@ package auth
 
 import (
 	"context"
+	"slices"
+	"time"
 
+	"auth/internal/user"
 	"auth/pkg/password"
 )
 
 type AccessTokenRequest struct {
 	Username string
 	Password string
+	// Address of the client, failed logins are also counted per IP
+	IP    string
+	Scope []string
 }
 
 type AccessTokenResponse struct {
 	GenerateTokenResponse
+	RefreshToken string
+	Scope        []string
 }
 
 func (s *Service) RequestAccessToken(ctx context.Context, req AccessTokenRequest) (AccessTokenResponse, error) {
-	// Find user by username (email)
-	user, err := s.UserRepo.FindByEmail(ctx, req.Username)
+	// Scopes are checked first, so that a login is never counted for a request bound to fail
+	scope, err := s.grantUserScopes(req.Scope)
 	if err != nil {
 		return AccessTokenResponse{}, err
 	}
 
+	u, err := s.authenticateUser(ctx, req.Username, req.Password, req.IP, scope)
+	if err != nil {
+		return AccessTokenResponse{}, err
+	}
+
+	amr := []string{AMRPassword}
+	token, err := s.GenerateToken(ctx, GenerateTokenRequest{UserID: u.ID, TokenVersion: u.TokenVersion, AMR: amr, Scope: scope, Admin: s.isAdmin(u)})
+	if err != nil {
+		return AccessTokenResponse{}, err
+	}
+
+	refresh, err := s.IssueRefreshToken(ctx, IssueRefreshTokenRequest{UserID: u.ID, AMR: amr, Scope: scope})
+	if err != nil {
+		return AccessTokenResponse{}, err
+	}
+
+	return AccessTokenResponse{token, refresh.RefreshToken, scope}, nil
+}
+
+// grantUserScopes returns the requested scopes, or every scope that users may be granted when none is requested.
+// It fails with ErrInvalidScope when a scope is not one that users may be granted.
+func (s *Service) grantUserScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return s.JWTConfig.Scopes, nil
+	}
+
+	for _, scope := range requested {
+		if !slices.Contains(s.JWTConfig.Scopes, scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+	return requested, nil
+}
+
+// authenticateUser checks the password of a login, counting failures towards lockouts.
+// Users with a second factor get an *MFARequiredError carrying the token that completes the login,
+// which is then granted the scope of the login.
+func (s *Service) authenticateUser(ctx context.Context, username, input, ip string, scope []string) (*user.User, error) {
+	// Locked accounts and clients are refused before their password is checked
+	now := time.Now()
+	if err := s.checkLoginLock(ctx, username, ip, now); err != nil {
+		return nil, err
+	}
+
+	// Find user by username (email), users pending deletion are restored by logging in
+	u, err := s.UserRepo.FindByEmail(ctx, username)
+	if err == user.ErrNotFoundByEmail && s.Deletion != nil {
+		u, err = s.UserRepo.FindDeletedByEmail(ctx, username, s.Deletion.RestorableAfter(now))
+	}
+	if err != nil {
+		if err == user.ErrNotFoundByEmail {
+			if err := s.recordLoginFailure(ctx, username, ip, now); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
 	// Check if incoming password matches stored password
 	checkPasswordRequest := password.CheckPasswordRequest{
-		Input:    req.Password,
-		Password: user.Password,
+		Input:    input,
+		Password: u.Password,
 	}
 	checkPasswordResponse, err := password.CheckPassword(ctx, checkPasswordRequest)
 	if err != nil {
-		return AccessTokenResponse{}, err
+		return nil, err
 	}
 
-	// If match is not valid, then deny access token request
+	// If match is not valid, then deny the login
 	if !checkPasswordResponse.Valid {
-		return AccessTokenResponse{}, ErrInvalidCredentials
+		if err := s.recordLoginFailure(ctx, username, ip, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
 	}
 
-	token, err := s.GenerateToken(ctx, GenerateTokenRequest{UserID: user.ID})
-	if err != nil {
-		return AccessTokenResponse{}, err
+	if err := s.resetLoginFailures(ctx, username); err != nil {
+		return nil, err
+	}
+
+	// Disabled users are not restored either
+	if u.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	if u.DeletedAt != nil {
+		if err := s.UserRepo.RestoreByID(ctx, u.ID, s.Deletion.RestorableAfter(now)); err != nil {
+			return nil, err
+		}
+		u.DeletedAt = nil
+	}
+
+	if s.Verification.Required && !u.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	// Users with a second factor get an mfa token to exchange instead
+	factor, err := s.Repo.FindTOTP(ctx, u.ID)
+	if err != nil && err != ErrTOTPNotFound {
+		return nil, err
+	}
+	if factor != nil && factor.ConfirmedAt != nil {
+		return nil, s.startMFAChallenge(ctx, u.ID, scope)
 	}
 
-	return AccessTokenResponse{token}, nil
+	return u, nil
 }
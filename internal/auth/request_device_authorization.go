@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/client"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type RequestDeviceAuthorizationRequest struct {
+	ClientID string
+	// Empty for public clients
+	ClientSecret string
+	Scope        []string
+}
+
+type RequestDeviceAuthorizationResponse struct {
+	DeviceCode string
+	UserCode   string
+	Scope      []string
+	ExpiresIn  int
+	Interval   int
+}
+
+// RequestDeviceAuthorization starts a device authorization (RFC 8628 section 3.1).
+// The device shows the user code to the user and polls the token endpoint with the device code.
+func (s *Service) RequestDeviceAuthorization(ctx context.Context, req RequestDeviceAuthorizationRequest) (RequestDeviceAuthorizationResponse, error) {
+	c, err := s.authenticateRegisteredClient(ctx, AuthenticateClientRequest{
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+	})
+	if err != nil {
+		return RequestDeviceAuthorizationResponse{}, err
+	}
+
+	if !c.AllowsGrant(client.GrantDeviceCode) {
+		return RequestDeviceAuthorizationResponse{}, ErrUnauthorizedClient
+	}
+
+	scope, err := c.GrantScopes(req.Scope)
+	if err != nil {
+		return RequestDeviceAuthorizationResponse{}, ErrInvalidScope
+	}
+
+	deviceCode, err := secret.Generate(32)
+	if err != nil {
+		return RequestDeviceAuthorizationResponse{}, err
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		return RequestDeviceAuthorizationResponse{}, err
+	}
+
+	now := time.Now()
+	err = s.Repo.InsertDeviceCode(ctx, &DeviceCode{
+		ID:           uuid.New(),
+		ClientID:     c.ID,
+		Hash:         secret.Hash(deviceCode),
+		UserCodeHash: hashUserCode(userCode),
+		Scope:        scope,
+		Status:       DeviceCodePending,
+		PollInterval: s.Device.Interval,
+		NextPollAt:   now,
+		ExpiresAt:    now.Add(time.Duration(s.Device.TTL) * time.Second),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return RequestDeviceAuthorizationResponse{}, err
+	}
+
+	return RequestDeviceAuthorizationResponse{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		Scope:      scope,
+		ExpiresIn:  s.Device.TTL,
+		Interval:   s.Device.Interval,
+	}, nil
+}
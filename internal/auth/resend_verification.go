@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/user"
+)
+
+type ResendVerificationRequest struct {
+	Email string
+}
+
+// ResendVerification issues a new verification code, replacing the previous one.
+// Unknown or already verified emails, as well as requests within the cooldown,
+// are silently ignored so that the outcome does not reveal whether an account exists.
+func (s *Service) ResendVerification(ctx context.Context, req ResendVerificationRequest) error {
+	u, err := s.UserRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if err == user.ErrNotFoundByEmail {
+			return nil
+		}
+		return err
+	}
+
+	if u.EmailVerified {
+		return nil
+	}
+
+	// The previous code was sent when it was issued, which is its expiration minus the TTL
+	if u.VerificationCodeExpiration != nil {
+		ttl := time.Duration(s.Verification.TTL) * time.Second
+		cooldown := time.Duration(s.Verification.Cooldown) * time.Second
+		sentAt := u.VerificationCodeExpiration.Add(-ttl)
+		if time.Since(sentAt) < cooldown {
+			return nil
+		}
+	}
+
+	otp, err := s.setVerificationCode(u)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UserRepo.SetVerificationCode(ctx, u); err != nil {
+		return err
+	}
+
+	return s.sendVerificationCode(ctx, u, otp)
+}
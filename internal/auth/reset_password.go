@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/pkg/secret"
+
+	"github.com/alexedwards/argon2id"
+)
+
+type ResetPasswordRequest struct {
+	Token    string
+	Password string
+}
+
+// ResetPassword sets a new password for the owner of a reset token and signs the user out everywhere,
+// by bumping the token version and revoking every refresh token.
+// The token and any other pending one are no longer usable afterwards.
+func (s *Service) ResetPassword(ctx context.Context, req ResetPasswordRequest) error {
+	now := time.Now()
+	hash := secret.Hash(req.Token)
+
+	token, err := s.Repo.FindPasswordResetTokenByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	u, err := s.UserRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+
+	// The token is only used up once the new password is accepted
+	if err := s.PasswordPolicy.Validate(req.Password, u.Email); err != nil {
+		return err
+	}
+
+	if _, err := s.Repo.UsePasswordResetToken(ctx, hash, now); err != nil {
+		return err
+	}
+
+	hashedPasswd, err := argon2id.CreateHash(req.Password, argon2id.DefaultParams)
+	if err != nil {
+		return err
+	}
+	u.Password = hashedPasswd
+
+	if err := s.UserRepo.UpdatePassword(ctx, u); err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeletePasswordResetTokens(ctx, u.ID); err != nil {
+		return err
+	}
+	return s.Repo.RevokeUserRefreshTokens(ctx, u.ID)
+}
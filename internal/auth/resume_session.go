@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+)
+
+// ResumeSession returns the session of the token, failing with ErrInvalidSession once it has expired,
+// once the user has been deleted or once the user has changed their password.
+func (s *Service) ResumeSession(ctx context.Context, token string) (*Session, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+
+	session, err := s.Repo.FindSessionByHash(ctx, secret.Hash(token))
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidSession
+	}
+
+	u, err := s.UserRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		if err == user.ErrNotFoundByID {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+	if u.TokenVersion != session.TokenVersion {
+		return nil, ErrInvalidSession
+	}
+
+	return session, nil
+}
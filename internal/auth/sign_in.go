@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type SignInRequest struct {
+	Username string
+	Password string
+	// Address of the client, failed logins are also counted per IP
+	IP string
+}
+
+type SignInMFARequest struct {
+	MFAToken string
+	// One-time password or recovery code
+	Code string
+}
+
+type SignInResponse struct {
+	Session *Session
+	// SessionToken is meant to be kept in a cookie
+	SessionToken string
+	ExpiresIn    int
+}
+
+// SignIn checks the credentials typed on the login page and starts a session.
+// It fails like the password grant, including with an *MFARequiredError for users with a second factor.
+func (s *Service) SignIn(ctx context.Context, req SignInRequest) (SignInResponse, error) {
+	// Sessions carry no scope, which is requested by each authorization request instead
+	u, err := s.authenticateUser(ctx, req.Username, req.Password, req.IP, nil)
+	if err != nil {
+		return SignInResponse{}, err
+	}
+	return s.startSession(ctx, u, []string{AMRPassword})
+}
+
+// SignInMFA completes a sign in that required a second factor.
+func (s *Service) SignInMFA(ctx context.Context, req SignInMFARequest) (SignInResponse, error) {
+	u, amr, _, err := s.completeMFAChallenge(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		return SignInResponse{}, err
+	}
+	return s.startSession(ctx, u, amr)
+}
+
+func (s *Service) startSession(ctx context.Context, u *user.User, amr []string) (SignInResponse, error) {
+	token, err := secret.Generate(32)
+	if err != nil {
+		return SignInResponse{}, err
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:           uuid.New(),
+		UserID:       u.ID,
+		Hash:         secret.Hash(token),
+		TokenVersion: u.TokenVersion,
+		AMR:          amr,
+		ExpiresAt:    now.Add(time.Duration(s.Authorize.SessionTTL) * time.Second),
+		CreatedAt:    now,
+	}
+	if err := s.Repo.InsertSession(ctx, session); err != nil {
+		return SignInResponse{}, err
+	}
+
+	return SignInResponse{Session: session, SessionToken: token, ExpiresIn: s.Authorize.SessionTTL}, nil
+}
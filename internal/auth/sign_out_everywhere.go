@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SignOutEverywhereRequest struct {
+	UserID uuid.UUID
+}
+
+// SignOutEverywhere revokes every refresh token of the user. Access tokens and login page sessions
+// are only invalidated along with the token version of the user, which the caller is expected to bump.
+func (s *Service) SignOutEverywhere(ctx context.Context, req SignOutEverywhereRequest) error {
+	return s.Repo.RevokeUserRefreshTokens(ctx, req.UserID)
+}
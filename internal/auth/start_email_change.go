@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/password"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type StartEmailChangeRequest struct {
+	UserID   uuid.UUID
+	Password string
+	Email    string
+}
+
+// StartEmailChange records a pending change of the email address of the user, replacing any previous one.
+// A confirmation code is sent to the new address, and the current address is notified with a link that
+// cancels the change. The address only changes once the code is confirmed.
+func (s *Service) StartEmailChange(ctx context.Context, req StartEmailChangeRequest) error {
+	u, err := s.UserRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	checkPasswordResponse, err := password.CheckPassword(ctx, password.CheckPasswordRequest{
+		Input:    req.Password,
+		Password: u.Password,
+	})
+	if err != nil {
+		return err
+	}
+	if !checkPasswordResponse.Valid {
+		return ErrInvalidCredentials
+	}
+
+	if strings.EqualFold(req.Email, u.Email) {
+		return ErrEmailUnchanged
+	}
+
+	// Taken addresses are refused early, the unique constraint still decides on confirmation
+	if _, err := s.UserRepo.FindByEmail(ctx, req.Email); err != user.ErrNotFoundByEmail {
+		if err == nil {
+			return user.ErrEmailAlreadyInUse
+		}
+		return err
+	}
+
+	// Only the most recent change can be confirmed
+	if err := s.Repo.DeleteEmailChanges(ctx, u.ID); err != nil {
+		return err
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return err
+	}
+	token, err := secret.Generate(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ttl := time.Duration(s.Verification.TTL) * time.Second
+	err = s.Repo.InsertEmailChange(ctx, &EmailChange{
+		UserID:     u.ID,
+		Email:      req.Email,
+		CodeHash:   secret.Hash(code),
+		CancelHash: secret.Hash(token),
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Both emails are in the language of the user, if chosen in its profile
+	var locale string
+	if u.Locale != nil {
+		locale = *u.Locale
+	}
+
+	confirmation, err := s.Templates.Render(locale, "confirm_email_change", req.Email, struct {
+		Code      string
+		ExpiresIn int
+	}{
+		Code:      code,
+		ExpiresIn: s.Verification.TTL / 60,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.Mailer.Send(ctx, confirmation); err != nil {
+		return err
+	}
+
+	notification, err := s.Templates.Render(locale, "cancel_email_change", u.Email, struct {
+		Email     string
+		Token     string
+		Link      string
+		ExpiresIn int
+	}{
+		Email:     req.Email,
+		Token:     token,
+		Link:      tokenLink(s.Verification.CancelURL, token),
+		ExpiresIn: s.Verification.TTL / 60,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(ctx, notification)
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// Parameters understood by every authenticator app
+const (
+	totpPeriod = 30
+	totpDigits = otp.DigitsSix
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    totpPeriod,
+	Digits:    totpDigits,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// matchTOTP returns the time step of the code, looking up to skew steps
+// before and after the current one to tolerate clock drift.
+func matchTOTP(secret, code string, now time.Time, skew int) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits.Length() {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for i := -skew; i <= skew; i++ {
+		step := current + int64(i)
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// verifySecondFactor accepts either a one-time password of the confirmed TOTP factor of the user
+// or one of their recovery codes, returning the authentication methods it proves.
+func (s *Service) verifySecondFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	factor, err := s.Repo.FindTOTP(ctx, userID)
+	if err != nil {
+		if err == ErrTOTPNotFound {
+			return nil, ErrTOTPNotEnabled
+		}
+		return nil, err
+	}
+	if factor.ConfirmedAt == nil {
+		return nil, ErrTOTPNotEnabled
+	}
+
+	if step, ok := matchTOTP(factor.Secret, code, time.Now(), s.MFA.Skew); ok {
+		if err := s.Repo.UseTOTPStep(ctx, userID, step); err != nil {
+			return nil, err
+		}
+		return []string{AMROTP, AMRMFA}, nil
+	}
+
+	if err := s.Repo.UseRecoveryCode(ctx, userID, secret.Hash(normalizeRecoveryCode(code)), time.Now()); err != nil {
+		return nil, err
+	}
+	return []string{AMRMFA}, nil
+}
+
+// verifySecondFactorAttempt verifies a code sent outside of an mfa challenge, which has attempts of its own.
+// Attempts are counted per user before the code is checked, so that concurrent guesses cannot exceed the limit,
+// and the user is locked out until no attempt was made for the lifetime of an mfa token.
+func (s *Service) verifySecondFactorAttempt(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	now := time.Now()
+	windowStart := now.Add(-time.Duration(s.MFA.TTL) * time.Second)
+	attempts, err := s.Repo.CountTOTPAttempt(ctx, userID, now, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > s.MFA.Attempts {
+		return nil, ErrTooManyTOTPAttempts
+	}
+
+	amr, err := s.verifySecondFactor(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.ResetTOTPAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+	return amr, nil
+}
+
+// generateRecoveryCodes returns n codes such as "k3j7q-x9m2w", along with their stored form.
+func (s *Service) generateRecoveryCodes(userID uuid.UUID) ([]string, []*RecoveryCode, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	now := time.Now()
+
+	values := make([]string, 0, s.MFA.RecoveryCodes)
+	codes := make([]*RecoveryCode, 0, s.MFA.RecoveryCodes)
+	for range s.MFA.RecoveryCodes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:10]
+		value := raw[:5] + "-" + raw[5:]
+
+		values = append(values, value)
+		codes = append(codes, &RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Hash:      secret.Hash(raw),
+			CreatedAt: now,
+		})
+	}
+	return values, codes, nil
+}
+
+// normalizeRecoveryCode ignores case, separators and spaces typed by the user.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+)
+
+type UnlockLoginRequest struct {
+	Email string
+	IP    string
+}
+
+type UnlockLoginResponse struct {
+	Unlocked int64
+}
+
+// UnlockLogin clears the failed logins and any lockout of an account, a client IP or both.
+func (s *Service) UnlockLogin(ctx context.Context, req UnlockLoginRequest) (UnlockLoginResponse, error) {
+	var keys []string
+	account, client := loginThrottleKeys(req.Email, req.IP)
+	if req.Email != "" {
+		keys = append(keys, account)
+	}
+	if client != "" {
+		keys = append(keys, client)
+	}
+
+	unlocked, err := s.Repo.DeleteLoginThrottles(ctx, keys...)
+	if err != nil {
+		return UnlockLoginResponse{}, err
+	}
+	return UnlockLoginResponse{unlocked}, nil
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"auth/pkg/secret"
+)
+
+// userCodeAlphabet has no vowels, so that codes never spell words, and no
+// characters that are easily mistaken for one another (RFC 8628 section 6.1).
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// userCodeLength gives about 34 bits of entropy, enough for a code that expires in minutes
+// and can only be tried by signed in users.
+const userCodeLength = 8
+
+// generateUserCode returns a code typed by the user on the verification page, formatted as "XXXX-XXXX".
+func generateUserCode() (string, error) {
+	var code strings.Builder
+	maxInt := big.NewInt(int64(len(userCodeAlphabet)))
+
+	for i := 0; i < userCodeLength; i++ {
+		if i == userCodeLength/2 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, maxInt)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+
+	return code.String(), nil
+}
+
+// hashUserCode normalizes a user code as typed by the user, ignoring case, dashes and spaces, then hashes it.
+func hashUserCode(userCode string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		if !strings.ContainsRune(userCodeAlphabet, r) {
+			return -1
+		}
+		return r
+	}, userCode)
+	return secret.Hash(normalized)
+}
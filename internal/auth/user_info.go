@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type UserInfoRequest struct {
+	UserID uuid.UUID
+	// Scope granted to the access token
+	Scope []string
+}
+
+type UserInfoResponse struct {
+	Claims map[string]any
+}
+
+// UserInfo returns the claims about the user of an access token (OIDC Core section 5.3).
+// Only tokens granted the openid scope are allowed, the other scopes select the claims.
+func (s *Service) UserInfo(ctx context.Context, req UserInfoRequest) (UserInfoResponse, error) {
+	if !slices.Contains(req.Scope, ScopeOpenID) {
+		return UserInfoResponse{}, ErrInsufficientScope
+	}
+
+	u, err := s.UserRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return UserInfoResponse{}, err
+	}
+
+	return UserInfoResponse{Claims: userClaims(u, req.Scope)}, nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"auth/internal/client"
+)
+
+// CodeChallengeS256 is the only PKCE method accepted, "plain" offers no protection
+// against a leaked authorization request (RFC 7636 section 4.2).
+const CodeChallengeS256 = "S256"
+
+type ValidateAuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               []string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+type ValidateAuthorizationResponse struct {
+	Client *client.Client
+	// Scope granted to the client, every scope of the client when none was requested
+	Scope []string
+}
+
+// ValidateAuthorization validates a request to the authorization endpoint (RFC 6749 section 4.1.1).
+// The client and the redirect URI are checked first: ErrInvalidClient and ErrInvalidRedirectURI
+// must be shown to the user instead of being sent to a redirect URI that cannot be trusted.
+func (s *Service) ValidateAuthorization(ctx context.Context, req ValidateAuthorizationRequest) (ValidateAuthorizationResponse, error) {
+	if req.ClientID == "" {
+		return ValidateAuthorizationResponse{}, ErrInvalidClient
+	}
+
+	c, err := s.ClientRepo.FindByID(ctx, req.ClientID)
+	if err != nil {
+		if err == client.ErrNotFoundByID {
+			return ValidateAuthorizationResponse{}, ErrInvalidClient
+		}
+		return ValidateAuthorizationResponse{}, err
+	}
+
+	if !c.AllowsRedirectURI(req.RedirectURI) {
+		return ValidateAuthorizationResponse{}, ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return ValidateAuthorizationResponse{}, ErrUnsupportedResponseType
+	}
+
+	if !c.AllowsGrant(client.GrantAuthorizationCode) {
+		return ValidateAuthorizationResponse{}, ErrUnauthorizedClient
+	}
+
+	if req.CodeChallengeMethod != CodeChallengeS256 || !validCodeChallenge(req.CodeChallenge) {
+		return ValidateAuthorizationResponse{}, ErrInvalidCodeChallenge
+	}
+
+	scope, err := c.GrantScopes(req.Scope)
+	if err != nil {
+		return ValidateAuthorizationResponse{}, ErrInvalidScope
+	}
+
+	return ValidateAuthorizationResponse{Client: c, Scope: scope}, nil
+}
+
+// validCodeChallenge reports whether the challenge is the base64url encoding of a SHA-256 hash.
+func validCodeChallenge(challenge string) bool {
+	hash, err := base64.RawURLEncoding.DecodeString(challenge)
+	return err == nil && len(hash) == sha256.Size
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against the S256 challenge of the authorization request.
+// Verifiers are 43 to 128 characters long, made of unreserved URI characters (RFC 7636 section 4.1).
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	if strings.Trim(verifier, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~") != "" {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
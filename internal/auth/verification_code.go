@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+)
+
+// setVerificationCode generates a new one-time code for u and resets its attempts.
+// Only the hash of the code is kept on the user, the code itself is returned for delivery.
+func (s *Service) setVerificationCode(u *user.User) (string, error) {
+	otp, err := generateOTP()
+	if err != nil {
+		return "", err
+	}
+
+	hash := secret.Hash(otp)
+	expiration := time.Now().Add(time.Duration(s.Verification.TTL) * time.Second)
+
+	u.VerificationCode = &hash
+	u.VerificationCodeExpiration = &expiration
+	u.VerificationAttempts = 0
+	return otp, nil
+}
+
+// sendVerificationCode renders the "verify_email" template in the default locale and queues it for delivery.
+func (s *Service) sendVerificationCode(ctx context.Context, u *user.User, otp string) error {
+	msg, err := s.Templates.Render("", "verify_email", u.Email, struct {
+		Code      string
+		ExpiresIn int
+	}{
+		Code:      otp,
+		ExpiresIn: s.Verification.TTL / 60,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(ctx, msg)
+}
+
+func generateOTP() (otp string, err error) {
+	length := 6
+	maxInt := big.NewInt(10)
+
+	for i := 0; i < length; i++ {
+		number, err := rand.Int(rand.Reader, maxInt)
+		if err != nil {
+			return "", err
+		}
+		otp += number.String()
+	}
+
+	return otp, nil
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+)
+
+type VerifyEmailRequest struct {
+	Email string
+	Code  string
+}
+
+type VerifyEmailResponse struct {
+	User *user.User
+}
+
+func (s *Service) VerifyEmail(ctx context.Context, req VerifyEmailRequest) (VerifyEmailResponse, error) {
+	// Unknown emails, verified addresses and expired codes are all reported as a wrong code,
+	// so that the endpoint cannot be used to enumerate accounts or learn their state
+	u, err := s.UserRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if err == user.ErrNotFoundByEmail {
+			return VerifyEmailResponse{nil}, ErrInvalidVerificationCode
+		}
+		return VerifyEmailResponse{nil}, err
+	}
+
+	if u.EmailVerified {
+		return VerifyEmailResponse{nil}, ErrInvalidVerificationCode
+	}
+
+	if u.VerificationCode == nil || u.VerificationCodeExpiration == nil || time.Now().After(*u.VerificationCodeExpiration) {
+		return VerifyEmailResponse{nil}, ErrInvalidVerificationCode
+	}
+
+	// The attempt is counted before comparing the code, so that concurrent guesses cannot exceed the limit
+	attempts, err := s.UserRepo.CountVerificationAttempt(ctx, u.ID)
+	if err != nil {
+		return VerifyEmailResponse{nil}, err
+	}
+	if attempts > s.Verification.Attempts {
+		return VerifyEmailResponse{nil}, ErrTooManyVerificationAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret.Hash(req.Code)), []byte(*u.VerificationCode)) != 1 {
+		return VerifyEmailResponse{nil}, ErrInvalidVerificationCode
+	}
+
+	if err := s.UserRepo.VerifyEmailByID(ctx, u.ID); err != nil {
+		return VerifyEmailResponse{nil}, err
+	}
+
+	u.EmailVerified = true
+	u.VerificationCode = nil
+	u.VerificationCodeExpiration = nil
+	u.VerificationAttempts = 0
+
+	return VerifyEmailResponse{u}, nil
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+
+	"github.com/google/uuid"
+)
+
+type VerifyMFARequest struct {
+	MFAToken string
+	// One-time password or recovery code
+	Code string
+}
+
+type VerifyMFAResponse struct {
+	GenerateTokenResponse
+	RefreshToken string
+	Scope        []string
+}
+
+// startMFAChallenge records a login whose password has been checked
+// and returns the error carrying the token that completes it.
+func (s *Service) startMFAChallenge(ctx context.Context, userID uuid.UUID, scope []string) error {
+	token, err := secret.Generate(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.Repo.InsertMFAChallenge(ctx, &MFAChallenge{
+		UserID:    userID,
+		Hash:      secret.Hash(token),
+		Scope:     scope,
+		ExpiresAt: now.Add(time.Duration(s.MFA.TTL) * time.Second),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return &MFARequiredError{MFAToken: token, ExpiresIn: s.MFA.TTL}
+}
+
+// VerifyMFA completes a login started by the password grant with a second factor.
+func (s *Service) VerifyMFA(ctx context.Context, req VerifyMFARequest) (VerifyMFAResponse, error) {
+	u, amr, scope, err := s.completeMFAChallenge(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		return VerifyMFAResponse{}, err
+	}
+
+	token, err := s.GenerateToken(ctx, GenerateTokenRequest{UserID: u.ID, TokenVersion: u.TokenVersion, AMR: amr, Scope: scope, Admin: s.isAdmin(u)})
+	if err != nil {
+		return VerifyMFAResponse{}, err
+	}
+
+	refresh, err := s.IssueRefreshToken(ctx, IssueRefreshTokenRequest{UserID: u.ID, AMR: amr, Scope: scope})
+	if err != nil {
+		return VerifyMFAResponse{}, err
+	}
+
+	return VerifyMFAResponse{token, refresh.RefreshToken, scope}, nil
+}
+
+// completeMFAChallenge checks the second factor of a login whose password has been checked
+// and returns the user along with the authentication methods and the scope of the whole login.
+// A challenge only allows a few attempts, after which the login must be started over.
+func (s *Service) completeMFAChallenge(ctx context.Context, mfaToken, code string) (*user.User, []string, []string, error) {
+	challenge, err := s.Repo.FindMFAChallengeByHash(ctx, secret.Hash(mfaToken))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	if challenge.UsedAt != nil || now.After(challenge.ExpiresAt) {
+		return nil, nil, nil, ErrInvalidMFAToken
+	}
+	// Attempts are counted before the code is checked, so that concurrent guesses cannot exceed the limit
+	attempts, err := s.Repo.CountMFAChallengeAttempt(ctx, challenge.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if attempts > s.MFA.Attempts {
+		return nil, nil, nil, ErrTooManyMFAAttempts
+	}
+
+	amr, err := s.verifySecondFactor(ctx, challenge.UserID, code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := s.Repo.UseMFAChallenge(ctx, challenge.ID, now); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The user may have changed its password or been disabled since the challenge started
+	u, err := s.UserRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if u.DisabledAt != nil {
+		return nil, nil, nil, ErrUserDisabled
+	}
+
+	return u, append([]string{AMRPassword}, amr...), challenge.Scope, nil
+}
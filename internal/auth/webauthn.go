@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/secret"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// Kinds of webauthn ceremonies
+const (
+	CeremonyRegistration = "registration"
+	CeremonyLogin        = "login"
+)
+
+// relyingParty requires discoverable credentials verified by the authenticator,
+// so that a passkey alone is enough to log in.
+func (s *Service) relyingParty() (*webauthn.WebAuthn, error) {
+	ttl := time.Duration(s.WebAuthn.TTL) * time.Second
+	return webauthn.New(&webauthn.Config{
+		RPID:          s.WebAuthn.RPID,
+		RPDisplayName: s.WebAuthn.RPName,
+		RPOrigins:     s.WebAuthn.Origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Timeout: ttl, TimeoutUVD: ttl},
+			Registration: webauthn.TimeoutConfig{Timeout: ttl, TimeoutUVD: ttl},
+		},
+	})
+}
+
+// webAuthnUser adapts a user and its credentials to the webauthn library.
+// The user handle stored by authenticators is the raw UUID of the user.
+type webAuthnUser struct {
+	user        *user.User
+	credentials []*WebAuthnCredential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(c.Transports))
+		for _, t := range c.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              c.ID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: c.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+func (s *Service) findWebAuthnUser(ctx context.Context, userID uuid.UUID) (*webAuthnUser, error) {
+	u, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.Repo.ListWebAuthnCredentials(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnUser{user: u, credentials: credentials}, nil
+}
+
+// startWebAuthnCeremony stores the session of a ceremony and returns the token that finishes it.
+func (s *Service) startWebAuthnCeremony(ctx context.Context, userID *uuid.UUID, kind string, session *webauthn.SessionData) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := secret.Generate(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = s.Repo.InsertWebAuthnCeremony(ctx, &WebAuthnCeremony{
+		UserID:    userID,
+		Hash:      secret.Hash(token),
+		Kind:      kind,
+		Session:   data,
+		ExpiresAt: now.Add(time.Duration(s.WebAuthn.TTL) * time.Second),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// useWebAuthnCeremony consumes a pending ceremony, which cannot be finished twice
+// even when the response of the authenticator turns out to be invalid.
+func (s *Service) useWebAuthnCeremony(ctx context.Context, token, kind string) (*WebAuthnCeremony, webauthn.SessionData, error) {
+	ceremony, err := s.Repo.UseWebAuthnCeremony(ctx, secret.Hash(token), kind, time.Now())
+	if err != nil {
+		return nil, webauthn.SessionData{}, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(ceremony.Session, &session); err != nil {
+		return nil, webauthn.SessionData{}, fmt.Errorf("%w: %w", ErrInternal, err)
+	}
+
+	return ceremony, session, nil
+}
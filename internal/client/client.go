@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInternal            = errors.New("the client service encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrNotFoundByID        = errors.New("could not find any client with provided ID")
+	ErrUnsupportedGrant    = errors.New("grant type is not supported")
+	ErrGrantNotAllowed     = errors.New("client is not allowed to use the grant type")
+	ErrScopeNotAllowed     = errors.New("requested scope exceeds the scopes of the client")
+	ErrInvalidAccessTTL    = errors.New("access token lifetime must not be negative")
+	ErrInvalidRefreshTTL   = errors.New("refresh token lifetime must not be negative")
+	ErrRedirectURIRequired = errors.New("authorization code clients must register at least one redirect uri")
+	ErrInvalidRedirectURI  = errors.New("redirect uri must be an absolute url without a fragment")
+	ErrPublicClientGrant   = errors.New("public clients cannot use the client credentials grant")
+	ErrPublicClientScope   = errors.New("public clients cannot introspect tokens")
+)
+
+// Grant types that clients can be allowed to use
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantAuthorizationCode = "authorization_code"
+	// Clients registered with this grant also get a refresh token from the authorization code
+	// and device code grants
+	GrantRefreshToken = "refresh_token"
+	// Device authorization grant (RFC 8628), for devices that cannot open a browser
+	GrantDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// ScopeIntrospect allows a confidential client, such as a resource server, to introspect tokens (RFC 7662).
+const ScopeIntrospect = "introspect"
+
+// Grants lists every grant type that a client can be registered with.
+var Grants = []string{GrantClientCredentials, GrantAuthorizationCode, GrantRefreshToken, GrantDeviceCode}
+
+// Client is an application registered to obtain tokens from the token endpoint.
+// Only the hash of its secret is stored, the secret itself is shown once at registration.
+// Public clients, such as browser applications, cannot keep a secret and have none.
+type Client struct {
+	ID         string
+	SecretHash string
+	Name       string
+	Public     bool
+	Grants     []string
+	Scopes     []string
+	// Exact URIs that authorization codes can be sent to
+	RedirectURIs []string
+	// Audiences of the access tokens issued to the client, the configured ones when empty
+	Audiences []string
+	// Lifetimes in seconds of the tokens issued to the client, the configured ones when zero
+	AccessTokenTTL  int
+	RefreshTokenTTL int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// AllowsGrant reports whether the client is registered with the grant type.
+func (c *Client) AllowsGrant(grant string) bool {
+	return slices.Contains(c.Grants, grant)
+}
+
+// CanIntrospect reports whether the client may introspect tokens.
+// Public clients cannot, since introspection requires the caller to authenticate.
+func (c *Client) CanIntrospect() bool {
+	return !c.Public && slices.Contains(c.Scopes, ScopeIntrospect)
+}
+
+// AllowsRedirectURI reports whether the URI is one of the registered ones.
+// URIs are compared as strings, as required by RFC 6749 section 3.1.2.3.
+func (c *Client) AllowsRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// GrantScopes returns the requested scopes, or every scope of the client when none is requested.
+// Requesting a scope that the client was not registered with fails with ErrScopeNotAllowed.
+func (c *Client) GrantScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return c.Scopes, nil
+	}
+
+	for _, scope := range requested {
+		if !slices.Contains(c.Scopes, scope) {
+			return nil, ErrScopeNotAllowed
+		}
+	}
+	return requested, nil
+}
+
+type Service struct {
+	Repo Repoer
+}
+
+type Repoer interface {
+	Insert(context.Context, *Client) error
+	FindByID(context.Context, string) (*Client, error)
+	DeleteByID(context.Context, string) error
+}
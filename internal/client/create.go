@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"time"
+
+	"auth/pkg/secret"
+)
+
+type CreateRequest struct {
+	Name            string
+	Public          bool
+	Grants          []string
+	Scopes          []string
+	RedirectURIs    []string
+	Audiences       []string
+	AccessTokenTTL  int
+	RefreshTokenTTL int
+}
+
+type CreateResponse struct {
+	Client *Client
+	// Secret is only available at registration, and empty for public clients
+	Secret string
+}
+
+// Create registers a client with a generated ID and, unless it is public, a generated secret.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (CreateResponse, error) {
+	for _, grant := range req.Grants {
+		if !slices.Contains(Grants, grant) {
+			return CreateResponse{}, ErrUnsupportedGrant
+		}
+	}
+	if req.Public && slices.Contains(req.Grants, GrantClientCredentials) {
+		return CreateResponse{}, ErrPublicClientGrant
+	}
+	if req.Public && slices.Contains(req.Scopes, ScopeIntrospect) {
+		return CreateResponse{}, ErrPublicClientScope
+	}
+	if slices.Contains(req.Grants, GrantAuthorizationCode) && len(req.RedirectURIs) == 0 {
+		return CreateResponse{}, ErrRedirectURIRequired
+	}
+	for _, uri := range req.RedirectURIs {
+		parsed, err := url.Parse(uri)
+		if err != nil || !parsed.IsAbs() || parsed.Host == "" || parsed.Fragment != "" {
+			return CreateResponse{}, ErrInvalidRedirectURI
+		}
+	}
+	if req.AccessTokenTTL < 0 {
+		return CreateResponse{}, ErrInvalidAccessTTL
+	}
+	if req.RefreshTokenTTL < 0 {
+		return CreateResponse{}, ErrInvalidRefreshTTL
+	}
+
+	// IDs never look like the UUID of a user, since both end up in the "sub" claim
+	id, err := secret.Generate(16)
+	if err != nil {
+		return CreateResponse{}, err
+	}
+
+	var clientSecret, secretHash string
+	if !req.Public {
+		clientSecret, err = secret.Generate(32)
+		if err != nil {
+			return CreateResponse{}, err
+		}
+		secretHash = secret.Hash(clientSecret)
+	}
+
+	now := time.Now()
+	c := &Client{
+		ID:              id,
+		SecretHash:      secretHash,
+		Name:            req.Name,
+		Public:          req.Public,
+		Grants:          slices.Compact(slices.Sorted(slices.Values(req.Grants))),
+		Scopes:          slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+		RedirectURIs:    req.RedirectURIs,
+		Audiences:       req.Audiences,
+		AccessTokenTTL:  req.AccessTokenTTL,
+		RefreshTokenTTL: req.RefreshTokenTTL,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.Repo.Insert(ctx, c); err != nil {
+		return CreateResponse{}, err
+	}
+
+	return CreateResponse{Client: c, Secret: clientSecret}, nil
+}
@@ -0,0 +1,14 @@
+package client
+
+import "context"
+
+type DeleteByIDRequest struct {
+	ID string
+}
+
+// DeleteByID unregisters a client. Access tokens already issued to it
+// stay valid for signature checks until they expire, but are no longer active
+// when introspected.
+func (s *Service) DeleteByID(ctx context.Context, req DeleteByIDRequest) error {
+	return s.Repo.DeleteByID(ctx, req.ID)
+}
@@ -0,0 +1,19 @@
+package client
+
+import "context"
+
+type FindByIDRequest struct {
+	ID string
+}
+
+type FindByIDResponse struct {
+	Client *Client
+}
+
+func (s *Service) FindByID(ctx context.Context, req FindByIDRequest) (FindByIDResponse, error) {
+	c, err := s.Repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return FindByIDResponse{nil}, err
+	}
+	return FindByIDResponse{c}, nil
+}
@@ -0,0 +1,45 @@
+package httphandler
+
+import (
+	"time"
+
+	"auth/internal/client"
+)
+
+type clientResponse struct {
+	ID              string    `json:"client_id"`
+	Name            string    `json:"name"`
+	Public          bool      `json:"public"`
+	Grants          []string  `json:"grant_types"`
+	Scopes          []string  `json:"scopes"`
+	RedirectURIs    []string  `json:"redirect_uris"`
+	Audiences       []string  `json:"audiences"`
+	AccessTokenTTL  int       `json:"access_token_ttl"`
+	RefreshTokenTTL int       `json:"refresh_token_ttl"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func newClientResponse(c *client.Client) clientResponse {
+	// Empty lists are encoded as [] instead of null
+	nonNil := func(s []string) []string {
+		if s == nil {
+			return []string{}
+		}
+		return s
+	}
+
+	return clientResponse{
+		ID:              c.ID,
+		Name:            c.Name,
+		Public:          c.Public,
+		Grants:          nonNil(c.Grants),
+		Scopes:          nonNil(c.Scopes),
+		RedirectURIs:    nonNil(c.RedirectURIs),
+		Audiences:       nonNil(c.Audiences),
+		AccessTokenTTL:  c.AccessTokenTTL,
+		RefreshTokenTTL: c.RefreshTokenTTL,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+}
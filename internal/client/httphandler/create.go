@@ -0,0 +1,136 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/client"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationCreate = "create"
+	FileCreate      = OperationCreate + ".go"
+)
+
+func (s *ClientServer) handleClientCreate() http.HandlerFunc {
+	const self = "handleClientCreate"
+
+	type request struct {
+		Name            string   `json:"name" validate:"required,max=128"`
+		Public          bool     `json:"public"`
+		Grants          []string `json:"grant_types" validate:"required,min=1"`
+		Scopes          []string `json:"scopes" validate:"dive,required,printascii,excludesall= \"\\"`
+		RedirectURIs    []string `json:"redirect_uris" validate:"dive,required,url"`
+		Audiences       []string `json:"audiences" validate:"dive,required"`
+		AccessTokenTTL  int      `json:"access_token_ttl" validate:"gte=0"`
+		RefreshTokenTTL int      `json:"refresh_token_ttl" validate:"gte=0"`
+	}
+
+	type response struct {
+		clientResponse
+		Secret string `json:"client_secret,omitempty"`
+	}
+
+	contract := map[string]responder.Field{
+		"Name": {
+			Name:       "name",
+			Validation: "Field value cannot be an empty string and must have at most 128 characters.",
+		},
+		"Grants": {
+			Name:       "grant_types",
+			Validation: "Field must list at least one grant type.",
+		},
+		"Scopes": {
+			Name:       "scopes",
+			Validation: "Field must only list non empty scopes made of printable characters other than spaces, quotes and backslashes.",
+		},
+		"RedirectURIs": {
+			Name:       "redirect_uris",
+			Validation: "Field must only list absolute URLs.",
+		},
+		"Audiences": {
+			Name:       "audiences",
+			Validation: "Field must only list non empty audiences.",
+		},
+		"AccessTokenTTL": {
+			Name:       "access_token_ttl",
+			Validation: "Field value must not be negative.",
+		},
+		"RefreshTokenTTL": {
+			Name:       "refresh_token_ttl",
+			Validation: "Field value must not be negative.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreate))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreate))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		createResponse, err := s.service.Create(ctx, client.CreateRequest{
+			Name:            req.Name,
+			Public:          req.Public,
+			Grants:          req.Grants,
+			Scopes:          req.Scopes,
+			RedirectURIs:    req.RedirectURIs,
+			Audiences:       req.Audiences,
+			AccessTokenTTL:  req.AccessTokenTTL,
+			RefreshTokenTTL: req.RefreshTokenTTL,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreate))
+			span.RecordError(err)
+			switch err {
+			case client.ErrUnsupportedGrant:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Grant type is not supported.")
+			case client.ErrInvalidAccessTTL, client.ErrInvalidRefreshTTL:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Token lifetimes must not be negative.")
+			case client.ErrPublicClientGrant:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Public clients cannot use the client_credentials grant.")
+			case client.ErrPublicClientScope:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Public clients cannot be granted the introspect scope.")
+			case client.ErrRedirectURIRequired:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Clients using the authorization_code grant must register a redirect URI.")
+			case client.ErrInvalidRedirectURI:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Redirect URIs must be absolute URLs without a fragment.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+		s.clientsCreatedCounter.Add(ctx, 1)
+
+		resp := response{newClientResponse(createResponse.Client), createResponse.Secret}
+		if err := responder.Respond(w, r, http.StatusCreated, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCreate))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileCreate, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationCreate)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,54 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/client"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationDeleteByID = "delete_by_id"
+	FileDeleteByID      = OperationDeleteByID + ".go"
+)
+
+func (s *ClientServer) handleClientDeleteByID() http.HandlerFunc {
+	const self = "handleClientDeleteByID"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		err := s.service.DeleteByID(ctx, client.DeleteByIDRequest{ID: r.PathValue("clientID")})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
+			span.RecordError(err)
+			switch err {
+			case client.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any client with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+		s.clientsDeletedCounter.Add(ctx, 1)
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteByID, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDeleteByID)
+	return otelhandler.ServeHTTP
+}
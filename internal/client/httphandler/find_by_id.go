@@ -0,0 +1,53 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/client"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationFindByID = "find_by_id"
+	FileFindByID      = OperationFindByID + ".go"
+)
+
+func (s *ClientServer) handleClientFindByID() http.HandlerFunc {
+	const self = "handleClientFindByID"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		findResponse, err := s.service.FindByID(ctx, client.FindByIDRequest{ID: r.PathValue("clientID")})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+			span.RecordError(err)
+			switch err {
+			case client.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any client with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, newClientResponse(findResponse.Client)); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFindByID, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationFindByID)
+	return otelhandler.ServeHTTP
+}
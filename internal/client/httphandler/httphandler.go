@@ -0,0 +1,104 @@
+package httphandler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"auth/internal/auth"
+	authrepo "auth/internal/auth/repo/gorm"
+	"auth/internal/client"
+	repo "auth/internal/client/repo/gorm"
+	"auth/internal/user"
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/jkitajima/composer"
+
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const Path = "auth/internal/client/httphandler"
+
+type ClientServer struct {
+	entity                string
+	mux                   *chi.Mux
+	prefix                string
+	service               *client.Service
+	keyring               *auth.Keyring
+	db                    client.Repoer
+	userRepo              user.Repoer
+	authRepo              auth.Repoer
+	inputValidator        *validator.Validate
+	logger                *slog.Logger
+	tracer                trace.Tracer
+	meter                 metric.Meter
+	clientsCreatedCounter metric.Int64Counter
+	clientsDeletedCounter metric.Int64Counter
+}
+
+func (s *ClientServer) Prefix() string {
+	return s.prefix
+}
+
+func (s *ClientServer) Mux() http.Handler {
+	return s.mux
+}
+
+func (s *ClientServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func NewServer(
+	keyring *auth.Keyring,
+	db *gorm.DB,
+	validtr *validator.Validate,
+	logger *slog.Logger,
+	tracer trace.Tracer,
+	meter metric.Meter,
+) (composer.Server, error) {
+	s := &ClientServer{
+		entity:         "clients",
+		prefix:         "/clients",
+		mux:            chi.NewRouter(),
+		keyring:        keyring,
+		db:             repo.NewRepo(db, logger),
+		userRepo:       userrepo.NewRepo(db, logger),
+		authRepo:       authrepo.NewRepo(db, logger),
+		inputValidator: validtr,
+		logger:         logger,
+		tracer:         tracer,
+		meter:          meter,
+	}
+	s.service = &client.Service{Repo: s.db}
+
+	if err := s.instrument(); err != nil {
+		return s, err
+	}
+
+	s.addRoutes()
+	return s, nil
+}
+
+func (s *ClientServer) instrument() error {
+	clientsCreatedCounter, err := s.meter.Int64Counter("clients_created",
+		metric.WithDescription("How many clients has been registered."),
+	)
+	if err != nil {
+		return err
+	}
+	s.clientsCreatedCounter = clientsCreatedCounter
+
+	clientsDeletedCounter, err := s.meter.Int64Counter("clients_deleted",
+		metric.WithDescription("How many clients has been deleted."),
+	)
+	if err != nil {
+		return err
+	}
+	s.clientsDeletedCounter = clientsDeletedCounter
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"auth/internal/auth/guard"
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *ClientServer) addRoutes() {
+	// Operator routes
+	s.mux.Group(func(r chi.Router) {
+		r.Use(guard.Verifier(s.keyring, s.authRepo, s.userRepo))
+		r.Use(guard.Authenticator)
+
+		clientsRead := r.With(guard.RequirePermission(role.PermissionClientsRead))
+		clientsWrite := r.With(guard.RequirePermission(role.PermissionClientsWrite))
+
+		otel.Route(clientsWrite, http.MethodPost, "/", s.handleClientCreate())
+		otel.Route(clientsRead, http.MethodGet, "/{clientID}", s.handleClientFindByID())
+		otel.Route(clientsWrite, http.MethodDelete, "/{clientID}", s.handleClientDeleteByID())
+	})
+}
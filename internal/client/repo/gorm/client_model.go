@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"time"
+)
+
+type ClientModel struct {
+	ID         string `gorm:"primaryKey"`
+	SecretHash string `gorm:"not null;default:''"`
+	Name       string `gorm:"not null"`
+	Public     bool   `gorm:"not null;default:false"`
+	// Space separated lists
+	Grants          string    `gorm:"not null;default:''"`
+	Scopes          string    `gorm:"not null;default:''"`
+	RedirectURIs    string    `gorm:"not null;default:''"`
+	Audiences       string    `gorm:"not null;default:''"`
+	AccessTokenTTL  int       `gorm:"not null;default:0"`
+	RefreshTokenTTL int       `gorm:"not null;default:0"`
+	CreatedAt       time.Time `gorm:"not null"`
+	UpdatedAt       time.Time `gorm:"not null"`
+}
+
+func (*ClientModel) TableName() string {
+	return "Client"
+}
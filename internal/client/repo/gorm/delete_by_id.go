@@ -0,0 +1,28 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/client"
+	"auth/pkg/otel"
+)
+
+const FileDeleteByID = "delete_by_id.go"
+
+func (db *DB) DeleteByID(ctx context.Context, id string) error {
+	const self = "DeleteByID"
+
+	result := db.Where("id = ?", id).Delete(&ClientModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteByID, self, "failed to delete client", result.Error))
+		return client.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return client.ErrNotFoundByID
+	}
+
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteByID, self, fmt.Sprintf("deleted client with id %q", id), nil))
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/client"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindByID = "find_by_id.go"
+
+func (db *DB) FindByID(ctx context.Context, id string) (*client.Client, error) {
+	const self = "FindByID"
+	span := trace.SpanFromContext(ctx)
+
+	var model ClientModel
+	result := db.Where("id = ?", id).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, client.ErrNotFoundByID
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindByID, self, client.ErrNotFoundByID.Error(), result.Error))
+			return nil, client.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned client_id %q", model.ID))
+
+	return &client.Client{
+		ID:              model.ID,
+		SecretHash:      model.SecretHash,
+		Name:            model.Name,
+		Public:          model.Public,
+		Grants:          strings.Fields(model.Grants),
+		Scopes:          strings.Fields(model.Scopes),
+		RedirectURIs:    strings.Fields(model.RedirectURIs),
+		Audiences:       strings.Fields(model.Audiences),
+		AccessTokenTTL:  model.AccessTokenTTL,
+		RefreshTokenTTL: model.RefreshTokenTTL,
+		CreatedAt:       model.CreatedAt,
+		UpdatedAt:       model.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package gorm
+
+import (
+	"log/slog"
+
+	"auth/internal/client"
+
+	"gorm.io/gorm"
+)
+
+const (
+	Path string = "auth/internal/client/repo/gorm"
+)
+
+type DB struct {
+	*gorm.DB
+	logger *slog.Logger
+}
+
+func NewRepo(db *gorm.DB, logger *slog.Logger) client.Repoer {
+	return &DB{db, logger}
+}
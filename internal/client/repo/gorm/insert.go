@@ -0,0 +1,40 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/client"
+	"auth/pkg/otel"
+)
+
+const FileInsert = "insert.go"
+
+func (db *DB) Insert(ctx context.Context, c *client.Client) error {
+	const self = "Insert"
+
+	model := &ClientModel{
+		ID:              c.ID,
+		SecretHash:      c.SecretHash,
+		Name:            c.Name,
+		Public:          c.Public,
+		Grants:          strings.Join(c.Grants, " "),
+		Scopes:          strings.Join(c.Scopes, " "),
+		RedirectURIs:    strings.Join(c.RedirectURIs, " "),
+		Audiences:       strings.Join(c.Audiences, " "),
+		AccessTokenTTL:  c.AccessTokenTTL,
+		RefreshTokenTTL: c.RefreshTokenTTL,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsert, self, "failed to create client", result.Error))
+		return client.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsert, self, fmt.Sprintf("created a new client with id %q", model.ID), nil))
+
+	return nil
+}
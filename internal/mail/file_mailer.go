@@ -0,0 +1,56 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"auth/pkg/otel"
+)
+
+const FileFileMailer = "file_mailer.go"
+
+// FileMailer drops every message as an .eml file into a directory,
+// where it can be opened by any mail client. Useful for local development.
+type FileMailer struct {
+	from   string
+	dir    string
+	logger *slog.Logger
+}
+
+func NewFileMailer(config *Config, logger *slog.Logger) (*FileMailer, error) {
+	if config.FileDir == "" {
+		return nil, fmt.Errorf("mail.file.dir is required for driver %q", DriverFile)
+	}
+	if err := os.MkdirAll(config.FileDir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileMailer{config.From, config.FileDir, logger}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	const self = "Send"
+
+	now := time.Now()
+	data, err := compose(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(m.dir, fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000Z"), suffix))
+
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		m.logger.WarnContext(ctx, otel.FormatLog(Path, FileFileMailer, self, "failed to write message", err))
+		return err
+	}
+	m.logger.InfoContext(ctx, otel.FormatLog(Path, FileFileMailer, self, fmt.Sprintf("wrote message to %q as %q", msg.To, name), nil))
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"auth/pkg/otel"
+)
+
+const FileLogMailer = "log_mailer.go"
+
+// LogMailer is a stand-in Mailer for local development and tests,
+// which writes every message to the logger instead of delivering it.
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func NewLogMailer(logger *slog.Logger) *LogMailer {
+	return &LogMailer{logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	const self = "Send"
+	m.logger.InfoContext(ctx, otel.FormatLog(Path, FileLogMailer, self, fmt.Sprintf("to %q, subject %q:\n%s", msg.To, msg.Subject, msg.Text), nil))
+	return nil
+}
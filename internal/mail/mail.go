@@ -0,0 +1,84 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const Path = "auth/internal/mail"
+
+var (
+	ErrInternal          = errors.New("the mail service encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrUnsupportedDriver = errors.New("mail driver is not supported")
+	ErrTemplateNotFound  = errors.New("could not find any mail template with provided name")
+	ErrInvalidMessage    = errors.New("mail message must have a recipient, a subject and a body")
+)
+
+// Drivers select the transport that actually delivers messages.
+const (
+	DriverLog    = "log"
+	DriverSMTP   = "smtp"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+type Config struct {
+	Driver string
+	From   string
+	// Default locale of rendered templates
+	Locale string
+	// Directory of templates overriding the embedded ones
+	Templates    string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string
+	// Outbox delivery, intervals in seconds
+	OutboxInterval int
+	OutboxAttempts int
+	OutboxBatch    int
+	OutboxBackoff  int
+	OutboxLease    int
+}
+
+// Message is an outbound email.
+// Text is always sent, HTML is optional and sent as an alternative part.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers outbound email messages.
+type Mailer interface {
+	Send(context.Context, Message) error
+}
+
+// OutboxMessage is a message waiting in the outbox for delivery.
+// Failed deliveries are retried at NextAttemptAt until Attempts reaches the configured limit,
+// after which FailedAt is set and the message is not retried anymore.
+type OutboxMessage struct {
+	ID            uuid.UUID
+	Message       Message
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	SentAt        *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time
+}
+
+type Repoer interface {
+	InsertOutboxMessage(context.Context, *OutboxMessage) error
+	// ClaimOutboxMessages leases up to limit messages due for delivery, counting an attempt
+	// and moving their next attempt to the end of the lease. Concurrent replicas skip leased messages,
+	// and a message whose delivery is never recorded, such as after a crash, is claimed again once the lease is over.
+	ClaimOutboxMessages(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*OutboxMessage, error)
+	// UpdateOutboxMessage records the outcome of a delivery attempt.
+	UpdateOutboxMessage(context.Context, *OutboxMessage) error
+}
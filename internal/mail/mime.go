@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// compose encodes msg as an RFC 5322 message with a multipart/alternative body
+// when an HTML version is available.
+func compose(from string, msg Message, now time.Time) ([]byte, error) {
+	if msg.To == "" || msg.Subject == "" || msg.Text == "" {
+		return nil, ErrInvalidMessage
+	}
+	if strings.ContainsAny(msg.To+msg.Subject+from, "\r\n") {
+		return nil, ErrInvalidMessage
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := textproto.MIMEHeader{}
+	header.Set("From", from)
+	header.Set("To", msg.To)
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", now.Format(time.RFC1123Z))
+	header.Set("Message-Id", fmt.Sprintf("<%s@%s>", id, domain(from)))
+	header.Set("Mime-Version", "1.0")
+
+	if msg.HTML == "" {
+		header.Set("Content-Type", `text/plain; charset="utf-8"`)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	boundary, err := randomHex(12)
+	if err != nil {
+		return nil, err
+	}
+	header.Set("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary))
+	writeHeader(&buf, header)
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	}
+	for _, part := range parts {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=\"utf-8\"\r\n", part.contentType)
+		fmt.Fprintf(&buf, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, part.body); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-Id", "Mime-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+// writeQuotedPrintable encodes body, whose line breaks the writer converts to CRLF.
+func writeQuotedPrintable(buf *bytes.Buffer, body string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(body, "\r\n", "\n"))); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// domain returns the domain of an address such as "Auth <no-reply@example.com>".
+func domain(address string) string {
+	address = strings.TrimSuffix(strings.TrimSpace(address), ">")
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
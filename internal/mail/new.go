@@ -0,0 +1,22 @@
+package mail
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// NewTransport returns the Mailer that delivers messages with the configured driver.
+func NewTransport(config *Config, logger *slog.Logger) (Mailer, error) {
+	switch config.Driver {
+	case DriverLog:
+		return NewLogMailer(logger), nil
+	case DriverSMTP:
+		return NewSMTPMailer(config, logger)
+	case DriverFile:
+		return NewFileMailer(config, logger)
+	case DriverMemory:
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("%w (%q)", ErrUnsupportedDriver, config.Driver)
+	}
+}
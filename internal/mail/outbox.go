@@ -0,0 +1,101 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"auth/pkg/otel"
+)
+
+const FileOutbox = "outbox.go"
+
+// Outbox is a Mailer that stores messages durably instead of delivering them right away,
+// so that a mail server outage never fails the operation that sent the message.
+// Deliver hands the stored messages to the transport, retrying failures with exponential backoff.
+type Outbox struct {
+	config    *Config
+	transport Mailer
+	repo      Repoer
+	logger    *slog.Logger
+}
+
+func NewOutbox(config *Config, transport Mailer, repo Repoer, logger *slog.Logger) *Outbox {
+	return &Outbox{config, transport, repo, logger}
+}
+
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" || msg.Subject == "" || msg.Text == "" {
+		return ErrInvalidMessage
+	}
+
+	now := time.Now()
+	return o.repo.InsertOutboxMessage(ctx, &OutboxMessage{
+		Message:       msg,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+type DeliverResponse struct {
+	Sent   int
+	Failed int
+}
+
+// Deliver sends a batch of messages that are due and reschedules the ones that failed.
+// Messages are sent outside of the claim, so that a slow mail server does not hold locks on the outbox,
+// and the outcome of each one is recorded on its own.
+func (o *Outbox) Deliver(ctx context.Context) (DeliverResponse, error) {
+	const self = "Deliver"
+
+	lease := time.Duration(o.config.OutboxLease) * time.Second
+	messages, err := o.repo.ClaimOutboxMessages(ctx, time.Now(), o.config.OutboxBatch, lease)
+	if err != nil {
+		return DeliverResponse{}, err
+	}
+
+	var resp DeliverResponse
+	for _, m := range messages {
+		err := o.transport.Send(ctx, m.Message)
+		now := time.Now()
+
+		if err != nil {
+			resp.Failed++
+			reason := err.Error()
+			m.LastError = &reason
+
+			if m.Attempts >= o.config.OutboxAttempts {
+				m.FailedAt = &now
+				o.logger.ErrorContext(ctx, otel.FormatLog(Path, FileOutbox, self, fmt.Sprintf("giving up on message %q after %d attempts", m.ID, m.Attempts), err))
+			} else {
+				m.NextAttemptAt = now.Add(o.backoff(m.Attempts))
+			}
+		} else {
+			resp.Sent++
+			m.SentAt = &now
+			m.LastError = nil
+		}
+
+		// An outcome that cannot be recorded is retried once the lease is over
+		if err := o.repo.UpdateOutboxMessage(ctx, m); err != nil {
+			o.logger.WarnContext(ctx, otel.FormatLog(Path, FileOutbox, self, fmt.Sprintf("failed to record delivery of message %q", m.ID), err))
+		}
+	}
+
+	return resp, nil
+}
+
+// backoff doubles the delay after every failed attempt, up to a day.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	const ceiling = 24 * time.Hour
+
+	delay := time.Duration(o.config.OutboxBackoff) * time.Second
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= ceiling {
+			return ceiling
+		}
+	}
+	return delay
+}
@@ -0,0 +1,200 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo keeps the outbox in memory, leasing messages like the Postgres repository.
+type memoryRepo struct {
+	messages []*OutboxMessage
+}
+
+func (m *memoryRepo) InsertOutboxMessage(_ context.Context, msg *OutboxMessage) error {
+	msg.ID = uuid.New()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *memoryRepo) ClaimOutboxMessages(_ context.Context, now time.Time, limit int, lease time.Duration) ([]*OutboxMessage, error) {
+	var claimed []*OutboxMessage
+	for _, msg := range m.messages {
+		if len(claimed) == limit {
+			break
+		}
+		if msg.SentAt != nil || msg.FailedAt != nil || msg.NextAttemptAt.After(now) {
+			continue
+		}
+		msg.Attempts++
+		msg.NextAttemptAt = now.Add(lease)
+		c := *msg
+		claimed = append(claimed, &c)
+	}
+	return claimed, nil
+}
+
+func (m *memoryRepo) UpdateOutboxMessage(_ context.Context, msg *OutboxMessage) error {
+	for _, stored := range m.messages {
+		if stored.ID == msg.ID {
+			stored.NextAttemptAt = msg.NextAttemptAt
+			stored.LastError = msg.LastError
+			stored.SentAt = msg.SentAt
+			stored.FailedAt = msg.FailedAt
+		}
+	}
+	return nil
+}
+
+// failingMailer fails every delivery, like a mail server that is down.
+type failingMailer struct{}
+
+func (failingMailer) Send(context.Context, Message) error {
+	return errors.New("connection refused")
+}
+
+func newTestOutbox(transport Mailer) (*Outbox, *memoryRepo) {
+	config := &Config{
+		OutboxAttempts: 3,
+		OutboxBatch:    10,
+		OutboxBackoff:  30,
+		OutboxLease:    300,
+	}
+	repo := &memoryRepo{}
+	return NewOutbox(config, transport, repo, slog.New(slog.NewTextHandler(io.Discard, nil))), repo
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	outbox, _ := newTestOutbox(NewMemoryMailer())
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, 64 * time.Minute},
+		{12, 1024 * time.Minute},
+		{13, 24 * time.Hour},
+		{100, 24 * time.Hour},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, outbox.backoff(test.attempts), "attempts %d", test.attempts)
+	}
+}
+
+func TestOutboxSend(t *testing.T) {
+	ctx := context.Background()
+	outbox, repo := newTestOutbox(NewMemoryMailer())
+
+	err := outbox.Send(ctx, Message{To: "ronaldo@spfc.com", Subject: "Hello"})
+	require.ErrorIs(t, err, ErrInvalidMessage)
+	require.Empty(t, repo.messages)
+
+	err = outbox.Send(ctx, Message{To: "ronaldo@spfc.com", Subject: "Hello", Text: "Hello"})
+	require.NoError(t, err)
+	require.Len(t, repo.messages, 1)
+	require.Zero(t, repo.messages[0].Attempts)
+}
+
+func TestOutboxDeliver(t *testing.T) {
+	ctx := context.Background()
+	msg := Message{To: "ronaldo@spfc.com", Subject: "Hello", Text: "Hello"}
+
+	t.Run("sent", func(t *testing.T) {
+		transport := NewMemoryMailer()
+		outbox, repo := newTestOutbox(transport)
+		require.NoError(t, outbox.Send(ctx, msg))
+
+		resp, err := outbox.Deliver(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DeliverResponse{Sent: 1}, resp)
+		require.Equal(t, []Message{msg}, transport.Messages())
+
+		stored := repo.messages[0]
+		require.Equal(t, 1, stored.Attempts)
+		require.NotNil(t, stored.SentAt)
+		require.Nil(t, stored.LastError)
+
+		// Sent messages are not delivered again
+		resp, err = outbox.Deliver(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DeliverResponse{}, resp)
+		require.Len(t, transport.Messages(), 1)
+	})
+
+	t.Run("retried_with_backoff", func(t *testing.T) {
+		outbox, repo := newTestOutbox(failingMailer{})
+		require.NoError(t, outbox.Send(ctx, msg))
+
+		before := time.Now()
+		resp, err := outbox.Deliver(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DeliverResponse{Failed: 1}, resp)
+
+		stored := repo.messages[0]
+		require.Equal(t, 1, stored.Attempts)
+		require.Nil(t, stored.SentAt)
+		require.Nil(t, stored.FailedAt)
+		require.NotNil(t, stored.LastError)
+		require.Equal(t, "connection refused", *stored.LastError)
+		require.WithinRange(t, stored.NextAttemptAt, before.Add(30*time.Second), time.Now().Add(30*time.Second))
+
+		// Not due until the backoff is over
+		resp, err = outbox.Deliver(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DeliverResponse{}, resp)
+	})
+
+	t.Run("given_up", func(t *testing.T) {
+		outbox, repo := newTestOutbox(failingMailer{})
+		require.NoError(t, outbox.Send(ctx, msg))
+
+		for attempt := 1; attempt <= 3; attempt++ {
+			resp, err := outbox.Deliver(ctx)
+			require.NoError(t, err)
+			require.Equal(t, DeliverResponse{Failed: 1}, resp)
+			require.Equal(t, attempt, repo.messages[0].Attempts)
+
+			// Make the message due again
+			repo.messages[0].NextAttemptAt = time.Now()
+		}
+
+		stored := repo.messages[0]
+		require.NotNil(t, stored.FailedAt)
+		require.Nil(t, stored.SentAt)
+
+		resp, err := outbox.Deliver(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DeliverResponse{}, resp)
+		require.Equal(t, 3, stored.Attempts)
+	})
+
+	t.Run("leased_until_recorded", func(t *testing.T) {
+		outbox, repo := newTestOutbox(NewMemoryMailer())
+		require.NoError(t, outbox.Send(ctx, msg))
+
+		// A message claimed by another replica is not claimed again until its lease is over
+		now := time.Now()
+		claimed, err := repo.ClaimOutboxMessages(ctx, now, 10, 5*time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		resp, err := outbox.Deliver(ctx)
+		require.NoError(t, err)
+		require.Equal(t, DeliverResponse{}, resp)
+
+		claimed, err = repo.ClaimOutboxMessages(ctx, now.Add(5*time.Minute), 10, 5*time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		require.Equal(t, 2, claimed[0].Attempts)
+	})
+}
@@ -0,0 +1,77 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/mail"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileClaimOutboxMessages = "claim_outbox_messages.go"
+
+func (db *DB) ClaimOutboxMessages(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*mail.OutboxMessage, error) {
+	const self = "ClaimOutboxMessages"
+
+	// The transaction only lasts until the messages are leased, messages are sent after it commits
+	var models []OutboxMessageModel
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		result := tx.
+			Model(&OutboxMessageModel{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Pluck("id", &ids)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		result = tx.
+			Model(&models).
+			Clauses(clause.Returning{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": now.Add(lease),
+			})
+		return result.Error
+	})
+	if err != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileClaimOutboxMessages, self, "failed to claim outbox messages", err))
+		return nil, mail.ErrInternal
+	}
+	if len(models) > 0 {
+		db.logger.InfoContext(ctx, otel.FormatLog(Path, FileClaimOutboxMessages, self, fmt.Sprintf("claimed %d outbox messages", len(models)), nil))
+	}
+
+	messages := make([]*mail.OutboxMessage, 0, len(models))
+	for _, model := range models {
+		messages = append(messages, &mail.OutboxMessage{
+			ID: model.ID,
+			Message: mail.Message{
+				To:      model.To,
+				Subject: model.Subject,
+				Text:    model.Text,
+				HTML:    model.HTML,
+			},
+			Attempts:      model.Attempts,
+			NextAttemptAt: model.NextAttemptAt,
+			LastError:     model.LastError,
+			SentAt:        model.SentAt,
+			FailedAt:      model.FailedAt,
+			CreatedAt:     model.CreatedAt,
+		})
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,22 @@
+package gorm
+
+import (
+	"log/slog"
+
+	"auth/internal/mail"
+
+	"gorm.io/gorm"
+)
+
+const (
+	Path string = "auth/internal/mail/repo/gorm"
+)
+
+type DB struct {
+	*gorm.DB
+	logger *slog.Logger
+}
+
+func NewRepo(db *gorm.DB, logger *slog.Logger) mail.Repoer {
+	return &DB{db, logger}
+}
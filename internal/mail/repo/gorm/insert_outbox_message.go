@@ -0,0 +1,35 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/mail"
+	"auth/pkg/otel"
+)
+
+const FileInsertOutboxMessage = "insert_outbox_message.go"
+
+func (db *DB) InsertOutboxMessage(ctx context.Context, m *mail.OutboxMessage) error {
+	const self = "InsertOutboxMessage"
+
+	model := &OutboxMessageModel{
+		To:            m.Message.To,
+		Subject:       m.Message.Subject,
+		Text:          m.Message.Text,
+		HTML:          m.Message.HTML,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		CreatedAt:     m.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertOutboxMessage, self, "failed to insert outbox message", result.Error))
+		return mail.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertOutboxMessage, self, fmt.Sprintf("queued message with id %q", model.ID.String()), nil))
+
+	m.ID = model.ID
+	return nil
+}
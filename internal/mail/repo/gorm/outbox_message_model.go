@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxMessageModel struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	To            string    `gorm:"not null"`
+	Subject       string    `gorm:"not null"`
+	Text          string    `gorm:"not null"`
+	HTML          string
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	LastError     *string
+	SentAt        *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (*OutboxMessageModel) TableName() string {
+	return "MailOutbox"
+}
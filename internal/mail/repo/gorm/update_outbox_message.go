@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/mail"
+	"auth/pkg/otel"
+)
+
+const FileUpdateOutboxMessage = "update_outbox_message.go"
+
+func (db *DB) UpdateOutboxMessage(ctx context.Context, m *mail.OutboxMessage) error {
+	const self = "UpdateOutboxMessage"
+
+	result := db.
+		Model(&OutboxMessageModel{}).
+		Where("id = ?", m.ID).
+		Updates(map[string]any{
+			"next_attempt_at": m.NextAttemptAt,
+			"last_error":      m.LastError,
+			"sent_at":         m.SentAt,
+			"failed_at":       m.FailedAt,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUpdateOutboxMessage, self, "failed to update outbox message", result.Error))
+		return mail.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+
+	"auth/pkg/otel"
+)
+
+const FileSMTPMailer = "smtp_mailer.go"
+
+// SMTPMailer delivers messages to an SMTP relay.
+// The connection is upgraded with STARTTLS whenever the server offers it,
+// and credentials are only sent when a username is configured.
+type SMTPMailer struct {
+	from   string
+	addr   string
+	auth   smtp.Auth
+	logger *slog.Logger
+}
+
+func NewSMTPMailer(config *Config, logger *slog.Logger) (*SMTPMailer, error) {
+	if config.SMTPHost == "" {
+		return nil, fmt.Errorf("mail.smtp.host is required for driver %q", DriverSMTP)
+	}
+	if _, err := netmail.ParseAddress(config.From); err != nil {
+		return nil, fmt.Errorf("mail.from is not a valid address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+
+	return &SMTPMailer{
+		from:   config.From,
+		addr:   net.JoinHostPort(config.SMTPHost, config.SMTPPort),
+		auth:   auth,
+		logger: logger,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	const self = "Send"
+
+	data, err := compose(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Envelope addresses must not carry display names
+	from, err := netmail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil {
+		return ErrInvalidMessage
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, from.Address, []string{to.Address}, data); err != nil {
+		m.logger.WarnContext(ctx, otel.FormatLog(Path, FileSMTPMailer, self, fmt.Sprintf("failed to deliver message to %q", to.Address), err))
+		return err
+	}
+	m.logger.InfoContext(ctx, otel.FormatLog(Path, FileSMTPMailer, self, fmt.Sprintf("delivered message to %q", to.Address), nil))
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Template files are named "<locale>/<name>.<part>.tmpl", where part is
+// "subject" and "txt", rendered with text/template, and the optional "html",
+// rendered with html/template.
+//
+//go:embed templates
+var embedded embed.FS
+
+const (
+	partSubject = "subject"
+	partText    = "txt"
+	partHTML    = "html"
+)
+
+// Templates renders messages from the embedded templates,
+// each of which may be overridden by a file at the same path of a custom directory.
+type Templates struct {
+	locale  string
+	locales map[string]bool
+	text    map[string]*texttemplate.Template
+	html    map[string]*htmltemplate.Template
+}
+
+func NewTemplates(config *Config) (*Templates, error) {
+	t := &Templates{
+		locale:  config.Locale,
+		locales: map[string]bool{},
+		text:    map[string]*texttemplate.Template{},
+		html:    map[string]*htmltemplate.Template{},
+	}
+
+	root, err := fs.Sub(embedded, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := t.load(root); err != nil {
+		return nil, err
+	}
+
+	if config.Templates != "" {
+		if err := t.load(os.DirFS(config.Templates)); err != nil {
+			return nil, err
+		}
+	}
+
+	if !t.locales[t.locale] {
+		return nil, fmt.Errorf("mail.locale %q does not have templates", t.locale)
+	}
+	return t, nil
+}
+
+// Render renders the templates named name into a message for to.
+// The locale falls back to its base language, then to the default locale,
+// so that "pt-PT" uses "pt" templates when there are any and the default ones otherwise.
+func (t *Templates) Render(locale, name, to string, data any) (Message, error) {
+	for _, l := range t.fallbacks(locale) {
+		subject, ok := t.text[key(l, name, partSubject)]
+		if !ok {
+			continue
+		}
+		text, ok := t.text[key(l, name, partText)]
+		if !ok {
+			continue
+		}
+
+		msg := Message{To: to}
+		var buf bytes.Buffer
+		if err := subject.Execute(&buf, data); err != nil {
+			return Message{}, err
+		}
+		msg.Subject = strings.TrimSpace(buf.String())
+
+		buf.Reset()
+		if err := text.Execute(&buf, data); err != nil {
+			return Message{}, err
+		}
+		msg.Text = buf.String()
+
+		if html, ok := t.html[key(l, name, partHTML)]; ok {
+			buf.Reset()
+			if err := html.Execute(&buf, data); err != nil {
+				return Message{}, err
+			}
+			msg.HTML = buf.String()
+		}
+		return msg, nil
+	}
+
+	return Message{}, fmt.Errorf("%w (%q)", ErrTemplateNotFound, name)
+}
+
+func (t *Templates) fallbacks(locale string) []string {
+	fallbacks := make([]string, 0, 3)
+	if locale != "" {
+		fallbacks = append(fallbacks, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			fallbacks = append(fallbacks, base)
+		}
+	}
+	return append(fallbacks, t.locale)
+}
+
+func (t *Templates) load(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".tmpl" {
+			return nil
+		}
+
+		locale, file := path.Split(p)
+		locale = strings.TrimSuffix(locale, "/")
+		name, part, ok := strings.Cut(strings.TrimSuffix(file, ".tmpl"), ".")
+		if !ok || locale == "" || strings.Contains(locale, "/") {
+			return fmt.Errorf("mail template %q must be named <locale>/<name>.<part>.tmpl", p)
+		}
+
+		t.locales[locale] = true
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		switch part {
+		case partSubject, partText:
+			tmpl, err := texttemplate.New(p).Option("missingkey=error").Parse(string(content))
+			if err != nil {
+				return err
+			}
+			t.text[key(locale, name, part)] = tmpl
+		case partHTML:
+			tmpl, err := htmltemplate.New(p).Option("missingkey=error").Parse(string(content))
+			if err != nil {
+				return err
+			}
+			t.html[key(locale, name, part)] = tmpl
+		default:
+			return fmt.Errorf("mail template %q has unknown part %q", p, part)
+		}
+		return nil
+	})
+}
+
+func key(locale, name, part string) string {
+	return locale + "/" + name + "." + part
+}
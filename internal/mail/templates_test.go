@@ -0,0 +1,88 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTemplates(t *testing.T) {
+	_, err := NewTemplates(&Config{Locale: "fr"})
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fr"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fr", "verify_email.body.tmpl"), []byte("Bonjour"), 0o600))
+	_, err = NewTemplates(&Config{Locale: "en", Templates: dir})
+	require.Error(t, err)
+}
+
+func TestTemplatesRender(t *testing.T) {
+	// A custom directory adds a "pt" locale and overrides a single English template
+	dir := t.TempDir()
+	files := map[string]string{
+		"pt/verify_email.subject.tmpl": "Verifique seu email",
+		"pt/verify_email.txt.tmpl":     "Código {{.Code}}",
+		"en/reset_password.txt.tmpl":   "Reset with {{.Link}}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	templates, err := NewTemplates(&Config{Locale: "en", Templates: dir})
+	require.NoError(t, err)
+
+	data := map[string]any{"Code": "123456", "ExpiresIn": 15, "Link": "https://spfc.com/reset"}
+
+	tests := []struct {
+		name    string
+		locale  string
+		tmpl    string
+		subject string
+		html    bool
+	}{
+		{"exact_locale", "pt-BR", "verify_email", "Confirme seu endereço de email", true},
+		{"base_language", "pt-PT", "verify_email", "Verifique seu email", false},
+		{"base_language_without_template", "pt-PT", "reset_password", "", true},
+		{"default_locale", "fr-FR", "verify_email", "Verify your email address", true},
+		{"empty_locale", "", "verify_email", "Verify your email address", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := NewMemoryMailer()
+
+			msg, err := templates.Render(test.locale, test.tmpl, "ronaldo@spfc.com", data)
+			require.NoError(t, err)
+			require.NoError(t, transport.Send(context.Background(), msg))
+
+			sent, ok := transport.Last("ronaldo@spfc.com")
+			require.True(t, ok)
+			if test.subject != "" {
+				require.Equal(t, test.subject, sent.Subject)
+			}
+			require.Equal(t, test.html, sent.HTML != "")
+		})
+	}
+
+	t.Run("overridden_part", func(t *testing.T) {
+		msg, err := templates.Render("en", "reset_password", "ronaldo@spfc.com", data)
+		require.NoError(t, err)
+		require.Equal(t, "Reset with https://spfc.com/reset", msg.Text)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		_, err := templates.Render("pt-BR", "welcome", "ronaldo@spfc.com", data)
+		require.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+
+	t.Run("missing_data", func(t *testing.T) {
+		_, err := templates.Render("en", "verify_email", "ronaldo@spfc.com", map[string]any{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,203 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const FileLimiter = "limiter.go"
+
+// SubjectFunc returns the "sub" claim of the verified access token of a request, if any.
+type SubjectFunc func(*http.Request) (string, bool)
+
+// Limiter throttles requests with a sliding window counter per rule and key.
+type Limiter struct {
+	rules            []Rule
+	repo             Repoer
+	subject          SubjectFunc
+	logger           *slog.Logger
+	throttledCounter metric.Int64Counter
+}
+
+type decision struct {
+	allowed    bool
+	remaining  int
+	reset      time.Duration
+	retryAfter time.Duration
+}
+
+func NewLimiter(config *Config, repo Repoer, subject SubjectFunc, logger *slog.Logger, meter metric.Meter) (*Limiter, error) {
+	l := &Limiter{
+		repo:    repo,
+		subject: subject,
+		logger:  logger,
+	}
+
+	for _, s := range config.Rules {
+		rule, err := ParseRule(s)
+		if err != nil {
+			return nil, err
+		}
+		l.rules = append(l.rules, rule)
+	}
+
+	throttledCounter, err := meter.Int64Counter("requests_throttled",
+		metric.WithDescription("How many requests was rejected by the rate limiter, by rule."),
+	)
+	if err != nil {
+		return nil, err
+	}
+	l.throttledCounter = throttledCounter
+
+	return l, nil
+}
+
+// Middleware applies the first rule matching the request, if any.
+// Requests are let through when the counters cannot be reached, so that an outage
+// of the store does not take the whole service down.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	const self = "Middleware"
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		rule, ok := l.match(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d, err := l.allow(ctx, rule, l.key(rule, r), time.Now())
+		if err != nil {
+			l.logger.WarnContext(ctx, otel.FormatLog(Path, FileLimiter, self, fmt.Sprintf("failed to apply rate limit rule %q", rule), err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.Limit, int(rule.Period.Seconds())))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(rule.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(d.remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(d.reset)))
+
+		if !d.allowed {
+			l.throttledCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("rule", rule.String())))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(d.retryAfter)))
+			responder.RespondMetaMessage(w, r, http.StatusTooManyRequests, "Too many requests. Try again later.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+func (l *Limiter) match(r *http.Request) (Rule, bool) {
+	for _, rule := range l.rules {
+		if rule.Matches(r) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// key returns the counter key of the request for the rule.
+// Client IDs are sent before the client is authenticated, so requests are counted
+// by IP and client together to keep a caller from draining the budget of another client.
+// Requests without a subject or a client are counted by IP instead.
+func (l *Limiter) key(rule Rule, r *http.Request) string {
+	prefix := rule.String() + "|"
+
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	switch rule.Key {
+	case KeySubject:
+		if l.subject != nil {
+			if sub, ok := l.subject(r); ok {
+				return prefix + KeySubject + ":" + sub
+			}
+		}
+	case KeyClient:
+		if clientID := clientID(r); clientID != "" {
+			return prefix + KeyIP + ":" + ip + "|" + KeyClient + ":" + clientID
+		}
+	}
+
+	return prefix + KeyIP + ":" + ip
+}
+
+// clientID returns the client ID sent with HTTP Basic authentication or in the form body.
+func clientID(r *http.Request) string {
+	if clientID, _, ok := r.BasicAuth(); ok && clientID != "" {
+		return clientID
+	}
+	if isForm(r) {
+		return r.PostFormValue("client_id")
+	}
+	return ""
+}
+
+func isForm(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
+
+// allow counts a hit and estimates the rate over the sliding window ending now,
+// weighting the previous fixed window by how much of it still overlaps.
+func (l *Limiter) allow(ctx context.Context, rule Rule, key string, now time.Time) (decision, error) {
+	windowStart := now.Truncate(rule.Period)
+	counter, err := l.repo.HitCounter(ctx, key, windowStart, rule.Period)
+	if err != nil {
+		return decision{}, err
+	}
+
+	elapsed := now.Sub(windowStart)
+	overlap := 1 - float64(elapsed)/float64(rule.Period)
+	limit := float64(rule.Limit)
+	current := float64(counter.Current)
+	previous := float64(counter.Previous)
+	rate := previous*overlap + current
+
+	d := decision{
+		allowed:   rate <= limit,
+		remaining: max(rule.Limit-int(math.Ceil(rate)), 0),
+		reset:     rule.Period - elapsed,
+	}
+	if d.allowed {
+		return d, nil
+	}
+
+	// Time until the estimate falls back under the limit without further hits
+	switch {
+	case current < limit && previous > 0:
+		until := time.Duration(float64(rule.Period) * (1 - (limit-current)/previous))
+		d.retryAfter = until - elapsed
+	default:
+		d.retryAfter = d.reset + time.Duration(float64(rule.Period)*(1-limit/current))
+	}
+	return d, nil
+}
+
+func seconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
+
+// Purge removes counters whose windows are too old to be counted anymore.
+func (l *Limiter) Purge(ctx context.Context) (int64, error) {
+	return l.repo.DeleteExpiredCounters(ctx, time.Now())
+}
@@ -0,0 +1,192 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	rule := Rule{Method: "*", Path: "/auth/token", Limit: 2, Period: 10 * time.Second, Key: KeyIP}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		counter    *Counter
+		now        time.Time
+		allowed    bool
+		remaining  int
+		reset      time.Duration
+		retryAfter int
+	}{
+		{
+			name:      "first_hit",
+			now:       start,
+			allowed:   true,
+			remaining: 1,
+			reset:     10 * time.Second,
+		},
+		{
+			name:      "at_limit",
+			counter:   &Counter{WindowStart: start, Current: 1},
+			now:       start.Add(4 * time.Second),
+			allowed:   true,
+			remaining: 0,
+			reset:     6 * time.Second,
+		},
+		{
+			// 3 hits in the current window, the estimate falls to 2 once the next window
+			// is a third over: 5s until it starts plus 10s * (1 - 2/3)
+			name:       "over_limit_in_current_window",
+			counter:    &Counter{WindowStart: start, Current: 2},
+			now:        start.Add(5 * time.Second),
+			allowed:    false,
+			remaining:  0,
+			reset:      5 * time.Second,
+			retryAfter: 9,
+		},
+		{
+			// Rate is 4 * 0.8 + 1, it falls to 2 once the previous window weighs 1/4,
+			// that is 7.5s into the window
+			name:       "over_limit_with_previous_window",
+			counter:    &Counter{WindowStart: start.Add(-10 * time.Second), Current: 4},
+			now:        start.Add(2 * time.Second),
+			allowed:    false,
+			remaining:  0,
+			reset:      8 * time.Second,
+			retryAfter: 6,
+		},
+		{
+			name:      "previous_window_weighted_by_overlap",
+			counter:   &Counter{WindowStart: start.Add(-10 * time.Second), Current: 2},
+			now:       start.Add(5 * time.Second),
+			allowed:   true,
+			remaining: 0,
+			reset:     5 * time.Second,
+		},
+		{
+			name:      "stale_counter",
+			counter:   &Counter{WindowStart: start.Add(-20 * time.Second), Current: 5},
+			now:       start.Add(time.Second),
+			allowed:   true,
+			remaining: 1,
+			reset:     9 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := NewMemoryRepo()
+			if test.counter != nil {
+				test.counter.Key = "key"
+				repo.counters["key"] = test.counter
+			}
+			l := &Limiter{repo: repo}
+
+			d, err := l.allow(context.Background(), rule, "key", test.now)
+			require.NoError(t, err)
+			require.Equal(t, test.allowed, d.allowed)
+			require.Equal(t, test.remaining, d.remaining)
+			require.Equal(t, test.reset, d.reset)
+			if !test.allowed {
+				require.Equal(t, test.retryAfter, seconds(d.retryAfter))
+			}
+		})
+	}
+}
+
+func TestLimiterKey(t *testing.T) {
+	subject := func(r *http.Request) (string, bool) {
+		sub := r.Header.Get("X-Subject")
+		return sub, sub != ""
+	}
+	l := &Limiter{subject: subject}
+
+	form := func(contentType string, values url.Values) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/auth/token", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", contentType)
+		r.RemoteAddr = "203.0.113.7:4321"
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		request func() *http.Request
+		want    string
+	}{
+		{
+			name: "ip",
+			key:  KeyIP,
+			request: func() *http.Request {
+				return form("application/x-www-form-urlencoded", url.Values{"client_id": {"app"}})
+			},
+			want: "ip:203.0.113.7",
+		},
+		{
+			name: "subject",
+			key:  KeySubject,
+			request: func() *http.Request {
+				r := form("application/x-www-form-urlencoded", nil)
+				r.Header.Set("X-Subject", "ronaldo")
+				return r
+			},
+			want: "sub:ronaldo",
+		},
+		{
+			name: "subject_without_token",
+			key:  KeySubject,
+			request: func() *http.Request {
+				return form("application/x-www-form-urlencoded", nil)
+			},
+			want: "ip:203.0.113.7",
+		},
+		{
+			name: "client_basic_auth",
+			key:  KeyClient,
+			request: func() *http.Request {
+				r := form("application/x-www-form-urlencoded", nil)
+				r.SetBasicAuth("app", "secret")
+				return r
+			},
+			want: "ip:203.0.113.7|client:app",
+		},
+		{
+			name: "client_form",
+			key:  KeyClient,
+			request: func() *http.Request {
+				return form("application/x-www-form-urlencoded", url.Values{"client_id": {"app"}})
+			},
+			want: "ip:203.0.113.7|client:app",
+		},
+		{
+			name: "client_form_with_charset",
+			key:  KeyClient,
+			request: func() *http.Request {
+				return form("application/x-www-form-urlencoded; charset=UTF-8", url.Values{"client_id": {"app"}})
+			},
+			want: "ip:203.0.113.7|client:app",
+		},
+		{
+			name: "client_not_form",
+			key:  KeyClient,
+			request: func() *http.Request {
+				return form("application/json", url.Values{"client_id": {"app"}})
+			},
+			want: "ip:203.0.113.7",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := Rule{Method: "POST", Path: "/auth/token", Limit: 10, Period: time.Minute, Key: test.key}
+			require.Equal(t, rule.String()+"|"+test.want, l.key(rule, test.request()))
+		})
+	}
+}
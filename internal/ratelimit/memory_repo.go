@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepo keeps counters in the memory of the replica.
+// Every replica enforces the limits on its own share of the traffic.
+type MemoryRepo struct {
+	mu       sync.Mutex
+	counters map[string]*Counter
+}
+
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{counters: make(map[string]*Counter)}
+}
+
+func (m *MemoryRepo) HitCounter(ctx context.Context, key string, windowStart time.Time, period time.Duration) (*Counter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &Counter{Key: key}
+		m.counters[key] = counter
+	}
+	rollCounter(counter, windowStart, period)
+
+	c := *counter
+	return &c, nil
+}
+
+func (m *MemoryRepo) DeleteExpiredCounters(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for key, counter := range m.counters {
+		if !counter.ExpiresAt.After(now) {
+			delete(m.counters, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// rollCounter counts a hit, moving the current window to the previous one when a new window has started.
+// Counters are only needed until the window following their current one is over.
+func rollCounter(c *Counter, windowStart time.Time, period time.Duration) {
+	switch {
+	case c.WindowStart.Equal(windowStart):
+		c.Current++
+	case c.WindowStart.Add(period).Equal(windowStart):
+		c.Previous = c.Current
+		c.Current = 1
+	default:
+		c.Previous = 0
+		c.Current = 1
+	}
+	c.WindowStart = windowStart
+	c.ExpiresAt = windowStart.Add(2 * period)
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepoHitCounter(t *testing.T) {
+	ctx := context.Background()
+	period := 10 * time.Second
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	hits := []struct {
+		name        string
+		key         string
+		windowStart time.Time
+		current     int
+		previous    int
+	}{
+		{"first_hit", "a", start, 1, 0},
+		{"same_window", "a", start, 2, 0},
+		{"other_key", "b", start, 1, 0},
+		{"next_window", "a", start.Add(period), 1, 2},
+		{"next_window_again", "a", start.Add(period), 2, 2},
+		{"skipped_window", "a", start.Add(3 * period), 1, 0},
+	}
+
+	repo := NewMemoryRepo()
+	for _, hit := range hits {
+		t.Run(hit.name, func(t *testing.T) {
+			counter, err := repo.HitCounter(ctx, hit.key, hit.windowStart, period)
+			require.NoError(t, err)
+			require.Equal(t, hit.key, counter.Key)
+			require.Equal(t, hit.current, counter.Current)
+			require.Equal(t, hit.previous, counter.Previous)
+			require.Equal(t, hit.windowStart, counter.WindowStart)
+			require.Equal(t, hit.windowStart.Add(2*period), counter.ExpiresAt)
+		})
+	}
+}
+
+func TestMemoryRepoDeleteExpiredCounters(t *testing.T) {
+	ctx := context.Background()
+	period := 10 * time.Second
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := NewMemoryRepo()
+	_, err := repo.HitCounter(ctx, "old", start, period)
+	require.NoError(t, err)
+	_, err = repo.HitCounter(ctx, "new", start.Add(period), period)
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteExpiredCounters(ctx, start.Add(2*period))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	require.NotContains(t, repo.counters, "old")
+	require.Contains(t, repo.counters, "new")
+
+	deleted, err = repo.DeleteExpiredCounters(ctx, start.Add(2*period))
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const Path = "auth/internal/ratelimit"
+
+var (
+	ErrInternal         = errors.New("the rate limiter encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrUnsupportedStore = errors.New("rate limit store is not supported")
+	ErrInvalidRule      = errors.New(`rate limit rule must look like "[METHOD] PATH LIMIT/PERIOD [ip|sub|client]"`)
+)
+
+// Stores keep the counters of the limiter.
+// Counters in memory are local to a replica, counters in Postgres are shared by every replica.
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// Keys select what requests are counted against.
+const (
+	KeyIP      = "ip"
+	KeySubject = "sub"
+	KeyClient  = "client"
+)
+
+type Config struct {
+	Store string
+	// Rules as "[METHOD] PATH LIMIT/PERIOD [KEY]", the first matching rule applies
+	Rules []string
+	// Seconds between purges of expired counters
+	Purge int
+}
+
+// Counter holds the hits of a key in the current fixed window and in the one before it,
+// from which the sliding window rate is estimated.
+type Counter struct {
+	Key         string
+	WindowStart time.Time
+	Current     int
+	Previous    int
+	ExpiresAt   time.Time
+}
+
+type Repoer interface {
+	// HitCounter counts a hit in the window starting at windowStart, rolling the counter over
+	// when the window has changed since the last hit.
+	HitCounter(ctx context.Context, key string, windowStart time.Time, period time.Duration) (*Counter, error)
+	DeleteExpiredCounters(context.Context, time.Time) (int64, error)
+}
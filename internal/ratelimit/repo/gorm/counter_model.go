@@ -0,0 +1,17 @@
+package gorm
+
+import (
+	"time"
+)
+
+type CounterModel struct {
+	Key         string    `gorm:"primaryKey"`
+	WindowStart time.Time `gorm:"not null"`
+	Current     int       `gorm:"not null"`
+	Previous    int       `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+func (*CounterModel) TableName() string {
+	return "RateLimitCounter"
+}
@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/ratelimit"
+	"auth/pkg/otel"
+)
+
+const FileDeleteExpiredCounters = "delete_expired_counters.go"
+
+func (db *DB) DeleteExpiredCounters(ctx context.Context, now time.Time) (int64, error) {
+	const self = "DeleteExpiredCounters"
+
+	result := db.Where("expires_at <= ?", now).Delete(&CounterModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteExpiredCounters, self, "failed to delete expired rate limit counters", result.Error))
+		return 0, ratelimit.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteExpiredCounters, self, fmt.Sprintf("deleted %d expired rate limit counters", result.RowsAffected), nil))
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,22 @@
+package gorm
+
+import (
+	"log/slog"
+
+	"auth/internal/ratelimit"
+
+	"gorm.io/gorm"
+)
+
+const (
+	Path string = "auth/internal/ratelimit/repo/gorm"
+)
+
+type DB struct {
+	*gorm.DB
+	logger *slog.Logger
+}
+
+func NewRepo(db *gorm.DB, logger *slog.Logger) ratelimit.Repoer {
+	return &DB{db, logger}
+}
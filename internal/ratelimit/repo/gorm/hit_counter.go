@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/ratelimit"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileHitCounter = "hit_counter.go"
+
+func (db *DB) HitCounter(ctx context.Context, key string, windowStart time.Time, period time.Duration) (*ratelimit.Counter, error) {
+	const self = "HitCounter"
+
+	model := &CounterModel{
+		Key:         key,
+		WindowStart: windowStart,
+		Current:     1,
+		Previous:    0,
+		ExpiresAt:   windowStart.Add(2 * period),
+	}
+
+	// Every assignment reads the row as it was before the update,
+	// so the counter is rolled over and incremented in a single atomic statement
+	result := db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"previous":     gorm.Expr(`CASE WHEN "RateLimitCounter".window_start = ? THEN "RateLimitCounter".previous WHEN "RateLimitCounter".window_start = ? THEN "RateLimitCounter".current ELSE 0 END`, windowStart, windowStart.Add(-period)),
+				"current":      gorm.Expr(`CASE WHEN "RateLimitCounter".window_start = ? THEN "RateLimitCounter".current + 1 ELSE 1 END`, windowStart),
+				"window_start": windowStart,
+				"expires_at":   model.ExpiresAt,
+			}),
+		},
+		clause.Returning{},
+	).Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileHitCounter, self, "failed to hit rate limit counter", result.Error))
+		return nil, ratelimit.ErrInternal
+	}
+
+	return &ratelimit.Counter{
+		Key:         model.Key,
+		WindowStart: model.WindowStart,
+		Current:     model.Current,
+		Previous:    model.Previous,
+		ExpiresAt:   model.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule limits the requests matching a method and a path to Limit per Period and Key.
+// A path ending in "/*" matches every path under it.
+type Rule struct {
+	Method string
+	Path   string
+	Limit  int
+	Period time.Duration
+	Key    string
+}
+
+// ParseRule parses rules such as "POST /auth/register 5/1m ip" or "/users/* 100/1m sub".
+// The method defaults to any method and the key to the client IP.
+func ParseRule(s string) (Rule, error) {
+	fields := strings.Fields(s)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
+		fields = append([]string{"*"}, fields...)
+	}
+	if len(fields) == 3 {
+		fields = append(fields, KeyIP)
+	}
+	if len(fields) != 4 {
+		return Rule{}, fmt.Errorf("%w (%q)", ErrInvalidRule, s)
+	}
+
+	rule := Rule{
+		Method: strings.ToUpper(fields[0]),
+		Path:   fields[1],
+		Key:    fields[3],
+	}
+	if !strings.HasPrefix(rule.Path, "/") {
+		return Rule{}, fmt.Errorf("%w (%q)", ErrInvalidRule, s)
+	}
+
+	limit, period, ok := strings.Cut(fields[2], "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("%w (%q)", ErrInvalidRule, s)
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return Rule{}, fmt.Errorf("%w (%q)", ErrInvalidRule, s)
+	}
+	rule.Limit = n
+
+	// A bare unit such as "m" means a single one of it
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d < time.Second {
+		return Rule{}, fmt.Errorf("%w (%q)", ErrInvalidRule, s)
+	}
+	rule.Period = d
+
+	switch rule.Key {
+	case KeyIP, KeySubject, KeyClient:
+	default:
+		return Rule{}, fmt.Errorf("%w (%q)", ErrInvalidRule, s)
+	}
+
+	return rule, nil
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s %s %d/%s %s", r.Method, r.Path, r.Limit, r.Period, r.Key)
+}
+
+// Matches reports whether the rule applies to the request.
+func (r Rule) Matches(req *http.Request) bool {
+	if r.Method != "*" && r.Method != req.Method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "/*"); ok {
+		return req.URL.Path == prefix || strings.HasPrefix(req.URL.Path, prefix+"/")
+	}
+	return req.URL.Path == r.Path
+}
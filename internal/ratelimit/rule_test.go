@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		want Rule
+	}{
+		{"full", "POST /auth/register 5/1m ip", Rule{Method: "POST", Path: "/auth/register", Limit: 5, Period: time.Minute, Key: KeyIP}},
+		{"any_method", "/users/* 100/1m sub", Rule{Method: "*", Path: "/users/*", Limit: 100, Period: time.Minute, Key: KeySubject}},
+		{"default_key", "post /auth/token 10/30s", Rule{Method: "POST", Path: "/auth/token", Limit: 10, Period: 30 * time.Second, Key: KeyIP}},
+		{"bare_unit", "/auth/oauth/introspect 600/m client", Rule{Method: "*", Path: "/auth/oauth/introspect", Limit: 600, Period: time.Minute, Key: KeyClient}},
+		{"path_only_default_key", "/healthz 1/1h", Rule{Method: "*", Path: "/healthz", Limit: 1, Period: time.Hour, Key: KeyIP}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := ParseRule(test.rule)
+			require.NoError(t, err)
+			require.Equal(t, test.want, rule)
+		})
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"empty", ""},
+		{"missing_limit", "POST /auth/register"},
+		{"too_many_fields", "POST /auth/register 5/1m ip extra"},
+		{"relative_path", "POST auth/register 5/1m"},
+		{"missing_period", "POST /auth/register 5 ip"},
+		{"zero_limit", "POST /auth/register 0/1m"},
+		{"negative_limit", "POST /auth/register -1/1m"},
+		{"invalid_period", "POST /auth/register 5/fortnight"},
+		{"period_under_a_second", "POST /auth/register 5/500ms"},
+		{"unknown_key", "POST /auth/register 5/1m email"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseRule(test.rule)
+			require.ErrorIs(t, err, ErrInvalidRule)
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		method string
+		path   string
+		want   bool
+	}{
+		{"exact", "POST /auth/token 10/1m", "POST", "/auth/token", true},
+		{"other_method", "POST /auth/token 10/1m", "GET", "/auth/token", false},
+		{"other_path", "POST /auth/token 10/1m", "POST", "/auth/tokens", false},
+		{"any_method", "/auth/token 10/1m", "DELETE", "/auth/token", true},
+		{"wildcard_root", "/users/* 10/1m", "GET", "/users", true},
+		{"wildcard_child", "/users/* 10/1m", "GET", "/users/me/sessions", true},
+		{"wildcard_sibling", "/users/* 10/1m", "GET", "/usersx", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := ParseRule(test.rule)
+			require.NoError(t, err)
+			require.Equal(t, test.want, rule.Matches(httptest.NewRequest(test.method, test.path, nil)))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type AssignUserRoleRequest struct {
+	UserID uuid.UUID
+	RoleID uuid.UUID
+}
+
+// AssignUserRole grants the permissions of a role to a user, along with its next access token.
+// The caller is expected to check that the user exists.
+func (s *Service) AssignUserRole(ctx context.Context, req AssignUserRoleRequest) error {
+	if _, err := s.Repo.FindRoleByID(ctx, req.RoleID); err != nil {
+		return err
+	}
+	return s.Repo.AssignUserRole(ctx, req.UserID, req.RoleID)
+}
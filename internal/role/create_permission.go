@@ -0,0 +1,32 @@
+package role
+
+import (
+	"context"
+	"time"
+)
+
+type CreatePermissionRequest struct {
+	Name        string
+	Description string
+}
+
+type CreatePermissionResponse struct {
+	Permission *Permission
+}
+
+// CreatePermission registers a permission that roles can grant.
+func (s *Service) CreatePermission(ctx context.Context, req CreatePermissionRequest) (CreatePermissionResponse, error) {
+	if !permissionPattern.MatchString(req.Name) {
+		return CreatePermissionResponse{}, ErrInvalidPermission
+	}
+
+	p := &Permission{
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.Repo.InsertPermission(ctx, p); err != nil {
+		return CreatePermissionResponse{}, err
+	}
+	return CreatePermissionResponse{p}, nil
+}
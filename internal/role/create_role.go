@@ -0,0 +1,44 @@
+package role
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateRoleRequest struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type CreateRoleResponse struct {
+	Role *Role
+}
+
+// CreateRole creates a role granting existing permissions.
+// It fails with ErrPermissionNotFound when any of them does not exist.
+func (s *Service) CreateRole(ctx context.Context, req CreateRoleRequest) (CreateRoleResponse, error) {
+	if !namePattern.MatchString(req.Name) {
+		return CreateRoleResponse{}, ErrInvalidName
+	}
+	permissions, err := validPermissions(req.Permissions)
+	if err != nil {
+		return CreateRoleResponse{}, err
+	}
+
+	now := time.Now()
+	r := &Role{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.Repo.InsertRole(ctx, r); err != nil {
+		return CreateRoleResponse{}, err
+	}
+	return CreateRoleResponse{r}, nil
+}
@@ -0,0 +1,19 @@
+package role
+
+import (
+	"context"
+	"slices"
+)
+
+type DeletePermissionRequest struct {
+	Name string
+}
+
+// DeletePermission deletes a permission, which is no longer granted by any role.
+// Built-in permissions cannot be deleted, since they would be created again at startup.
+func (s *Service) DeletePermission(ctx context.Context, req DeletePermissionRequest) error {
+	if slices.Contains(BuiltinPermissions, req.Name) {
+		return ErrBuiltinPermission
+	}
+	return s.Repo.DeletePermission(ctx, req.Name)
+}
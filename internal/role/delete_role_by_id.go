@@ -0,0 +1,16 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type DeleteRoleByIDRequest struct {
+	ID uuid.UUID
+}
+
+// DeleteRoleByID deletes a role, which is unassigned from every user.
+func (s *Service) DeleteRoleByID(ctx context.Context, req DeleteRoleByIDRequest) error {
+	return s.Repo.DeleteRoleByID(ctx, req.ID)
+}
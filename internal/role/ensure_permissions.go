@@ -0,0 +1,10 @@
+package role
+
+import (
+	"context"
+)
+
+// EnsureBuiltinPermissions creates the permissions checked by the service, so that roles can grant them.
+func (s *Service) EnsureBuiltinPermissions(ctx context.Context) error {
+	return s.Repo.EnsurePermissions(ctx, BuiltinPermissions)
+}
@@ -0,0 +1,23 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type FindRoleByIDRequest struct {
+	ID uuid.UUID
+}
+
+type FindRoleByIDResponse struct {
+	Role *Role
+}
+
+func (s *Service) FindRoleByID(ctx context.Context, req FindRoleByIDRequest) (FindRoleByIDResponse, error) {
+	r, err := s.Repo.FindRoleByID(ctx, req.ID)
+	if err != nil {
+		return FindRoleByIDResponse{nil}, err
+	}
+	return FindRoleByIDResponse{r}, nil
+}
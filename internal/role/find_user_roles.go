@@ -0,0 +1,32 @@
+package role
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type FindUserRolesRequest struct {
+	UserID uuid.UUID
+}
+
+type FindUserRolesResponse struct {
+	Roles []*Role
+	// Sorted union of the permissions of the roles
+	Permissions []string
+}
+
+// FindUserRoles returns the roles assigned to a user and the permissions they grant.
+func (s *Service) FindUserRoles(ctx context.Context, req FindUserRolesRequest) (FindUserRolesResponse, error) {
+	roles, err := s.Repo.FindUserRoles(ctx, req.UserID)
+	if err != nil {
+		return FindUserRolesResponse{}, err
+	}
+
+	var permissions []string
+	for _, r := range roles {
+		permissions = append(permissions, r.Permissions...)
+	}
+	return FindUserRolesResponse{roles, slices.Compact(slices.Sorted(slices.Values(permissions)))}, nil
+}
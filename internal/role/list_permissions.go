@@ -0,0 +1,18 @@
+package role
+
+import (
+	"context"
+)
+
+type ListPermissionsResponse struct {
+	Permissions []*Permission
+}
+
+// ListPermissions returns every permission, sorted by name.
+func (s *Service) ListPermissions(ctx context.Context) (ListPermissionsResponse, error) {
+	permissions, err := s.Repo.ListPermissions(ctx)
+	if err != nil {
+		return ListPermissionsResponse{}, err
+	}
+	return ListPermissionsResponse{permissions}, nil
+}
@@ -0,0 +1,18 @@
+package role
+
+import (
+	"context"
+)
+
+type ListRolesResponse struct {
+	Roles []*Role
+}
+
+// ListRoles returns every role along with its permissions, sorted by name.
+func (s *Service) ListRoles(ctx context.Context) (ListRolesResponse, error) {
+	roles, err := s.Repo.ListRoles(ctx)
+	if err != nil {
+		return ListRolesResponse{}, err
+	}
+	return ListRolesResponse{roles}, nil
+}
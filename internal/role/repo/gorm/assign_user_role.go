@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+const FileAssignUserRole = "assign_user_role.go"
+
+func (db *DB) AssignUserRole(ctx context.Context, userID, roleID uuid.UUID) error {
+	const self = "AssignUserRole"
+
+	model := &UserRoleModel{
+		UserID:    userID,
+		RoleID:    roleID,
+		CreatedAt: time.Now(),
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileAssignUserRole, self, "failed to assign role", result.Error))
+		return role.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileAssignUserRole, self, fmt.Sprintf("assigned role %q to user %q", roleID.String(), userID.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+)
+
+const FileDeletePermission = "delete_permission.go"
+
+func (db *DB) DeletePermission(ctx context.Context, name string) error {
+	const self = "DeletePermission"
+
+	// Grants of the permission are removed by the cascading foreign key
+	result := db.Where("name = ?", name).Delete(&PermissionModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeletePermission, self, "failed to delete permission", result.Error))
+		return role.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return role.ErrPermissionNotFound
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeletePermission, self, fmt.Sprintf("deleted permission %q", name), nil))
+
+	return nil
+}
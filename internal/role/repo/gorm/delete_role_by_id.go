@@ -0,0 +1,31 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileDeleteRoleByID = "delete_role_by_id.go"
+
+func (db *DB) DeleteRoleByID(ctx context.Context, id uuid.UUID) error {
+	const self = "DeleteRoleByID"
+
+	// Grants and assignments of the role are removed by the cascading foreign keys
+	result := db.Where("id = ?", id).Delete(&RoleModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDeleteRoleByID, self, "failed to delete role", result.Error))
+		return role.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return role.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDeleteRoleByID, self, fmt.Sprintf("deleted role with id %q", id.String()), nil))
+
+	return nil
+}
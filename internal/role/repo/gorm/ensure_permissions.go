@@ -0,0 +1,31 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm/clause"
+)
+
+const FileEnsurePermissions = "ensure_permissions.go"
+
+func (db *DB) EnsurePermissions(ctx context.Context, names []string) error {
+	const self = "EnsurePermissions"
+
+	now := time.Now()
+	models := make([]*PermissionModel, 0, len(names))
+	for _, name := range names {
+		models = append(models, &PermissionModel{Name: name, CreatedAt: now})
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileEnsurePermissions, self, "failed to create permissions", result.Error))
+		return role.ErrInternal
+	}
+
+	return nil
+}
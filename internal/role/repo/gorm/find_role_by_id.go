@@ -0,0 +1,41 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindRoleByID = "find_role_by_id.go"
+
+func (db *DB) FindRoleByID(ctx context.Context, id uuid.UUID) (*role.Role, error) {
+	const self = "FindRoleByID"
+	span := trace.SpanFromContext(ctx)
+
+	var model RoleModel
+	result := db.First(&model, "id = ?", id.String())
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, role.ErrNotFoundByID
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindRoleByID, self, role.ErrNotFoundByID.Error(), result.Error))
+			return nil, role.ErrInternal
+		}
+	}
+	span.AddEvent(fmt.Sprintf("db query returned role_id %q", id.String()))
+
+	roles, err := db.withPermissions([]RoleModel{model})
+	if err != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindRoleByID, self, "failed to find role permissions", err))
+		return nil, role.ErrInternal
+	}
+	return roles[0], nil
+}
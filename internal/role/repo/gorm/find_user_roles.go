@@ -0,0 +1,39 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const FileFindUserRoles = "find_user_roles.go"
+
+func (db *DB) FindUserRoles(ctx context.Context, userID uuid.UUID) ([]*role.Role, error) {
+	const self = "FindUserRoles"
+	span := trace.SpanFromContext(ctx)
+
+	var models []RoleModel
+	result := db.
+		Joins(`JOIN "UserRole" ON "UserRole".role_id = "Role".id`).
+		Where(`"UserRole".user_id = ?`, userID).
+		Order(`"Role".name`).
+		Find(&models)
+	if result.Error != nil {
+		span.AddEvent("db query failed")
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindUserRoles, self, "failed to find user roles", result.Error))
+		return nil, role.ErrInternal
+	}
+	span.AddEvent(fmt.Sprintf("db query returned %d roles of user_id %q", len(models), userID.String()))
+
+	roles, err := db.withPermissions(models)
+	if err != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindUserRoles, self, "failed to find role permissions", err))
+		return nil, role.ErrInternal
+	}
+	return roles, nil
+}
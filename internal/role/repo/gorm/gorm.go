@@ -0,0 +1,22 @@
+package gorm
+
+import (
+	"log/slog"
+
+	"auth/internal/role"
+
+	"gorm.io/gorm"
+)
+
+const (
+	Path string = "auth/internal/role/repo/gorm"
+)
+
+type DB struct {
+	*gorm.DB
+	logger *slog.Logger
+}
+
+func NewRepo(db *gorm.DB, logger *slog.Logger) role.Repoer {
+	return &DB{db, logger}
+}
@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const FileInsertPermission = "insert_permission.go"
+
+func (db *DB) InsertPermission(ctx context.Context, p *role.Permission) error {
+	const self = "InsertPermission"
+
+	model := &PermissionModel{
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedAt:   p.CreatedAt,
+	}
+
+	result := db.Create(model)
+	if result.Error != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
+			return role.ErrPermissionAlreadyExists
+		}
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertPermission, self, "failed to create permission", result.Error))
+		return role.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertPermission, self, fmt.Sprintf("created permission %q", model.Name), nil))
+
+	return nil
+}
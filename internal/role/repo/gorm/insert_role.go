@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const FileInsertRole = "insert_role.go"
+
+func (db *DB) InsertRole(ctx context.Context, r *role.Role) error {
+	const self = "InsertRole"
+
+	model := &RoleModel{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		if len(r.Permissions) == 0 {
+			return nil
+		}
+		return tx.Create(grantModels(model.ID, r.Permissions)).Error
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return role.ErrNameAlreadyInUse
+			case "23503":
+				return role.ErrPermissionNotFound
+			}
+		}
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileInsertRole, self, "failed to create role", err))
+		return role.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileInsertRole, self, fmt.Sprintf("created role %q with id %q", model.Name, model.ID.String()), nil))
+
+	return nil
+}
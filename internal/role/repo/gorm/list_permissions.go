@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const FileListPermissions = "list_permissions.go"
+
+func (db *DB) ListPermissions(ctx context.Context) ([]*role.Permission, error) {
+	const self = "ListPermissions"
+	span := trace.SpanFromContext(ctx)
+
+	var models []PermissionModel
+	result := db.Order("name").Find(&models)
+	if result.Error != nil {
+		span.AddEvent("db query failed")
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileListPermissions, self, "failed to list permissions", result.Error))
+		return nil, role.ErrInternal
+	}
+	span.AddEvent(fmt.Sprintf("db query returned %d permissions", len(models)))
+
+	permissions := make([]*role.Permission, 0, len(models))
+	for _, m := range models {
+		permissions = append(permissions, &role.Permission{
+			Name:        m.Name,
+			Description: m.Description,
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+	return permissions, nil
+}
@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const FileListRoles = "list_roles.go"
+
+func (db *DB) ListRoles(ctx context.Context) ([]*role.Role, error) {
+	const self = "ListRoles"
+	span := trace.SpanFromContext(ctx)
+
+	var models []RoleModel
+	result := db.Order("name").Find(&models)
+	if result.Error != nil {
+		span.AddEvent("db query failed")
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileListRoles, self, "failed to list roles", result.Error))
+		return nil, role.ErrInternal
+	}
+	span.AddEvent(fmt.Sprintf("db query returned %d roles", len(models)))
+
+	roles, err := db.withPermissions(models)
+	if err != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileListRoles, self, "failed to find role permissions", err))
+		return nil, role.ErrInternal
+	}
+	return roles, nil
+}
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"time"
+
+	"auth/internal/role"
+	userrepo "auth/internal/user/repo/gorm"
+
+	"github.com/google/uuid"
+)
+
+type PermissionModel struct {
+	Name        string    `gorm:"primaryKey"`
+	Description string    `gorm:"not null;default:''"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (*PermissionModel) TableName() string {
+	return "Permission"
+}
+
+type RoleModel struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	Name        string    `gorm:"not null;unique"`
+	Description string    `gorm:"not null;default:''"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (*RoleModel) TableName() string {
+	return "Role"
+}
+
+// RolePermissionModel grants a permission to a role. Deleting either one deletes the grant.
+type RolePermissionModel struct {
+	RoleID         uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	Role           *RoleModel       `gorm:"constraint:OnDelete:CASCADE"`
+	PermissionName string           `gorm:"primaryKey"`
+	Permission     *PermissionModel `gorm:"foreignKey:PermissionName;references:Name;constraint:OnDelete:CASCADE"`
+}
+
+func (*RolePermissionModel) TableName() string {
+	return "RolePermission"
+}
+
+// UserRoleModel assigns a role to a user. Deleting either one deletes the assignment.
+type UserRoleModel struct {
+	UserID    uuid.UUID           `gorm:"type:uuid;primaryKey"`
+	User      *userrepo.UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	RoleID    uuid.UUID           `gorm:"type:uuid;primaryKey;index"`
+	Role      *RoleModel          `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time           `gorm:"not null"`
+}
+
+func (*UserRoleModel) TableName() string {
+	return "UserRole"
+}
+
+// withPermissions maps rows to roles, loading the permissions of all of them at once.
+func (db *DB) withPermissions(models []RoleModel) ([]*role.Role, error) {
+	roles := make([]*role.Role, 0, len(models))
+	if len(models) == 0 {
+		return roles, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(models))
+	for _, m := range models {
+		ids = append(ids, m.ID)
+	}
+
+	var grants []RolePermissionModel
+	result := db.Where("role_id IN ?", ids).Order("permission_name").Find(&grants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	permissions := make(map[uuid.UUID][]string, len(models))
+	for _, g := range grants {
+		permissions[g.RoleID] = append(permissions[g.RoleID], g.PermissionName)
+	}
+
+	for _, m := range models {
+		roles = append(roles, &role.Role{
+			ID:          m.ID,
+			Name:        m.Name,
+			Description: m.Description,
+			Permissions: permissions[m.ID],
+			CreatedAt:   m.CreatedAt,
+			UpdatedAt:   m.UpdatedAt,
+		})
+	}
+	return roles, nil
+}
+
+// grantModels maps the permissions of a role to rows.
+func grantModels(roleID uuid.UUID, permissions []string) []*RolePermissionModel {
+	models := make([]*RolePermissionModel, 0, len(permissions))
+	for _, p := range permissions {
+		models = append(models, &RolePermissionModel{RoleID: roleID, PermissionName: p})
+	}
+	return models
+}
@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const FileSetRolePermissions = "set_role_permissions.go"
+
+func (db *DB) SetRolePermissions(ctx context.Context, id uuid.UUID, permissions []string) error {
+	const self = "SetRolePermissions"
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RoleModel{}).Where("id = ?", id).Update("updated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return role.ErrNotFoundByID
+		}
+
+		if err := tx.Where("role_id = ?", id).Delete(&RolePermissionModel{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		return tx.Create(grantModels(id, permissions)).Error
+	})
+	if err != nil {
+		if err == role.ErrNotFoundByID {
+			return err
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return role.ErrPermissionNotFound
+		}
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileSetRolePermissions, self, "failed to set role permissions", err))
+		return role.ErrInternal
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileSetRolePermissions, self, fmt.Sprintf("set %d permissions of role with id %q", len(permissions), id.String()), nil))
+
+	return nil
+}
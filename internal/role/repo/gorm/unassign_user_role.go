@@ -0,0 +1,29 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/role"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileUnassignUserRole = "unassign_user_role.go"
+
+func (db *DB) UnassignUserRole(ctx context.Context, userID, roleID uuid.UUID) error {
+	const self = "UnassignUserRole"
+
+	result := db.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&UserRoleModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUnassignUserRole, self, "failed to unassign role", result.Error))
+		return role.ErrInternal
+	}
+
+	if result.RowsAffected > 0 {
+		db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUnassignUserRole, self, fmt.Sprintf("unassigned role %q from user %q", roleID.String(), userID.String()), nil))
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInternal                = errors.New("the role service encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrNotFoundByID            = errors.New("could not find any role with provided ID")
+	ErrNameAlreadyInUse        = errors.New("provided role name is already in use")
+	ErrInvalidName             = errors.New("role name must be lowercase letters, digits, dashes and underscores")
+	ErrPermissionNotFound      = errors.New("could not find any permission with provided name")
+	ErrPermissionAlreadyExists = errors.New("permission already exists")
+	ErrInvalidPermission       = errors.New(`permission must be lowercase segments separated by colons, such as "users:delete"`)
+	ErrBuiltinPermission       = errors.New("built-in permissions cannot be deleted")
+)
+
+// Permissions checked by the admin API and by the operator routes, which are created at startup.
+// Admins are granted every permission, while other users are granted the ones of their roles.
+// Beware that roles:write is as powerful as being an admin, since it allows granting any role.
+const (
+	PermissionUsersRead    = "users:read"
+	PermissionUsersWrite   = "users:write"
+	PermissionUsersDelete  = "users:delete"
+	PermissionUsersRestore = "users:restore"
+	PermissionRolesRead    = "roles:read"
+	PermissionRolesWrite   = "roles:write"
+	PermissionClientsRead  = "clients:read"
+	PermissionClientsWrite = "clients:write"
+	PermissionLoginsUnlock = "logins:unlock"
+)
+
+// BuiltinPermissions lists the permissions that the service itself checks.
+var BuiltinPermissions = []string{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionUsersDelete,
+	PermissionUsersRestore,
+	PermissionRolesRead,
+	PermissionRolesWrite,
+	PermissionClientsRead,
+	PermissionClientsWrite,
+	PermissionLoginsUnlock,
+}
+
+var (
+	namePattern       = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+	permissionPattern = regexp.MustCompile(`^[a-z0-9_-]+(:[a-z0-9_-]+)+$`)
+)
+
+// Permission is the right to perform an action, checked by resource servers through the "permissions" claim.
+type Permission struct {
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+// Role is a named set of permissions that users are assigned.
+type Role struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	// Sorted names of the permissions granted by the role
+	Permissions []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Service struct {
+	Repo Repoer
+}
+
+type Repoer interface {
+	InsertPermission(context.Context, *Permission) error
+	// EnsurePermissions creates the permissions that do not exist yet
+	EnsurePermissions(context.Context, []string) error
+	ListPermissions(context.Context) ([]*Permission, error)
+	DeletePermission(context.Context, string) error
+	InsertRole(context.Context, *Role) error
+	FindRoleByID(context.Context, uuid.UUID) (*Role, error)
+	ListRoles(context.Context) ([]*Role, error)
+	SetRolePermissions(ctx context.Context, id uuid.UUID, permissions []string) error
+	DeleteRoleByID(context.Context, uuid.UUID) error
+	// Assigning a role twice is not an error, neither is unassigning a role that the user does not have
+	AssignUserRole(ctx context.Context, userID, roleID uuid.UUID) error
+	UnassignUserRole(ctx context.Context, userID, roleID uuid.UUID) error
+	FindUserRoles(context.Context, uuid.UUID) ([]*Role, error)
+}
+
+// validPermissions checks the names of permissions and returns them sorted, without duplicates.
+func validPermissions(permissions []string) ([]string, error) {
+	for _, p := range permissions {
+		if !permissionPattern.MatchString(p) {
+			return nil, ErrInvalidPermission
+		}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(permissions))), nil
+}
@@ -0,0 +1,35 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SetRolePermissionsRequest struct {
+	ID          uuid.UUID
+	Permissions []string
+}
+
+type SetRolePermissionsResponse struct {
+	Role *Role
+}
+
+// SetRolePermissions replaces the permissions granted by a role.
+// Users only get the new permissions along with their next access token.
+func (s *Service) SetRolePermissions(ctx context.Context, req SetRolePermissionsRequest) (SetRolePermissionsResponse, error) {
+	permissions, err := validPermissions(req.Permissions)
+	if err != nil {
+		return SetRolePermissionsResponse{}, err
+	}
+
+	if err := s.Repo.SetRolePermissions(ctx, req.ID, permissions); err != nil {
+		return SetRolePermissionsResponse{}, err
+	}
+
+	r, err := s.Repo.FindRoleByID(ctx, req.ID)
+	if err != nil {
+		return SetRolePermissionsResponse{}, err
+	}
+	return SetRolePermissionsResponse{r}, nil
+}
@@ -0,0 +1,17 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type UnassignUserRoleRequest struct {
+	UserID uuid.UUID
+	RoleID uuid.UUID
+}
+
+// UnassignUserRole takes a role away from a user. Access tokens already issued keep it until they expire.
+func (s *Service) UnassignUserRole(ctx context.Context, req UnassignUserRoleRequest) error {
+	return s.Repo.UnassignUserRole(ctx, req.UserID, req.RoleID)
+}
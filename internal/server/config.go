@@ -1,10 +1,13 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 
+	"auth/pkg/keys"
+
 	"github.com/peterbourgon/ff/v4"
 	"github.com/peterbourgon/ff/v4/ffhelp"
 	"github.com/peterbourgon/ff/v4/ffyaml"
@@ -32,6 +35,7 @@ type Config struct {
 	Environment Environment
 	Server      *Server
 	Auth        *Auth
+	Mail        *Mail
 	DB          *DB
 }
 
@@ -41,6 +45,9 @@ type Server struct {
 	Timeout        *Timeout
 	Health         *Health
 	MaxHeaderBytes int
+	// Reverse proxies trusted to set X-Forwarded-For, as CIDR ranges or addresses
+	Proxies   []string
+	RateLimit *RateLimit
 }
 
 type Timeout struct {
@@ -58,16 +65,154 @@ type Health struct {
 	Retries  int
 }
 
+type RateLimit struct {
+	Store string
+	Rules []string
+	Purge int
+}
+
 type Auth struct {
-	JWT *JWT
+	JWT          *JWT
+	Refresh      *Refresh
+	Revocation   *Revocation
+	Verification *Verification
+	Reset        *Reset
+	Password     *Password
+	Lockout      *Lockout
+	MFA          *MFA
+	WebAuthn     *WebAuthn
+	Authorize    *Authorize
+	Device       *Device
+	Deletion     *Deletion
+	Admin        *Admin
 }
 
 type JWT struct {
 	Algorithm  string
 	Key        string
+	KeyFile    string
+	KeyDir     string
 	Issuer     string
 	Audience   []string
 	Expiration int
+	// Scopes that users may be granted by the password grant
+	Scopes []string
+	Keys   []keys.Key
+	// Key rotation, in seconds
+	RotationInterval int
+	RotationDelay    int
+	RotationCheck    int
+}
+
+type Refresh struct {
+	Expiration int
+}
+
+type Revocation struct {
+	Purge int
+}
+
+type Verification struct {
+	TTL       int
+	Attempts  int
+	Cooldown  int
+	Required  bool
+	CancelURL string
+}
+
+type Password struct {
+	MinLength int
+	MaxLength int
+	// Required character classes
+	Upper  bool
+	Lower  bool
+	Digit  bool
+	Symbol bool
+	// Minimum strength score, from 0 (too guessable) to 4 (very unguessable)
+	MinScore int
+	// Whether passwords containing the local-part of the email are rejected
+	Email bool
+	// File of breached or common passwords, one per line
+	Denylist string
+}
+
+type Mail struct {
+	Driver string
+	From   string
+	// Default locale of rendered templates
+	Locale string
+	// Directory of templates overriding the embedded ones
+	Templates    string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string
+	// Outbox delivery, intervals in seconds
+	OutboxInterval int
+	OutboxAttempts int
+	OutboxBatch    int
+	OutboxBackoff  int
+	OutboxLease    int
+}
+
+type Reset struct {
+	TTL int
+	URL string
+}
+
+type Lockout struct {
+	AccountThreshold int
+	IPThreshold      int
+	// Lockout durations, in seconds
+	Backoff     int
+	MaxDuration int
+	Window      int
+}
+
+type MFA struct {
+	Issuer string
+	// Time steps of clock drift tolerated on each side
+	Skew int
+	// Seconds that an mfa token is valid for
+	TTL           int
+	Attempts      int
+	RecoveryCodes int
+}
+
+type WebAuthn struct {
+	RPID    string
+	RPName  string
+	Origins []string
+	// Seconds that a ceremony is valid for
+	TTL int
+}
+
+type Authorize struct {
+	// Lifetimes, in seconds
+	CodeTTL    int
+	SessionTTL int
+	// Whether cookies are only sent over HTTPS
+	Secure bool
+}
+
+type Device struct {
+	// Seconds that a device code is valid for
+	TTL int
+	// Seconds that devices must wait between two polls of the token endpoint
+	Interval int
+}
+
+type Deletion struct {
+	// Seconds that a deleted user can be restored for, before being purged
+	GracePeriod int
+	// Seconds between purges of users deleted past the grace period
+	Purge int
+}
+
+type Admin struct {
+	// Email addresses of the users granted the admin claim, once verified
+	Users []string
 }
 
 type DB struct {
@@ -97,11 +242,75 @@ func NewConfig(stdout io.Writer, args []string) (*Config, error) {
 		serverHealthDelay     int
 		serverHealthRetries   int
 		serverMaxHeaderBytes  int
+		serverProxies         []string
+		serverRateLimitStore  string
+		serverRateLimitRules  []string
+		serverRateLimitPurge  int
 		authJWTAlg            string
 		authJWTKey            string
+		authJWTKeyFile        string
+		authJWTKeyDir         string
 		authJWTIssuer         string
 		authJWTAudience       []string
 		authJWTExpiration     int
+		authJWTScopes         []string
+		authJWTRotInterval    int
+		authJWTRotDelay       int
+		authJWTRotCheck       int
+		authRefreshExpiration int
+		authRevocationPurge   int
+		authVerifyTTL         int
+		authVerifyAttempts    int
+		authVerifyCooldown    int
+		authVerifyRequired    bool
+		authVerifyCancelURL   string
+		authResetTTL          int
+		authResetURL          string
+		authPasswdMin         int
+		authPasswdMax         int
+		authPasswdUpper       bool
+		authPasswdLower       bool
+		authPasswdDigit       bool
+		authPasswdSymbol      bool
+		authPasswdScore       int
+		authPasswdEmail       bool
+		authPasswdDenylist    string
+		authLockoutAccount    int
+		authLockoutIP         int
+		authLockoutBackoff    int
+		authLockoutMax        int
+		authLockoutWindow     int
+		authMFAIssuer         string
+		authMFASkew           int
+		authMFATTL            int
+		authMFAAttempts       int
+		authMFARecovery       int
+		authWebAuthnRPID      string
+		authWebAuthnRPName    string
+		authWebAuthnOrigins   []string
+		authWebAuthnTTL       int
+		authAuthorizeCode     int
+		authAuthorizeSession  int
+		authAuthorizeSecure   bool
+		authDeviceTTL         int
+		authDeviceInterval    int
+		authDeletionGrace     int
+		authDeletionPurge     int
+		authAdminUsers        []string
+		mailDriver            string
+		mailFrom              string
+		mailLocale            string
+		mailTemplates         string
+		mailSMTPHost          string
+		mailSMTPPort          string
+		mailSMTPUsername      string
+		mailSMTPPassword      string
+		mailFileDir           string
+		mailOutboxInterval    int
+		mailOutboxAttempts    int
+		mailOutboxBatch       int
+		mailOutboxBackoff     int
+		mailOutboxLease       int
 		dbHost                string
 		dbPort                string
 		dbName                string
@@ -123,11 +332,75 @@ func NewConfig(stdout io.Writer, args []string) (*Config, error) {
 	fs.IntVar(&serverHealthDelay, 0, "server.health.delay", 5, "the initialization time for the program to bootstrap before the health check begins")
 	fs.IntVar(&serverHealthRetries, 0, "server.health.retries", 3, "the number of consecutive failures of the health check for the container to be considered unhealthy")
 	fs.IntVar(&serverMaxHeaderBytes, 0, "server.header", 10240, "number of bytes that will be the maximum permitted size of the headers in an HTTP request")
-	fs.StringVar(&authJWTAlg, 0, "auth.jwt.alg", "HS256", "algorithm that was used for signing the JWT token")
-	fs.StringVar(&authJWTKey, 0, "auth.jwt.key", "", "key that was used for signing the JWT token")
-	fs.StringVar(&authJWTIssuer, 0, "auth.jwt.iss", "", `the "iss" (issuer) claim identifies the principal that issued the jwt`)
+	fs.StringListVar(&serverProxies, 0, "server.proxies", "reverse proxies trusted to set the X-Forwarded-For header, as cidr ranges or addresses")
+	fs.StringEnumVar(&serverRateLimitStore, 0, "server.ratelimit.store", "where rate limit counters are kept (memory is per replica, postgres is shared)", "memory", "postgres")
+	fs.StringListVar(&serverRateLimitRules, 0, "server.ratelimit.rules", `rate limit rules as "[METHOD] PATH LIMIT/PERIOD [ip|sub|client]", the first matching rule applies`)
+	fs.IntVar(&serverRateLimitPurge, 0, "server.ratelimit.purge", 300, "number of seconds between purges of expired rate limit counters")
+	fs.StringVar(&authJWTAlg, 0, "auth.jwt.alg", "HS256", "algorithm that was used for signing the JWT token (HS256, HS384, HS512, RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512 or EdDSA)")
+	fs.StringVar(&authJWTKey, 0, "auth.jwt.key", "", "secret that was used for signing the JWT token when using an HMAC algorithm")
+	fs.StringVar(&authJWTKeyFile, 0, "auth.jwt.keyfile", "", "path to the PEM encoded private key used for signing the JWT token when using an asymmetric algorithm")
+	fs.StringVar(&authJWTKeyDir, 0, "auth.jwt.keydir", "", "directory of PEM encoded private keys (*.pem) used for signing and rotating JWT tokens")
+	fs.StringVar(&authJWTIssuer, 0, "auth.jwt.iss", "", `the "iss" (issuer) claim identifies the principal that issued the jwt, it must be the public url of the auth server for openid connect discovery`)
 	fs.StringListVar(&authJWTAudience, 0, "auth.jwt.aud", `the "aud" (audience) claim identifies the recipients that the jwt is intended for`)
 	fs.IntVar(&authJWTExpiration, 0, "auth.jwt.exp", 1200, `the "exp" (expiration time) claim identifies the expiration time on or after which the jwt must not be accepted for processing`)
+	fs.StringListVar(&authJWTScopes, 0, "auth.jwt.scopes", `scopes that users may request with the password grant, all of them when none is requested`)
+	fs.IntVar(&authJWTRotInterval, 0, "auth.jwt.rotation.interval", 0, "number of seconds that a signing key from auth.jwt.keydir is used before a new one is generated (0 disables rotation)")
+	fs.IntVar(&authJWTRotDelay, 0, "auth.jwt.rotation.delay", 3600, "number of seconds that a new signing key is published before it starts signing tokens")
+	fs.IntVar(&authJWTRotCheck, 0, "auth.jwt.rotation.check", 60, "number of seconds between reloads of auth.jwt.keydir")
+	fs.IntVar(&authRefreshExpiration, 0, "auth.refresh.exp", 2592000, "number of seconds that an issued refresh token remains valid for being exchanged")
+	fs.IntVar(&authRevocationPurge, 0, "auth.revocation.purge", 3600, "number of seconds between purges of expired entries from the token revocation denylist")
+	fs.IntVar(&authVerifyTTL, 0, "auth.verification.ttl", 900, "number of seconds that an email verification code remains valid")
+	fs.IntVar(&authVerifyAttempts, 0, "auth.verification.attempts", 5, "number of failed attempts allowed for a single email verification code")
+	fs.IntVar(&authVerifyCooldown, 0, "auth.verification.cooldown", 60, "number of seconds between two email verification codes sent to the same user")
+	fs.BoolVarDefault(&authVerifyRequired, 0, "auth.verification.required", false, "reject password grant logins until the user email address is verified")
+	fs.StringVar(&authVerifyCancelURL, 0, "auth.verification.cancel", "", "link sent to the current email address when changing it, for canceling the change, the token is appended to it (the bare token is sent when empty)")
+	fs.IntVar(&authResetTTL, 0, "auth.reset.ttl", 3600, "number of seconds that a password reset token remains valid")
+	fs.StringVar(&authResetURL, 0, "auth.reset.url", "", "link sent by email for resetting the password, the token is appended to it (the bare token is sent when empty)")
+	fs.IntVar(&authPasswdMin, 0, "auth.password.min", 8, "minimum number of characters of a password")
+	fs.IntVar(&authPasswdMax, 0, "auth.password.max", 128, "maximum number of characters of a password (0 disables the limit)")
+	fs.BoolVarDefault(&authPasswdUpper, 0, "auth.password.upper", false, "require an uppercase letter in passwords")
+	fs.BoolVarDefault(&authPasswdLower, 0, "auth.password.lower", false, "require a lowercase letter in passwords")
+	fs.BoolVarDefault(&authPasswdDigit, 0, "auth.password.digit", false, "require a digit in passwords")
+	fs.BoolVarDefault(&authPasswdSymbol, 0, "auth.password.symbol", false, "require a symbol in passwords")
+	fs.IntVar(&authPasswdScore, 0, "auth.password.score", 2, "minimum strength score of a password, from 0 (too guessable) to 4 (very unguessable)")
+	fs.BoolVarDefault(&authPasswdEmail, 0, "auth.password.email", true, "reject passwords containing the local-part of the user email address")
+	fs.StringVar(&authPasswdDenylist, 0, "auth.password.denylist", "", "file of breached or common passwords that are rejected, one per line")
+	fs.IntVar(&authLockoutAccount, 0, "auth.lockout.account", 5, "number of failed logins of an account before it is temporarily locked (0 disables account lockout)")
+	fs.IntVar(&authLockoutIP, 0, "auth.lockout.ip", 20, "number of failed logins from a client ip before it is temporarily locked (0 disables ip lockout)")
+	fs.IntVar(&authLockoutBackoff, 0, "auth.lockout.backoff", 30, "number of seconds of the first lockout, doubled after every further failed login")
+	fs.IntVar(&authLockoutMax, 0, "auth.lockout.max", 3600, "maximum number of seconds of a lockout")
+	fs.IntVar(&authLockoutWindow, 0, "auth.lockout.window", 900, "number of seconds without failed logins after which the count starts over")
+	fs.StringVar(&authMFAIssuer, 0, "auth.mfa.issuer", "Auth", "issuer shown by authenticator apps next to the account")
+	fs.IntVar(&authMFASkew, 0, "auth.mfa.skew", 1, "number of 30 seconds time steps of clock drift tolerated before and after the current one")
+	fs.IntVar(&authMFATTL, 0, "auth.mfa.ttl", 300, "number of seconds that an mfa token remains valid for completing a login")
+	fs.IntVar(&authMFAAttempts, 0, "auth.mfa.attempts", 5, "number of one-time passwords allowed for a single mfa token")
+	fs.IntVar(&authMFARecovery, 0, "auth.mfa.recovery", 10, "number of recovery codes generated when enabling two-factor authentication")
+	fs.StringVar(&authWebAuthnRPID, 0, "auth.webauthn.rpid", "localhost", "webauthn relying party id, the domain that passkeys are scoped to")
+	fs.StringVar(&authWebAuthnRPName, 0, "auth.webauthn.rpname", "Auth", "webauthn relying party name shown by authenticators")
+	fs.StringListVar(&authWebAuthnOrigins, 0, "auth.webauthn.origins", "fully qualified origins allowed to register and use passkeys")
+	fs.IntVar(&authWebAuthnTTL, 0, "auth.webauthn.ttl", 300, "number of seconds that a webauthn ceremony remains valid for")
+	fs.IntVar(&authAuthorizeCode, 0, "auth.authorize.code", 60, "number of seconds that an authorization code remains valid for")
+	fs.IntVar(&authAuthorizeSession, 0, "auth.authorize.session", 86400, "number of seconds that a sign in on the authorization login page is remembered for")
+	fs.BoolVarDefault(&authAuthorizeSecure, 0, "auth.authorize.secure", true, "only send the cookies of the authorization login page over https")
+	fs.IntVar(&authDeviceTTL, 0, "auth.device.ttl", 600, "number of seconds that a device code remains valid for")
+	fs.IntVar(&authDeviceInterval, 0, "auth.device.interval", 5, "minimum number of seconds between two polls of the token endpoint by a device")
+	fs.IntVar(&authDeletionGrace, 0, "auth.deletion.grace", 2592000, "number of seconds that a deleted user can be restored by logging in or by an operator, before being purged")
+	fs.IntVar(&authDeletionPurge, 0, "auth.deletion.purge", 3600, "number of seconds between purges of users deleted past the grace period")
+	fs.StringListVar(&authAdminUsers, 0, "auth.admin.users", "email addresses of the users allowed to manage every user through the admin api, once verified")
+	fs.StringEnumVar(&mailDriver, 0, "mail.driver", "transport that delivers outbound email (log, smtp, file or memory)", "log", "smtp", "file", "memory")
+	fs.StringVar(&mailFrom, 0, "mail.from", "Auth <no-reply@localhost>", "sender address of outbound email")
+	fs.StringVar(&mailLocale, 0, "mail.locale", "en", "default locale of email templates")
+	fs.StringVar(&mailTemplates, 0, "mail.templates", "", "directory of <locale>/<name>.<part>.tmpl files overriding the embedded email templates")
+	fs.StringVar(&mailSMTPHost, 0, "mail.smtp.host", "", "smtp server host address")
+	fs.StringVar(&mailSMTPPort, 0, "mail.smtp.port", "587", "smtp server port number")
+	fs.StringVar(&mailSMTPUsername, 0, "mail.smtp.user", "", "smtp user, authentication is skipped when empty")
+	fs.StringVar(&mailSMTPPassword, 0, "mail.smtp.passwd", "", "smtp password")
+	fs.StringVar(&mailFileDir, 0, "mail.file.dir", "", "directory where the file driver writes .eml files")
+	fs.IntVar(&mailOutboxInterval, 0, "mail.outbox.interval", 5, "number of seconds between deliveries of queued email")
+	fs.IntVar(&mailOutboxAttempts, 0, "mail.outbox.attempts", 8, "number of delivery attempts of a queued email before giving up")
+	fs.IntVar(&mailOutboxBatch, 0, "mail.outbox.batch", 50, "maximum number of queued email delivered at once")
+	fs.IntVar(&mailOutboxBackoff, 0, "mail.outbox.backoff", 30, "number of seconds before retrying a failed delivery, doubled after every attempt")
+	fs.IntVar(&mailOutboxLease, 0, "mail.outbox.lease", 300, "number of seconds that claimed email is kept from other replicas while it is delivered")
 	fs.StringVar(&dbHost, 0, "db.host", "", "database host address")
 	fs.StringVar(&dbPort, 0, "db.port", "", "database port number")
 	fs.StringVar(&dbName, 0, "db.name", "", "database name")
@@ -146,7 +419,7 @@ func NewConfig(stdout io.Writer, args []string) (*Config, error) {
 		return &Config{}, err
 	}
 
-	return &Config{
+	cfg := &Config{
 		Environment: NewEnvironment(env),
 		Server: &Server{
 			Host: serverHost,
@@ -165,15 +438,109 @@ func NewConfig(stdout io.Writer, args []string) (*Config, error) {
 				Retries:  serverHealthRetries,
 			},
 			MaxHeaderBytes: serverMaxHeaderBytes,
+			Proxies:        serverProxies,
+			RateLimit: &RateLimit{
+				Store: serverRateLimitStore,
+				Rules: serverRateLimitRules,
+				Purge: serverRateLimitPurge,
+			},
 		},
 		Auth: &Auth{
-			&JWT{
+			JWT: &JWT{
 				Algorithm:  authJWTAlg,
 				Key:        authJWTKey,
+				KeyFile:    authJWTKeyFile,
+				KeyDir:     authJWTKeyDir,
 				Issuer:     authJWTIssuer,
 				Audience:   authJWTAudience,
 				Expiration: authJWTExpiration,
+				Scopes:     authJWTScopes,
+
+				RotationInterval: authJWTRotInterval,
+				RotationDelay:    authJWTRotDelay,
+				RotationCheck:    authJWTRotCheck,
+			},
+			Refresh: &Refresh{
+				Expiration: authRefreshExpiration,
+			},
+			Revocation: &Revocation{
+				Purge: authRevocationPurge,
+			},
+			Verification: &Verification{
+				TTL:       authVerifyTTL,
+				Attempts:  authVerifyAttempts,
+				Cooldown:  authVerifyCooldown,
+				Required:  authVerifyRequired,
+				CancelURL: authVerifyCancelURL,
+			},
+			Reset: &Reset{
+				TTL: authResetTTL,
+				URL: authResetURL,
+			},
+			Password: &Password{
+				MinLength: authPasswdMin,
+				MaxLength: authPasswdMax,
+				Upper:     authPasswdUpper,
+				Lower:     authPasswdLower,
+				Digit:     authPasswdDigit,
+				Symbol:    authPasswdSymbol,
+				MinScore:  authPasswdScore,
+				Email:     authPasswdEmail,
+				Denylist:  authPasswdDenylist,
+			},
+			Lockout: &Lockout{
+				AccountThreshold: authLockoutAccount,
+				IPThreshold:      authLockoutIP,
+				Backoff:          authLockoutBackoff,
+				MaxDuration:      authLockoutMax,
+				Window:           authLockoutWindow,
+			},
+			MFA: &MFA{
+				Issuer:        authMFAIssuer,
+				Skew:          authMFASkew,
+				TTL:           authMFATTL,
+				Attempts:      authMFAAttempts,
+				RecoveryCodes: authMFARecovery,
 			},
+			WebAuthn: &WebAuthn{
+				RPID:    authWebAuthnRPID,
+				RPName:  authWebAuthnRPName,
+				Origins: authWebAuthnOrigins,
+				TTL:     authWebAuthnTTL,
+			},
+			Authorize: &Authorize{
+				CodeTTL:    authAuthorizeCode,
+				SessionTTL: authAuthorizeSession,
+				Secure:     authAuthorizeSecure,
+			},
+			Device: &Device{
+				TTL:      authDeviceTTL,
+				Interval: authDeviceInterval,
+			},
+			Deletion: &Deletion{
+				GracePeriod: authDeletionGrace,
+				Purge:       authDeletionPurge,
+			},
+			Admin: &Admin{
+				Users: authAdminUsers,
+			},
+		},
+		Mail: &Mail{
+			Driver:       mailDriver,
+			From:         mailFrom,
+			Locale:       mailLocale,
+			Templates:    mailTemplates,
+			SMTPHost:     mailSMTPHost,
+			SMTPPort:     mailSMTPPort,
+			SMTPUsername: mailSMTPUsername,
+			SMTPPassword: mailSMTPPassword,
+			FileDir:      mailFileDir,
+
+			OutboxInterval: mailOutboxInterval,
+			OutboxAttempts: mailOutboxAttempts,
+			OutboxBatch:    mailOutboxBatch,
+			OutboxBackoff:  mailOutboxBackoff,
+			OutboxLease:    mailOutboxLease,
 		},
 		DB: &DB{
 			Host:     dbHost,
@@ -183,5 +550,56 @@ func NewConfig(stdout io.Writer, args []string) (*Config, error) {
 			Password: dbPasswd,
 			SSL:      dbSSL,
 		},
-	}, nil
+	}
+
+	if err := cfg.Auth.JWT.loadKeys(); err != nil {
+		fmt.Fprintf(stdout, "ERROR\n%v\n", err)
+		return &Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadKeys loads the private keys used for signing tokens with an asymmetric
+// algorithm, either from a single file or from a key directory.
+// HMAC algorithms only need the shared secret.
+func (j *JWT) loadKeys() error {
+	if keys.IsSymmetric(j.Algorithm) {
+		if j.Key == "" {
+			return fmt.Errorf("auth.jwt.key is required for algorithm %s", j.Algorithm)
+		}
+		return nil
+	}
+
+	if j.RotationInterval > 0 && j.KeyDir == "" {
+		return fmt.Errorf("auth.jwt.keydir is required when auth.jwt.rotation.interval is set")
+	}
+
+	switch {
+	case j.KeyFile != "":
+		key, err := keys.LoadFile(j.KeyFile)
+		if err != nil {
+			return err
+		}
+		j.Keys = []keys.Key{key}
+	case j.KeyDir != "":
+		loaded, err := keys.LoadDir(j.KeyDir)
+		if err != nil {
+			// An empty directory is bootstrapped by the rotation policy
+			if errors.Is(err, keys.ErrEmptyKeyDir) && j.RotationInterval > 0 {
+				return nil
+			}
+			return err
+		}
+		j.Keys = loaded
+	default:
+		return fmt.Errorf("auth.jwt.keyfile or auth.jwt.keydir is required for algorithm %s", j.Algorithm)
+	}
+
+	for _, key := range j.Keys {
+		if err := keys.Check(j.Algorithm, key.Private); err != nil {
+			return fmt.Errorf("%w (key %q)", err, key.ID)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"auth/pkg/otel"
+)
+
+const FileJobs = "jobs.go"
+
+// schedule runs job every interval until ctx is done.
+// A failed run is logged and retried on the next tick.
+func schedule(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, job func(context.Context) error) {
+	const self = "schedule"
+
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				logger.ErrorContext(ctx, otel.FormatLog(Path, FileJobs, self, fmt.Sprintf("job %q failed", name), err))
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package server
+
+import "auth/internal/auth"
+
+// Option customizes the server started by Exec, so that it can be extended without being forked.
+type Option func(*options)
+
+type options struct {
+	claims auth.ClaimsEnricher
+}
+
+// WithClaimsEnricher adds the custom claims of the enricher, such as tenant IDs, roles
+// or feature flags, to every access token issued by the server.
+func WithClaimsEnricher(enricher auth.ClaimsEnricher) Option {
+	return func(o *options) {
+		o.claims = enricher
+	}
+}
@@ -13,15 +13,28 @@ import (
 	"syscall"
 	"time"
 
+	adminserver "auth/internal/admin/httphandler"
 	"auth/internal/auth"
+	"auth/internal/auth/guard"
 	authserver "auth/internal/auth/httphandler"
+	authrepo "auth/internal/auth/repo/gorm"
+	clientserver "auth/internal/client/httphandler"
+	clientrepo "auth/internal/client/repo/gorm"
+	"auth/internal/mail"
+	mailrepo "auth/internal/mail/repo/gorm"
+	"auth/internal/ratelimit"
+	ratelimitrepo "auth/internal/ratelimit/repo/gorm"
+	"auth/internal/role"
+	rolerepo "auth/internal/role/repo/gorm"
+	"auth/internal/user"
 	userserver "auth/internal/user/httphandler"
 	userrepo "auth/internal/user/repo/gorm"
+	"auth/pkg/password"
+	"auth/pkg/realip"
 
 	servercomposer "github.com/jkitajima/composer"
 
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/jwtauth/v5"
 	"github.com/go-playground/validator/v10"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel"
@@ -43,14 +56,28 @@ func Exec(
 	_ io.Writer,
 	_ func(string) string,
 	_ func() (string, error),
+	opts ...Option,
 ) error {
 	cfg, err := NewConfig(stdout, args)
 	if err != nil {
 		return err
 	}
 
+	var options options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	// Setting up dependencies
-	jwtAuth := jwtauth.New(cfg.Auth.JWT.Algorithm, []byte(cfg.Auth.JWT.Key), nil)
+	keyring, err := auth.NewKeyring((*auth.JWTConfig)(cfg.Auth.JWT))
+	if err != nil {
+		return err
+	}
+
+	passwordPolicy, err := password.NewPolicy((*password.PolicyConfig)(cfg.Auth.Password))
+	if err != nil {
+		return err
+	}
 
 	db, err := initDB(cfg.Environment, cfg.DB)
 	if err != nil {
@@ -62,15 +89,44 @@ func Exec(
 	tracer := otel.Tracer(Service)
 	meter := otel.Meter(Service)
 
+	// Permissions checked by the admin API must exist before roles can grant them
+	roles := &role.Service{Repo: rolerepo.NewRepo(db, logger)}
+	if err := roles.EnsureBuiltinPermissions(ctx); err != nil {
+		return err
+	}
+
+	// Client addresses are resolved before anything keys on them
+	resolver, err := realip.New(cfg.Server.Proxies)
+	if err != nil {
+		return err
+	}
+
+	var rateLimitRepo ratelimit.Repoer
+	switch cfg.Server.RateLimit.Store {
+	case ratelimit.StoreMemory:
+		rateLimitRepo = ratelimit.NewMemoryRepo()
+	case ratelimit.StorePostgres:
+		rateLimitRepo = ratelimitrepo.NewRepo(db, logger)
+	default:
+		return fmt.Errorf("%w (%q)", ratelimit.ErrUnsupportedStore, cfg.Server.RateLimit.Store)
+	}
+	limiter, err := ratelimit.NewLimiter((*ratelimit.Config)(cfg.Server.RateLimit), rateLimitRepo, guard.Subject(keyring), logger, meter)
+	if err != nil {
+		return err
+	}
+
 	// Mounting routers
 	composer := servercomposer.NewComposer(
 		middleware.Recoverer,
+		resolver.Middleware,
 		middleware.AllowContentType(
 			"application/json",
 			"application/x-www-form-urlencoded",
+			"application/merge-patch+json",
 		),
 		middleware.CleanPath,
 		middleware.RedirectSlashes,
+		limiter.Middleware,
 	)
 
 	// API Docs
@@ -81,17 +137,82 @@ func Exec(
 
 	healthCheck := SetupHealthCheck(cfg, logger)
 
-	authServer, err := authserver.NewServer(jwtAuth, (*auth.JWTConfig)(cfg.Auth.JWT), db, inputValidator, logger, tracer, meter)
+	// Email is queued in the outbox and delivered in the background
+	mailConfig := (*mail.Config)(cfg.Mail)
+	transport, err := mail.NewTransport(mailConfig, logger)
+	if err != nil {
+		return err
+	}
+	templates, err := mail.NewTemplates(mailConfig)
+	if err != nil {
+		return err
+	}
+	outbox := mail.NewOutbox(mailConfig, transport, mailrepo.NewRepo(db, logger), logger)
+
+	// Signing keys are reloaded and rotated in the background
+	go keyring.Run(ctx, logger)
+
+	// Housekeeping jobs
+	jobs := &auth.Service{Lockout: (*auth.LockoutConfig)(cfg.Auth.Lockout), Repo: authrepo.NewRepo(db, logger)}
+	go schedule(ctx, logger, "purge_revoked_tokens", time.Duration(cfg.Auth.Revocation.Purge)*time.Second, func(ctx context.Context) error {
+		_, err := jobs.PurgeRevokedTokens(ctx)
+		return err
+	})
+	go schedule(ctx, logger, "purge_login_throttles", time.Duration(cfg.Auth.Lockout.Window)*time.Second, func(ctx context.Context) error {
+		_, err := jobs.PurgeLoginThrottles(ctx)
+		return err
+	})
+	users := &user.Service{Deletion: (*user.DeletionConfig)(cfg.Auth.Deletion), Repo: userrepo.NewRepo(db, logger)}
+	go schedule(ctx, logger, "purge_deleted_users", time.Duration(cfg.Auth.Deletion.Purge)*time.Second, func(ctx context.Context) error {
+		_, err := users.PurgeDeleted(ctx)
+		return err
+	})
+	go schedule(ctx, logger, "purge_rate_limits", time.Duration(cfg.Server.RateLimit.Purge)*time.Second, func(ctx context.Context) error {
+		_, err := limiter.Purge(ctx)
+		return err
+	})
+	go schedule(ctx, logger, "deliver_mail", time.Duration(cfg.Mail.OutboxInterval)*time.Second, func(ctx context.Context) error {
+		_, err := outbox.Deliver(ctx)
+		return err
+	})
+
+	authConfig := &auth.Config{
+		JWT:            (*auth.JWTConfig)(cfg.Auth.JWT),
+		Refresh:        (*auth.RefreshConfig)(cfg.Auth.Refresh),
+		Verification:   (*auth.VerificationConfig)(cfg.Auth.Verification),
+		Reset:          (*auth.ResetConfig)(cfg.Auth.Reset),
+		Lockout:        (*auth.LockoutConfig)(cfg.Auth.Lockout),
+		MFA:            (*auth.MFAConfig)(cfg.Auth.MFA),
+		WebAuthn:       (*auth.WebAuthnConfig)(cfg.Auth.WebAuthn),
+		Authorize:      (*auth.AuthorizeConfig)(cfg.Auth.Authorize),
+		Device:         (*auth.DeviceConfig)(cfg.Auth.Device),
+		Deletion:       (*user.DeletionConfig)(cfg.Auth.Deletion),
+		Admin:          (*auth.AdminConfig)(cfg.Auth.Admin),
+		PasswordPolicy: passwordPolicy,
+		Claims:         options.claims,
+	}
+
+	authServer, err := authserver.NewServer(keyring, authConfig, outbox, templates, db, inputValidator, logger, tracer, meter)
+	if err != nil {
+		return err
+	}
+
+	userServer, err := userserver.NewServer(keyring, authConfig, outbox, templates, db, inputValidator, logger, tracer, meter)
+	if err != nil {
+		return err
+	}
+
+	clientServer, err := clientserver.NewServer(keyring, db, inputValidator, logger, tracer, meter)
 	if err != nil {
 		return err
 	}
 
-	userServer, err := userserver.NewServer(jwtAuth, db, inputValidator, logger, tracer, meter)
+	adminServer, err := adminserver.NewServer(keyring, (*auth.ResetConfig)(cfg.Auth.Reset), (*user.DeletionConfig)(cfg.Auth.Deletion), outbox, templates, db, inputValidator, logger, tracer, meter)
 	if err != nil {
 		return err
 	}
 
-	if err := composer.Compose(healthCheck, authServer, userServer); err != nil {
+	if err := composer.Compose(healthCheck, authServer, userServer, clientServer, adminServer); err != nil {
 		return err
 	}
 
@@ -159,7 +280,7 @@ func initDB(env Environment, config *DB) (*gorm.DB, error) {
 	db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`)
 
 	// Migrate the schema
-	db.AutoMigrate(&userrepo.UserModel{})
+	db.AutoMigrate(&userrepo.UserModel{}, &authrepo.RefreshTokenModel{}, &authrepo.RevokedTokenModel{}, &authrepo.PasswordResetTokenModel{}, &authrepo.LoginThrottleModel{}, &authrepo.TOTPModel{}, &authrepo.RecoveryCodeModel{}, &authrepo.MFAChallengeModel{}, &authrepo.WebAuthnCredentialModel{}, &authrepo.WebAuthnCeremonyModel{}, &clientrepo.ClientModel{}, &authrepo.SessionModel{}, &authrepo.AuthorizationCodeModel{}, &authrepo.DeviceCodeModel{}, &authrepo.EmailChangeModel{}, &mailrepo.OutboxMessageModel{}, &ratelimitrepo.CounterModel{}, &rolerepo.PermissionModel{}, &rolerepo.RoleModel{}, &rolerepo.RolePermissionModel{}, &rolerepo.UserRoleModel{})
 
 	// Seeding data for tests
 	if env == EnvironmentTest {
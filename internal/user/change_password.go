@@ -0,0 +1,59 @@
+package user
+
+import (
+	"context"
+
+	"auth/pkg/password"
+
+	"github.com/alexedwards/argon2id"
+	"github.com/google/uuid"
+)
+
+type ChangePasswordRequest struct {
+	ID              uuid.UUID
+	CurrentPassword string
+	NewPassword     string
+}
+
+type ChangePasswordResponse struct {
+	User *User
+}
+
+// ChangePassword replaces the password of a user who knows the current one.
+// The token version is bumped along with it, so access tokens issued before are rejected.
+func (s *Service) ChangePassword(ctx context.Context, req ChangePasswordRequest) (ChangePasswordResponse, error) {
+	findResponse, err := s.FindByID(ctx, FindByIDRequest{req.ID})
+	if err != nil {
+		return ChangePasswordResponse{nil}, err
+	}
+	user := findResponse.User
+
+	// Check if incoming password matches stored password
+	checkPasswordRequest := password.CheckPasswordRequest{
+		Input:    req.CurrentPassword,
+		Password: user.Password,
+	}
+	checkPasswordResponse, err := password.CheckPassword(ctx, checkPasswordRequest)
+	if err != nil {
+		return ChangePasswordResponse{nil}, err
+	}
+
+	if !checkPasswordResponse.Valid {
+		return ChangePasswordResponse{nil}, ErrInvalidCredentials
+	}
+
+	if err := s.PasswordPolicy.Validate(req.NewPassword, user.Email); err != nil {
+		return ChangePasswordResponse{nil}, err
+	}
+
+	hashedPasswd, err := argon2id.CreateHash(req.NewPassword, argon2id.DefaultParams)
+	if err != nil {
+		return ChangePasswordResponse{nil}, err
+	}
+	user.Password = hashedPasswd
+
+	if err := s.Repo.UpdatePassword(ctx, user); err != nil {
+		return ChangePasswordResponse{nil}, err
+	}
+	return ChangePasswordResponse{user}, nil
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeleteByIDRequest struct {
+	ID uuid.UUID
+	// Permanent deletions skip the grace period, and also apply to users already pending deletion
+	Permanent bool
+}
+
+// DeleteByID deletes a user on behalf of an admin, without its password.
+// Users are marked as pending deletion unless the deletion is permanent.
+func (s *Service) DeleteByID(ctx context.Context, req DeleteByIDRequest) error {
+	if req.Permanent {
+		if _, err := s.Repo.FindAnyByID(ctx, req.ID); err != nil {
+			return err
+		}
+		return s.Repo.HardDeleteByID(ctx, req.ID)
+	}
+	return s.Repo.SoftDeleteByID(ctx, req.ID, time.Now())
+}
@@ -0,0 +1,19 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DisableByIDRequest struct {
+	ID uuid.UUID
+}
+
+// DisableByID prevents the user from signing in until it is enabled again.
+// Its access tokens and sessions are invalidated along with its token version,
+// while revoking its refresh tokens is up to the caller.
+func (s *Service) DisableByID(ctx context.Context, req DisableByIDRequest) error {
+	return s.Repo.DisableByID(ctx, req.ID, time.Now())
+}
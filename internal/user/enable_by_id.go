@@ -0,0 +1,16 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type EnableByIDRequest struct {
+	ID uuid.UUID
+}
+
+// EnableByID allows a disabled user to sign in again. Tokens invalidated by disabling it stay invalid.
+func (s *Service) EnableByID(ctx context.Context, req EnableByIDRequest) error {
+	return s.Repo.EnableByID(ctx, req.ID)
+}
@@ -0,0 +1,14 @@
+package user
+
+import (
+	"context"
+)
+
+// FindAnyByID finds a user even when it is pending deletion.
+func (s *Service) FindAnyByID(ctx context.Context, req FindByIDRequest) (FindByIDResponse, error) {
+	user, err := s.Repo.FindAnyByID(ctx, req.ID)
+	if err != nil {
+		return FindByIDResponse{nil}, err
+	}
+	return FindByIDResponse{user}, nil
+}
@@ -0,0 +1,55 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationCancelEmailChange = "cancel_email_change"
+	FileCancelEmailChange      = OperationCancelEmailChange + ".go"
+)
+
+// handleUserCancelEmailChange is the one-click link sent to the current address of an email change.
+// It is public, since whoever started the change may not be the owner of the account.
+func (s *UserServer) handleUserCancelEmailChange() http.HandlerFunc {
+	const self = "handleUserCancelEmailChange"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		err := s.authService.CancelEmailChange(ctx, auth.CancelEmailChangeRequest{Token: r.URL.Query().Get("token")})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCancelEmailChange))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrEmailChangeNotFound:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "The link is invalid or expired, or the email change was already confirmed.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.RespondMetaMessage(w, r, http.StatusOK, "The email change was canceled."); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationCancelEmailChange))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileCancelEmailChange, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationCancelEmailChange)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,177 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"auth/internal/auth"
+	"auth/internal/auth/guard"
+	"auth/internal/user"
+	"auth/pkg/otel"
+	"auth/pkg/password"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationChangePassword = "change_password"
+	FileChangePassword      = OperationChangePassword + ".go"
+)
+
+func (s *UserServer) handleUserChangePassword() http.HandlerFunc {
+	const self = "handleUserChangePassword"
+
+	type request struct {
+		CurrentPassword string `json:"current_password" validate:"required"`
+		NewPassword     string `json:"new_password" validate:"required"`
+	}
+
+	type response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+
+	contract := map[string]responder.Field{
+		"CurrentPassword": {
+			Name:       "current_password",
+			Validation: "Field value cannot be an empty string.",
+		},
+		"NewPassword": {
+			Name:       "new_password",
+			Validation: "Field value cannot be an empty string.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		_, claims, err := guard.FromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		sub, err := uuid.Parse(claims["sub"].(string))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid UUID.")
+			return
+		}
+
+		id := r.PathValue("userID")
+		uuid, err := uuid.Parse(id)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		if sub != uuid {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusForbidden, "You are not allowed to change the password of another user.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		changePasswordResponse, err := s.service.ChangePassword(ctx, user.ChangePasswordRequest{
+			ID:              uuid,
+			CurrentPassword: req.CurrentPassword,
+			NewPassword:     req.NewPassword,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			if errs, ok := password.ErrorObjects(err, "new_password"); ok {
+				responder.RespondClientErrors(w, r, errs...)
+				return
+			}
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			case user.ErrInvalidCredentials:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Provided credentials was invalid.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.passwordsChangedCounter.Add(ctx, 1)
+
+		// The renewed session keeps the authentication methods of the current one
+		var amr []string
+		if methods, ok := claims[auth.ClaimAMR].([]any); ok {
+			for _, method := range methods {
+				if method, ok := method.(string); ok {
+					amr = append(amr, method)
+				}
+			}
+		}
+
+		// Nor is it widened beyond the scopes and client of the current one
+		scope, _ := claims[auth.ClaimScope].(string)
+		clientID, _ := claims[auth.ClaimClientID].(string)
+
+		// Every other session is signed out, while the caller gets a new token pair
+		// since the token it used is now outdated as well
+		renewSessionResponse, err := s.authService.RenewSession(ctx, auth.RenewSessionRequest{
+			User:     changePasswordResponse.User,
+			AMR:      amr,
+			Scope:    strings.Fields(scope),
+			ClientID: clientID,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileChangePassword, self, "failed to renew session", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+
+		resp := response{
+			AccessToken:  string(renewSessionResponse.AccessToken),
+			RefreshToken: renewSessionResponse.RefreshToken,
+			TokenType:    renewSessionResponse.TokenType,
+			ExpiresIn:    renewSessionResponse.ExpiresIn,
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, resp); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationChangePassword))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileChangePassword, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationChangePassword)
+	return otelhandler.ServeHTTP
+}
@@ -0,0 +1,108 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/auth/guard"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationConfirmEmailChange = "confirm_email_change"
+	FileConfirmEmailChange      = OperationConfirmEmailChange + ".go"
+)
+
+// handleUserConfirmEmailChange changes the email address of the user of the bearer token
+// with the code sent to the new address.
+func (s *UserServer) handleUserConfirmEmailChange() http.HandlerFunc {
+	const self = "handleUserConfirmEmailChange"
+
+	type request struct {
+		Code string `json:"code" validate:"required,len=6,numeric"`
+	}
+
+	contract := map[string]responder.Field{
+		"Code": {
+			Name:       "code",
+			Validation: "Field is required and must be a 6 digits code.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		_, claims, err := guard.FromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmEmailChange))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		sub, err := uuid.Parse(claims["sub"].(string))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmEmailChange))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid UUID.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmEmailChange))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmEmailChange))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		confirmResponse, err := s.authService.ConfirmEmailChange(ctx, auth.ConfirmEmailChangeRequest{UserID: sub, Code: req.Code})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmEmailChange))
+			span.RecordError(err)
+			switch err {
+			case auth.ErrEmailChangeNotFound:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "There is no pending email change, it may have expired or been canceled.")
+			case auth.ErrInvalidVerificationCode:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid verification code.")
+			case auth.ErrTooManyVerificationAttempts:
+				responder.RespondMetaMessage(w, r, http.StatusTooManyRequests, "Too many failed attempts, please start the email change again.")
+			case user.ErrEmailAlreadyInUse:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "There is already an user with provided email.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.emailsChangedCounter.Add(ctx, 1)
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: s.newUserResponse(confirmResponse.User)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationConfirmEmailChange))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileConfirmEmailChange, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationConfirmEmailChange)
+	return otelhandler.ServeHTTP
+}
@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"net/http"
 
+	"auth/internal/auth"
+	"auth/internal/auth/guard"
 	"auth/internal/user"
 	"auth/pkg/otel"
 
 	"github.com/jkitajima/responder"
 
-	"github.com/go-chi/jwtauth/v5"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel/codes"
@@ -17,15 +18,18 @@ import (
 )
 
 const (
-	OperationHardDeleteByID = "hard_delete_by_id"
-	FileHardDeleteByID      = OperationHardDeleteByID + ".go"
+	OperationDeleteByID = "delete_by_id"
+	FileDeleteByID      = OperationDeleteByID + ".go"
 )
 
-func (s *UserServer) handleUserHardDeleteByID() http.HandlerFunc {
-	const self = "handleUserHardDeleteByID"
+// handleUserDeleteByID deletes the user after a grace period, during which it can be restored by logging in.
+// Users can also ask to be deleted permanently right away.
+func (s *UserServer) handleUserDeleteByID() http.HandlerFunc {
+	const self = "handleUserDeleteByID"
 
 	type request struct {
-		Password string `json:"password" validate:"required"`
+		Password  string `json:"password" validate:"required"`
+		Permanent bool   `json:"permanent"`
 	}
 
 	contract := map[string]responder.Field{
@@ -39,9 +43,9 @@ func (s *UserServer) handleUserHardDeleteByID() http.HandlerFunc {
 		ctx := r.Context()
 		span := trace.SpanFromContext(ctx)
 
-		_, claims, err := jwtauth.FromContext(ctx)
+		_, claims, err := guard.FromContext(ctx)
 		if err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
 			return
@@ -49,7 +53,7 @@ func (s *UserServer) handleUserHardDeleteByID() http.HandlerFunc {
 
 		sub, err := uuid.Parse(claims["sub"].(string))
 		if err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid UUID.")
 			return
@@ -58,14 +62,14 @@ func (s *UserServer) handleUserHardDeleteByID() http.HandlerFunc {
 		id := r.PathValue("userID")
 		uuid, err := uuid.Parse(id)
 		if err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
 			return
 		}
 
 		if sub != uuid {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			responder.RespondMetaMessage(w, r, http.StatusForbidden, "You are not allowed to request deletion of another user.")
 			return
@@ -73,22 +77,26 @@ func (s *UserServer) handleUserHardDeleteByID() http.HandlerFunc {
 
 		req, err := responder.Decode[request](r)
 		if err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
 			return
 		}
 
 		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			responder.RespondClientErrors(w, r, errors...)
 			return
 		}
 
-		err = s.service.HardDeleteByID(ctx, user.HardDeleteByIDRequest{ID: uuid, Password: req.Password})
+		if req.Permanent {
+			err = s.service.HardDeleteByID(ctx, user.HardDeleteByIDRequest{ID: uuid, Password: req.Password})
+		} else {
+			err = s.service.SoftDeleteByID(ctx, user.SoftDeleteByIDRequest{ID: uuid, Password: req.Password})
+		}
 		if err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
 			switch err {
 			case user.ErrNotFoundByID:
@@ -101,15 +109,28 @@ func (s *UserServer) handleUserHardDeleteByID() http.HandlerFunc {
 			return
 		}
 
+		// Refresh tokens would otherwise outlive the deletion, and be usable again once restored
+		if !req.Permanent {
+			if err := s.authService.SignOutEverywhere(ctx, auth.SignOutEverywhereRequest{UserID: uuid}); err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
+				span.RecordError(err)
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteByID, self, "failed to revoke refresh tokens", err))
+				responder.RespondInternalError(w, r)
+				return
+			}
+		}
+
+		s.usersDeletedCounter.Add(ctx, 1)
+
 		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
-			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationHardDeleteByID))
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationDeleteByID))
 			span.RecordError(err)
-			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileHardDeleteByID, self, "failed to encode response", err))
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileDeleteByID, self, "failed to encode response", err))
 			responder.RespondInternalError(w, r)
 			return
 		}
 	}
 
-	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationHardDeleteByID)
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationDeleteByID)
 	return otelhandler.ServeHTTP
 }
@@ -0,0 +1,90 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth/guard"
+	"auth/internal/role"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationFindByID = "find_by_id"
+	FileFindByID      = OperationFindByID + ".go"
+)
+
+// handleUserFindByID returns the user of the bearer token on /users/me.
+// Otherwise users can only find themselves, while operators granted users:read can find any user.
+func (s *UserServer) handleUserFindByID() http.HandlerFunc {
+	const self = "handleUserFindByID"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		// The ID is taken from the bearer token on /users/me
+		var id uuid.UUID
+		if param := r.PathValue("userID"); param != "" {
+			parsed, err := uuid.Parse(param)
+			if err != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+				span.RecordError(err)
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+				return
+			}
+			id = parsed
+		}
+
+		_, claims, _ := guard.FromContext(ctx)
+		sub, err := uuid.Parse(claims["sub"].(string))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid UUID.")
+			return
+		}
+
+		if id == uuid.Nil {
+			id = sub
+		}
+
+		if id != sub && !guard.HasPermission(claims, role.PermissionUsersRead) {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+			responder.RespondMetaMessage(w, r, http.StatusForbidden, "You are not allowed to find another user.")
+			return
+		}
+
+		findResponse, err := s.service.FindByID(ctx, user.FindByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: s.newUserResponse(findResponse.User)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationFindByID))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileFindByID, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationFindByID)
+	return otelhandler.ServeHTTP
+}
@@ -4,6 +4,11 @@ import (
 	"log/slog"
 	"net/http"
 
+	"auth/internal/auth"
+	authrepo "auth/internal/auth/repo/gorm"
+	clientrepo "auth/internal/client/repo/gorm"
+	"auth/internal/mail"
+	rolerepo "auth/internal/role/repo/gorm"
 	"auth/internal/user"
 	repo "auth/internal/user/repo/gorm"
 
@@ -14,24 +19,28 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 	"gorm.io/gorm"
 )
 
 const Path = "auth/internal/user/httphandler"
 
 type UserServer struct {
-	entity              string
-	mux                 *chi.Mux
-	prefix              string
-	service             *user.Service
-	auth                *jwtauth.JWTAuth
-	db                  user.Repoer
-	inputValidator      *validator.Validate
-	logger              *slog.Logger
-	tracer              trace.Tracer
-	meter               metric.Meter
-	usersDeletedCounter metric.Int64Counter
+	entity                  string
+	mux                     *chi.Mux
+	prefix                  string
+	service                 *user.Service
+	authService             *auth.Service
+	keyring                 *auth.Keyring
+	db                      user.Repoer
+	authRepo                auth.Repoer
+	inputValidator          *validator.Validate
+	logger                  *slog.Logger
+	tracer                  trace.Tracer
+	meter                   metric.Meter
+	usersDeletedCounter     metric.Int64Counter
+	usersRestoredCounter    metric.Int64Counter
+	passwordsChangedCounter metric.Int64Counter
+	emailsChangedCounter    metric.Int64Counter
 }
 
 func (s *UserServer) Prefix() string {
@@ -47,7 +56,10 @@ func (s *UserServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func NewServer(
-	auth *jwtauth.JWTAuth,
+	keyring *auth.Keyring,
+	config *auth.Config,
+	mailer mail.Mailer,
+	templates *mail.Templates,
 	db *gorm.DB,
 	validtr *validator.Validate,
 	logger *slog.Logger,
@@ -58,14 +70,30 @@ func NewServer(
 		entity:         "users",
 		prefix:         "/users",
 		mux:            chi.NewRouter(),
-		auth:           auth,
+		keyring:        keyring,
 		db:             repo.NewRepo(db, logger),
+		authRepo:       authrepo.NewRepo(db, logger),
 		inputValidator: validtr,
 		logger:         logger,
 		tracer:         tracer,
 		meter:          meter,
 	}
-	s.service = &user.Service{Repo: s.db}
+	s.service = &user.Service{Repo: s.db, PasswordPolicy: config.PasswordPolicy, Deletion: config.Deletion}
+	s.authService = &auth.Service{
+		JWTConfig:     config.JWT,
+		RefreshConfig: config.Refresh,
+		Verification:  config.Verification,
+		Deletion:      config.Deletion,
+		Admin:         config.Admin,
+		Keyring:       keyring,
+		Mailer:        mailer,
+		Templates:     templates,
+		Claims:        config.Claims,
+		UserRepo:      s.db,
+		ClientRepo:    clientrepo.NewRepo(db, logger),
+		RoleRepo:      rolerepo.NewRepo(db, logger),
+		Repo:          s.authRepo,
+	}
 
 	if err := s.instrument(); err != nil {
 		return s, err
@@ -84,5 +112,29 @@ func (s *UserServer) instrument() error {
 	}
 	s.usersDeletedCounter = usersDeletedCounter
 
+	usersRestoredCounter, err := s.meter.Int64Counter("users_restored",
+		metric.WithDescription("How many deleted users has been restored by an operator."),
+	)
+	if err != nil {
+		return err
+	}
+	s.usersRestoredCounter = usersRestoredCounter
+
+	passwordsChangedCounter, err := s.meter.Int64Counter("passwords_changed",
+		metric.WithDescription("How many users has changed their password."),
+	)
+	if err != nil {
+		return err
+	}
+	s.passwordsChangedCounter = passwordsChangedCounter
+
+	emailsChangedCounter, err := s.meter.Int64Counter("emails_changed",
+		metric.WithDescription("How many users has changed their email address."),
+	)
+	if err != nil {
+		return err
+	}
+	s.emailsChangedCounter = emailsChangedCounter
+
 	return nil
 }
@@ -0,0 +1,65 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationRestoreByID = "restore_by_id"
+	FileRestoreByID      = OperationRestoreByID + ".go"
+)
+
+// handleUserRestoreByID lets operators cancel the deletion of a user within the grace period.
+func (s *UserServer) handleUserRestoreByID() http.HandlerFunc {
+	const self = "handleUserRestoreByID"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		id, err := uuid.Parse(r.PathValue("userID"))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRestoreByID))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "User ID must be a valid UUID.")
+			return
+		}
+
+		err = s.service.RestoreByID(ctx, user.RestoreByIDRequest{ID: id})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRestoreByID))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundDeleted:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user pending deletion with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		s.usersRestoredCounter.Add(ctx, 1)
+
+		if err := responder.Respond(w, r, http.StatusNoContent, nil); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationRestoreByID))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileRestoreByID, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationRestoreByID)
+	return otelhandler.ServeHTTP
+}
@@ -3,24 +3,36 @@ package httphandler
 import (
 	"net/http"
 
+	"auth/internal/auth/guard"
+	"auth/internal/role"
 	"auth/pkg/otel"
 
-	"github.com/jkitajima/responder"
-
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 )
 
 func (s *UserServer) addRoutes() {
 	// Private routes
 	s.mux.Group(func(r chi.Router) {
-		r.Use(jwtauth.Verifier(s.auth))
-		r.Use(responder.RespondAuth(s.auth))
+		r.Use(guard.Verifier(s.keyring, s.authRepo, s.db))
+		r.Use(guard.Authenticator)
+
+		otel.Route(r, http.MethodGet, "/me", s.handleUserFindByID())
+		otel.Route(r, http.MethodPatch, "/me", s.handleUserUpdateProfile())
+		otel.Route(r, http.MethodPost, "/me/email", s.handleUserStartEmailChange())
+		otel.Route(r, http.MethodPost, "/me/email/confirm", s.handleUserConfirmEmailChange())
+		otel.Route(r, http.MethodPost, "/{userID}/delete", s.handleUserDeleteByID())
+		otel.Route(r, http.MethodPost, "/{userID}/password", s.handleUserChangePassword())
+
+		// Users can find themselves, operators need the users:read permission to find anyone else
+		otel.Route(r, http.MethodGet, "/{userID}", s.handleUserFindByID())
 
-		otel.Route(r, http.MethodPost, "/{userID}/delete", s.handleUserHardDeleteByID())
+		// Operator routes
+		usersRestore := r.With(guard.RequirePermission(role.PermissionUsersRestore))
+		otel.Route(usersRestore, http.MethodPost, "/{userID}/restore", s.handleUserRestoreByID())
 	})
 
 	// Public routes
-	// s.mux.Group(func(r chi.Router) {
-	// })
+	s.mux.Group(func(r chi.Router) {
+		otel.Route(r, http.MethodGet, "/email/cancel", s.handleUserCancelEmailChange())
+	})
 }
@@ -0,0 +1,116 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"auth/internal/auth"
+	"auth/internal/auth/guard"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationStartEmailChange = "start_email_change"
+	FileStartEmailChange      = OperationStartEmailChange + ".go"
+)
+
+// handleUserStartEmailChange sends a confirmation code to the new address of the user of the bearer token,
+// and a link that cancels the change to its current address.
+func (s *UserServer) handleUserStartEmailChange() http.HandlerFunc {
+	const self = "handleUserStartEmailChange"
+
+	type request struct {
+		Password string `json:"password" validate:"required"`
+		Email    string `json:"email" validate:"required,email"`
+	}
+
+	contract := map[string]responder.Field{
+		"Password": {
+			Name:       "password",
+			Validation: "Field value cannot be an empty string.",
+		},
+		"Email": {
+			Name:       "email",
+			Validation: "Field is required and must be a valid email.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		_, claims, err := guard.FromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationStartEmailChange))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		sub, err := uuid.Parse(claims["sub"].(string))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationStartEmailChange))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid UUID.")
+			return
+		}
+
+		req, err := responder.Decode[request](r)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationStartEmailChange))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body is invalid.")
+			return
+		}
+
+		if errors := responder.ValidateInput(s.inputValidator, req, contract); len(errors) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationStartEmailChange))
+			span.RecordError(err)
+			responder.RespondClientErrors(w, r, errors...)
+			return
+		}
+
+		err = s.authService.StartEmailChange(ctx, auth.StartEmailChangeRequest{
+			UserID:   sub,
+			Password: req.Password,
+			Email:    req.Email,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationStartEmailChange))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			case auth.ErrInvalidCredentials:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Provided credentials was invalid.")
+			case auth.ErrEmailUnchanged:
+				responder.RespondMetaMessage(w, r, http.StatusBadRequest, "New email address is the current one.")
+			case user.ErrEmailAlreadyInUse:
+				responder.RespondMetaMessage(w, r, http.StatusConflict, "There is already an user with provided email.")
+			default:
+				s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileStartEmailChange, self, "failed to start email change", err))
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.RespondMetaMessage(w, r, http.StatusAccepted, "A confirmation code was sent to the new email address."); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationStartEmailChange))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileStartEmailChange, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationStartEmailChange)
+	return otelhandler.ServeHTTP
+}
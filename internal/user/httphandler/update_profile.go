@@ -0,0 +1,149 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"auth/internal/auth/guard"
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/jkitajima/responder"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	OperationUpdateProfile = "update_profile"
+	FileUpdateProfile      = OperationUpdateProfile + ".go"
+)
+
+// handleUserUpdateProfile applies a JSON Merge Patch (RFC 7396) to the profile of the user of the bearer token:
+// absent fields are left unchanged and null fields are cleared.
+func (s *UserServer) handleUserUpdateProfile() http.HandlerFunc {
+	const self = "handleUserUpdateProfile"
+
+	type profile struct {
+		DisplayName *string `validate:"omitnil,min=1,max=64"`
+		Locale      *string `validate:"omitnil,bcp47_language_tag"`
+		Timezone    *string `validate:"omitnil,timezone"`
+		AvatarURL   *string `validate:"omitnil,http_url,max=2048"`
+	}
+
+	contract := map[string]responder.Field{
+		"DisplayName": {
+			Name:       string(user.ProfileDisplayName),
+			Validation: "Field value must have between 1 and 64 characters.",
+		},
+		"Locale": {
+			Name:       string(user.ProfileLocale),
+			Validation: "Field value must be a BCP 47 language tag, such as pt-BR.",
+		},
+		"Timezone": {
+			Name:       string(user.ProfileTimezone),
+			Validation: "Field value must be an IANA time zone, such as America/Sao_Paulo.",
+		},
+		"AvatarURL": {
+			Name:       string(user.ProfileAvatarURL),
+			Validation: "Field value must be an HTTP or HTTPS URL of up to 2048 characters.",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		_, claims, err := guard.FromContext(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUpdateProfile))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Bearer token is malformatted.")
+			return
+		}
+
+		sub, err := uuid.Parse(claims["sub"].(string))
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUpdateProfile))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Invalid UUID.")
+			return
+		}
+
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body == nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUpdateProfile))
+			span.RecordError(err)
+			responder.RespondMetaMessage(w, r, http.StatusBadRequest, "Request body must be a JSON object.")
+			return
+		}
+
+		// Every member of the patch must be an editable field, either set to a string or cleared with null
+		var fields profile
+		targets := map[user.ProfileField]**string{
+			user.ProfileDisplayName: &fields.DisplayName,
+			user.ProfileLocale:      &fields.Locale,
+			user.ProfileTimezone:    &fields.Timezone,
+			user.ProfileAvatarURL:   &fields.AvatarURL,
+		}
+
+		patch := make(user.ProfilePatch, len(body))
+		var errs []responder.ErrorObject
+		for _, name := range slices.Sorted(maps.Keys(body)) {
+			field := user.ProfileField(name)
+			target, ok := targets[field]
+			if !ok {
+				detail := "Field cannot be changed."
+				errs = append(errs, responder.ErrorObject{Title: name, Detail: &detail})
+				continue
+			}
+
+			var value *string
+			if err := json.Unmarshal(body[name], &value); err != nil {
+				detail := "Field value must be a string or null."
+				errs = append(errs, responder.ErrorObject{Title: name, Detail: &detail})
+				continue
+			}
+			*target = value
+			patch[field] = value
+		}
+
+		if len(errs) == 0 {
+			errs = responder.ValidateInput(s.inputValidator, fields, contract)
+		}
+		if len(errs) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUpdateProfile))
+			responder.RespondClientErrors(w, r, errs...)
+			return
+		}
+
+		updateResponse, err := s.service.UpdateProfile(ctx, user.UpdateProfileRequest{ID: sub, Patch: patch})
+		if err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUpdateProfile))
+			span.RecordError(err)
+			switch err {
+			case user.ErrNotFoundByID:
+				responder.RespondMetaMessage(w, r, http.StatusNotFound, "Could not find any user with provided ID.")
+			default:
+				responder.RespondInternalError(w, r)
+			}
+			return
+		}
+
+		if err := responder.Respond(w, r, http.StatusOK, &responder.DataField{Data: s.newUserResponse(updateResponse.User)}); err != nil {
+			span.SetStatus(codes.Error, fmt.Sprintf("%s failed", OperationUpdateProfile))
+			span.RecordError(err)
+			s.logger.ErrorContext(ctx, otel.FormatLog(Path, FileUpdateProfile, self, "failed to encode response", err))
+			responder.RespondInternalError(w, r)
+			return
+		}
+	}
+
+	otelhandler := otelhttp.NewHandler(http.HandlerFunc(handler), OperationUpdateProfile)
+	return otelhandler.ServeHTTP
+}
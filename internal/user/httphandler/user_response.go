@@ -0,0 +1,38 @@
+package httphandler
+
+import (
+	"time"
+
+	"auth/internal/user"
+
+	"github.com/google/uuid"
+)
+
+// userResponse is the public representation of a user, without its credentials.
+type userResponse struct {
+	Entity        string    `json:"entity"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	DisplayName   *string   `json:"display_name"`
+	Locale        *string   `json:"locale"`
+	Timezone      *string   `json:"timezone"`
+	AvatarURL     *string   `json:"avatar_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (s *UserServer) newUserResponse(u *user.User) userResponse {
+	return userResponse{
+		Entity:        s.entity,
+		ID:            u.ID,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		DisplayName:   u.DisplayName,
+		Locale:        u.Locale,
+		Timezone:      u.Timezone,
+		AvatarURL:     u.AvatarURL,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	}
+}
@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListSort is the column that users are listed by, with ties broken by ID.
+type ListSort string
+
+const (
+	SortCreatedAt ListSort = "created_at"
+	SortEmail     ListSort = "email"
+)
+
+// DeletedFilter tells whether users pending deletion are listed.
+type DeletedFilter string
+
+const (
+	DeletedExclude DeletedFilter = "exclude"
+	DeletedInclude DeletedFilter = "include"
+	DeletedOnly    DeletedFilter = "only"
+)
+
+// ListQuery filters and sorts users, every nil or empty filter matching any user.
+type ListQuery struct {
+	// Case-insensitive substring of the email address
+	Email         string
+	Verified      *bool
+	Disabled      *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Deleted       DeletedFilter
+	Sort          ListSort
+	Descending    bool
+	// Users are listed from the one following the cursor, if any
+	After *ListCursor
+	Limit int
+}
+
+// ListCursor is the position of the last user of a page, in the sort it was listed by.
+type ListCursor struct {
+	Sort       ListSort  `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	CreatedAt  time.Time `json:"c"`
+	Email      string    `json:"e"`
+	ID         uuid.UUID `json:"i"`
+}
+
+type ListRequest struct {
+	Query ListQuery
+	// Opaque cursor returned along with the previous page
+	Cursor string
+}
+
+type ListResponse struct {
+	Users []*User
+	// Empty on the last page
+	NextCursor string
+}
+
+// List returns a page of the users matching the query, using keyset pagination
+// so that pages stay consistent while users are created or deleted.
+// It fails with ErrInvalidCursor for cursors not issued for the sort of the query.
+func (s *Service) List(ctx context.Context, req ListRequest) (ListResponse, error) {
+	query := req.Query
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor)
+		if err != nil || after.Sort != query.Sort || after.Descending != query.Descending {
+			return ListResponse{}, ErrInvalidCursor
+		}
+		query.After = after
+	}
+
+	// One more user is fetched to tell whether there is a next page
+	limit := query.Limit
+	query.Limit++
+	users, err := s.Repo.List(ctx, query)
+	if err != nil {
+		return ListResponse{}, err
+	}
+	if len(users) <= limit {
+		return ListResponse{Users: users}, nil
+	}
+
+	users = users[:limit]
+	last := users[limit-1]
+	next, err := encodeCursor(&ListCursor{
+		Sort:       query.Sort,
+		Descending: query.Descending,
+		CreatedAt:  last.CreatedAt,
+		Email:      last.Email,
+		ID:         last.ID,
+	})
+	if err != nil {
+		return ListResponse{}, err
+	}
+	return ListResponse{Users: users, NextCursor: next}, nil
+}
+
+func encodeCursor(cursor *ListCursor) (string, error) {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string) (*ListCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor ListCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,19 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+type PurgeDeletedResponse struct {
+	Purged int64
+}
+
+// PurgeDeleted permanently deletes the users whose grace period has run out, along with their dependent data.
+func (s *Service) PurgeDeleted(ctx context.Context) (PurgeDeletedResponse, error) {
+	purged, err := s.Repo.PurgeDeleted(ctx, s.Deletion.RestorableAfter(time.Now()))
+	if err != nil {
+		return PurgeDeletedResponse{}, err
+	}
+	return PurgeDeletedResponse{purged}, nil
+}
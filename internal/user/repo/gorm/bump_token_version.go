@@ -0,0 +1,38 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileBumpTokenVersion = "bump_token_version.go"
+
+func (db *DB) BumpTokenVersion(ctx context.Context, id uuid.UUID) error {
+	const self = "BumpTokenVersion"
+
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"token_version": gorm.Expr("token_version + 1"),
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileBumpTokenVersion, self, "failed to bump token version", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileBumpTokenVersion, self, fmt.Sprintf("bumped token version of user with id %q", id.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"context"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileCountVerificationAttempt = "count_verification_attempt.go"
+
+// CountVerificationAttempt increments the verification attempts of the user and returns the new count.
+func (db *DB) CountVerificationAttempt(ctx context.Context, id uuid.UUID) (int, error) {
+	const self = "CountVerificationAttempt"
+
+	var models []UserModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "verification_attempts"}}}).
+		Where("id = ?", id).
+		Update("verification_attempts", gorm.Expr("verification_attempts + 1"))
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileCountVerificationAttempt, self, "failed to count verification attempt", result.Error))
+		return 0, user.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return 0, user.ErrNotFoundByID
+	}
+
+	return models[0].VerificationAttempts, nil
+}
@@ -0,0 +1,41 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileDisableByID = "disable_by_id.go"
+
+func (db *DB) DisableByID(ctx context.Context, id uuid.UUID, now time.Time) error {
+	const self = "DisableByID"
+
+	// Bumping the token version invalidates every access token and session of the user,
+	// while users disabled more than once keep the time they were first disabled
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"disabled_at":   gorm.Expr("COALESCE(disabled_at, ?)", now),
+			"token_version": gorm.Expr("token_version + 1"),
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileDisableByID, self, "failed to disable user", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileDisableByID, self, fmt.Sprintf("disabled user with id %q", id.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileEnableByID = "enable_by_id.go"
+
+func (db *DB) EnableByID(ctx context.Context, id uuid.UUID) error {
+	const self = "EnableByID"
+
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"disabled_at": nil,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileEnableByID, self, "failed to enable user", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileEnableByID, self, fmt.Sprintf("enabled user with id %q", id.String()), nil))
+
+	return nil
+}
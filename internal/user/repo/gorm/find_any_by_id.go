@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindAnyByID = "find_any_by_id.go"
+
+func (db *DB) FindAnyByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	const self = "FindAnyByID"
+	span := trace.SpanFromContext(ctx)
+
+	var model UserModel
+	result := db.Unscoped().First(&model, "id = ?", id.String())
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, user.ErrNotFoundByID
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindAnyByID, self, user.ErrNotFoundByID.Error(), result.Error))
+			return nil, user.ErrInternal
+		}
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileFindAnyByID, self, fmt.Sprintf("found user with id %q", model.ID.String()), nil))
+	span.AddEvent(fmt.Sprintf("db query returned user_id %q", id.String()))
+
+	return model.toUser(), nil
+}
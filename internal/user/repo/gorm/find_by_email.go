@@ -3,7 +3,6 @@ package gorm
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"auth/internal/user"
 	"auth/pkg/otel"
@@ -33,22 +32,5 @@ func (db *DB) FindByEmail(ctx context.Context, email string) (*user.User, error)
 	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileFindByEmail, self, fmt.Sprintf("found user with email %q", model.Email), nil))
 	span.AddEvent(fmt.Sprintf("db query returned user_email %q", model.Email))
 
-	expiration := model.VerificationCodeExpiration
-	var unixts *time.Time
-	if expiration != nil {
-		t := time.Unix(int64(*model.VerificationCodeExpiration), 0)
-		unixts = &t
-	}
-
-	user := user.User{
-		ID:                         model.ID,
-		Email:                      model.Email,
-		EmailVerified:              model.EmailVerified,
-		Password:                   model.Password,
-		VerificationCode:           model.VerificationCode,
-		VerificationCodeExpiration: unixts,
-		CreatedAt:                  model.CreatedAt,
-		UpdatedAt:                  model.UpdatedAt,
-	}
-	return &user, nil
+	return model.toUser(), nil
 }
@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const FileFindDeletedByEmail = "find_deleted_by_email.go"
+
+func (db *DB) FindDeletedByEmail(ctx context.Context, email string, after time.Time) (*user.User, error) {
+	const self = "FindDeletedByEmail"
+	span := trace.SpanFromContext(ctx)
+
+	var model UserModel
+	result := db.Unscoped().Where("email = ? AND deleted_at > ?", email, after).First(&model)
+	if result.Error != nil {
+		switch result.Error {
+		case gorm.ErrRecordNotFound:
+			return nil, user.ErrNotFoundByEmail
+		default:
+			span.AddEvent("db query failed")
+			db.logger.WarnContext(ctx, otel.FormatLog(Path, FileFindDeletedByEmail, self, user.ErrNotFoundByEmail.Error(), result.Error))
+			return nil, user.ErrInternal
+		}
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileFindDeletedByEmail, self, fmt.Sprintf("found deleted user with email %q", model.Email), nil))
+	span.AddEvent(fmt.Sprintf("db query returned deleted user_email %q", model.Email))
+
+	return model.toUser(), nil
+}
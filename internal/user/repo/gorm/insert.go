@@ -33,6 +33,11 @@ func (db *DB) Insert(ctx context.Context, u *user.User) error {
 		Password:                   u.Password,
 		VerificationCode:           u.VerificationCode,
 		VerificationCodeExpiration: expptr,
+		VerificationAttempts:       u.VerificationAttempts,
+		DisplayName:                u.DisplayName,
+		Locale:                     u.Locale,
+		Timezone:                   u.Timezone,
+		AvatarURL:                  u.AvatarURL,
 		CreatedAt:                  u.CreatedAt,
 		UpdatedAt:                  u.UpdatedAt,
 	}
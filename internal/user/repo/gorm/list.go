@@ -0,0 +1,81 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const FileList = "list.go"
+
+// likeEscaper escapes the wildcards of LIKE patterns, whose default escape character is the backslash.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (db *DB) List(ctx context.Context, query user.ListQuery) ([]*user.User, error) {
+	const self = "List"
+	span := trace.SpanFromContext(ctx)
+
+	tx := db.Model(&UserModel{})
+	switch query.Deleted {
+	case user.DeletedInclude:
+		tx = tx.Unscoped()
+	case user.DeletedOnly:
+		tx = tx.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	if query.Email != "" {
+		tx = tx.Where("email ILIKE ?", "%"+likeEscaper.Replace(query.Email)+"%")
+	}
+	if query.Verified != nil {
+		tx = tx.Where("email_verified = ?", *query.Verified)
+	}
+	if query.Disabled != nil {
+		if *query.Disabled {
+			tx = tx.Where("disabled_at IS NOT NULL")
+		} else {
+			tx = tx.Where("disabled_at IS NULL")
+		}
+	}
+	if query.CreatedAfter != nil {
+		tx = tx.Where("created_at >= ?", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		tx = tx.Where("created_at < ?", *query.CreatedBefore)
+	}
+
+	// Users are paginated by keyset, the ID breaking ties between equal sort values
+	column, direction, comparison := "created_at", "ASC", ">"
+	if query.Sort == user.SortEmail {
+		column = "email"
+	}
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		var value any = query.After.CreatedAt
+		if query.Sort == user.SortEmail {
+			value = query.After.Email
+		}
+		tx = tx.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), value, query.After.ID)
+	}
+
+	var models []UserModel
+	result := tx.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).Limit(query.Limit).Find(&models)
+	if result.Error != nil {
+		span.AddEvent("db query failed")
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileList, self, "failed to list users", result.Error))
+		return nil, user.ErrInternal
+	}
+	span.AddEvent(fmt.Sprintf("db query returned %d users", len(models)))
+
+	users := make([]*user.User, 0, len(models))
+	for _, model := range models {
+		users = append(users, model.toUser())
+	}
+	return users, nil
+}
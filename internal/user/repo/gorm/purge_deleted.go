@@ -0,0 +1,29 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+)
+
+const FilePurgeDeleted = "purge_deleted.go"
+
+func (db *DB) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	const self = "PurgeDeleted"
+
+	// Dependent rows are removed by the cascading foreign keys to the user
+	result := db.Unscoped().Where("deleted_at <= ?", before).Delete(&UserModel{})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FilePurgeDeleted, self, "failed to purge deleted users", result.Error))
+		return 0, user.ErrInternal
+	}
+
+	if result.RowsAffected > 0 {
+		db.logger.InfoContext(ctx, otel.FormatLog(Path, FilePurgeDeleted, self, fmt.Sprintf("purged %d deleted users", result.RowsAffected), nil))
+	}
+
+	return result.RowsAffected, nil
+}
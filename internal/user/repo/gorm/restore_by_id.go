@@ -0,0 +1,38 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileRestoreByID = "restore_by_id.go"
+
+func (db *DB) RestoreByID(ctx context.Context, id uuid.UUID, after time.Time) error {
+	const self = "RestoreByID"
+
+	result := db.
+		Unscoped().
+		Model(&UserModel{}).
+		Where("id = ? AND deleted_at > ?", id, after).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileRestoreByID, self, "failed to restore user", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundDeleted
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileRestoreByID, self, fmt.Sprintf("restored user with id %q", id.String()), nil))
+
+	return nil
+}
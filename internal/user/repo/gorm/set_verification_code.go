@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+)
+
+const FileSetVerificationCode = "set_verification_code.go"
+
+func (db *DB) SetVerificationCode(ctx context.Context, u *user.User) error {
+	const self = "SetVerificationCode"
+
+	var expiration *int
+	if u.VerificationCodeExpiration != nil {
+		unix := int(u.VerificationCodeExpiration.Unix())
+		expiration = &unix
+	}
+
+	now := time.Now()
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", u.ID).
+		Updates(map[string]any{
+			"verification_code":            u.VerificationCode,
+			"verification_code_expiration": expiration,
+			"verification_attempts":        u.VerificationAttempts,
+			"updated_at":                   now,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileSetVerificationCode, self, "failed to set user verification code", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileSetVerificationCode, self, fmt.Sprintf("set verification code of user with id %q", u.ID.String()), nil))
+
+	u.UpdatedAt = now
+
+	return nil
+}
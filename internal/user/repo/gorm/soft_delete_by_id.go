@@ -0,0 +1,41 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const FileSoftDeleteByID = "soft_delete_by_id.go"
+
+func (db *DB) SoftDeleteByID(ctx context.Context, id uuid.UUID, now time.Time) error {
+	const self = "SoftDeleteByID"
+
+	// Bumping the token version invalidates every access token and session of the user,
+	// which stay invalid if the user is restored
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"deleted_at":    now,
+			"token_version": gorm.Expr("token_version + 1"),
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileSoftDeleteByID, self, "failed to soft delete user", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileSoftDeleteByID, self, fmt.Sprintf("soft deleted user with id %q", id.String()), nil))
+
+	return nil
+}
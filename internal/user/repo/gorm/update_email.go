@@ -0,0 +1,45 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const FileUpdateEmail = "update_email.go"
+
+func (db *DB) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	const self = "UpdateEmail"
+
+	// The address was confirmed with a code sent to it, so it is verified as well
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email":          email,
+			"email_verified": true,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUpdateEmail, self, "failed to update user email", result.Error))
+		var pgErr *pgconn.PgError
+		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
+			return user.ErrEmailAlreadyInUse
+		}
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUpdateEmail, self, fmt.Sprintf("updated email of user with id %q", id.String()), nil))
+
+	return nil
+}
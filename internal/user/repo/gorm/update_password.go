@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const FileUpdatePassword = "update_password.go"
+
+func (db *DB) UpdatePassword(ctx context.Context, u *user.User) error {
+	const self = "UpdatePassword"
+
+	// The version is incremented by the database, so that concurrent
+	// password changes can never end up sharing the same version
+	var models []UserModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "token_version"}, {Name: "updated_at"}}}).
+		Where("id = ?", u.ID).
+		Updates(map[string]any{
+			"password":      u.Password,
+			"token_version": gorm.Expr("token_version + 1"),
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUpdatePassword, self, "failed to update user password", result.Error))
+		return user.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUpdatePassword, self, fmt.Sprintf("updated password of user with id %q", u.ID.String()), nil))
+
+	u.TokenVersion = models[0].TokenVersion
+	u.UpdatedAt = models[0].UpdatedAt
+
+	return nil
+}
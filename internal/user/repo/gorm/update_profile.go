@@ -0,0 +1,59 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+const FileUpdateProfile = "update_profile.go"
+
+var profileColumns = map[user.ProfileField]string{
+	user.ProfileDisplayName: "display_name",
+	user.ProfileLocale:      "locale",
+	user.ProfileTimezone:    "timezone",
+	user.ProfileAvatarURL:   "avatar_url",
+}
+
+func (db *DB) UpdateProfile(ctx context.Context, id uuid.UUID, patch user.ProfilePatch) (*user.User, error) {
+	const self = "UpdateProfile"
+
+	// Only the columns of the patch are written, so that concurrent patches
+	// of different fields do not overwrite each other
+	columns := map[string]any{"updated_at": time.Now()}
+	for field, value := range patch {
+		column, ok := profileColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w (%q)", user.ErrUnknownField, field)
+		}
+		if value == nil {
+			columns[column] = nil
+			continue
+		}
+		columns[column] = *value
+	}
+
+	var models []UserModel
+	result := db.
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Updates(columns)
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileUpdateProfile, self, "failed to update user profile", result.Error))
+		return nil, user.ErrInternal
+	}
+
+	if len(models) == 0 {
+		return nil, user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileUpdateProfile, self, fmt.Sprintf("updated profile of user with id %q", id.String()), nil))
+
+	return models[0].toUser(), nil
+}
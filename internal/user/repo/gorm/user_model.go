@@ -3,6 +3,8 @@ package gorm
 import (
 	"time"
 
+	"auth/internal/user"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -14,11 +16,52 @@ type UserModel struct {
 	Password                   string    `gorm:"not null"`
 	VerificationCode           *string
 	VerificationCodeExpiration *int
-	CreatedAt                  time.Time      `gorm:"not null"`
+	VerificationAttempts       int            `gorm:"not null;default:0"`
+	TokenVersion               int            `gorm:"not null;default:0"`
+	CreatedAt                  time.Time      `gorm:"not null;index"`
 	UpdatedAt                  time.Time      `gorm:"not null"`
 	DeletedAt                  gorm.DeletedAt `gorm:"index"`
+	DisabledAt                 *time.Time
+	// Profile
+	DisplayName *string
+	Locale      *string
+	Timezone    *string
+	AvatarURL   *string
 }
 
 func (*UserModel) TableName() string {
 	return "User"
 }
+
+// toUser maps a row to a user, including the deletion time of users pending deletion.
+func (m *UserModel) toUser() *user.User {
+	var expiration *time.Time
+	if m.VerificationCodeExpiration != nil {
+		t := time.Unix(int64(*m.VerificationCodeExpiration), 0)
+		expiration = &t
+	}
+
+	var deletedAt *time.Time
+	if m.DeletedAt.Valid {
+		deletedAt = &m.DeletedAt.Time
+	}
+
+	return &user.User{
+		ID:                         m.ID,
+		Email:                      m.Email,
+		EmailVerified:              m.EmailVerified,
+		Password:                   m.Password,
+		VerificationCode:           m.VerificationCode,
+		VerificationCodeExpiration: expiration,
+		VerificationAttempts:       m.VerificationAttempts,
+		TokenVersion:               m.TokenVersion,
+		DisplayName:                m.DisplayName,
+		Locale:                     m.Locale,
+		Timezone:                   m.Timezone,
+		AvatarURL:                  m.AvatarURL,
+		CreatedAt:                  m.CreatedAt,
+		UpdatedAt:                  m.UpdatedAt,
+		DeletedAt:                  deletedAt,
+		DisabledAt:                 m.DisabledAt,
+	}
+}
@@ -0,0 +1,40 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auth/internal/user"
+	"auth/pkg/otel"
+
+	"github.com/google/uuid"
+)
+
+const FileVerifyEmailByID = "verify_email_by_id.go"
+
+func (db *DB) VerifyEmailByID(ctx context.Context, id uuid.UUID) error {
+	const self = "VerifyEmailByID"
+
+	result := db.
+		Model(&UserModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email_verified":               true,
+			"verification_code":            nil,
+			"verification_code_expiration": nil,
+			"verification_attempts":        0,
+			"updated_at":                   time.Now(),
+		})
+	if result.Error != nil {
+		db.logger.WarnContext(ctx, otel.FormatLog(Path, FileVerifyEmailByID, self, "failed to verify user email", result.Error))
+		return user.ErrInternal
+	}
+
+	if result.RowsAffected == 0 {
+		return user.ErrNotFoundByID
+	}
+	db.logger.InfoContext(ctx, otel.FormatLog(Path, FileVerifyEmailByID, self, fmt.Sprintf("verified email of user with id %q", id.String()), nil))
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RestoreByIDRequest struct {
+	ID uuid.UUID
+}
+
+// RestoreByID cancels the deletion of a user still within the grace period.
+// It fails with ErrNotFoundDeleted for users that are not pending deletion.
+func (s *Service) RestoreByID(ctx context.Context, req RestoreByIDRequest) error {
+	return s.Repo.RestoreByID(ctx, req.ID, s.Deletion.RestorableAfter(time.Now()))
+}
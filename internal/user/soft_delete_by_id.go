@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"auth/pkg/password"
+
+	"github.com/google/uuid"
+)
+
+type SoftDeleteByIDRequest struct {
+	ID       uuid.UUID
+	Password string
+}
+
+// SoftDeleteByID marks the user as pending deletion, which signs it out everywhere.
+// The user can be restored until the grace period runs out, and is then purged.
+func (s *Service) SoftDeleteByID(ctx context.Context, req SoftDeleteByIDRequest) error {
+	// Check if the user exists first
+	findResponse, err := s.FindByID(ctx, FindByIDRequest{req.ID})
+	if err != nil {
+		return err
+	}
+
+	// Check if incoming password matches stored password
+	checkPasswordRequest := password.CheckPasswordRequest{
+		Input:    req.Password,
+		Password: findResponse.User.Password,
+	}
+	checkPasswordResponse, err := password.CheckPassword(ctx, checkPasswordRequest)
+	if err != nil {
+		return err
+	}
+
+	// If match is not valid, then deny the deletion
+	if !checkPasswordResponse.Valid {
+		return ErrInvalidCredentials
+	}
+
+	return s.Repo.SoftDeleteByID(ctx, req.ID, time.Now())
+}
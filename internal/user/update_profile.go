@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ProfileField is an editable field of the profile of a user, named as in the API.
+type ProfileField string
+
+const (
+	ProfileDisplayName ProfileField = "display_name"
+	ProfileLocale      ProfileField = "locale"
+	ProfileTimezone    ProfileField = "timezone"
+	ProfileAvatarURL   ProfileField = "avatar_url"
+)
+
+// ProfilePatch is a JSON Merge Patch (RFC 7396) of the profile of a user:
+// absent fields are left unchanged and fields set to nil are cleared.
+type ProfilePatch map[ProfileField]*string
+
+type UpdateProfileRequest struct {
+	ID    uuid.UUID
+	Patch ProfilePatch
+}
+
+type UpdateProfileResponse struct {
+	User *User
+}
+
+func (s *Service) UpdateProfile(ctx context.Context, req UpdateProfileRequest) (UpdateProfileResponse, error) {
+	// An empty patch changes nothing, the user is returned as is
+	if len(req.Patch) == 0 {
+		findResponse, err := s.FindByID(ctx, FindByIDRequest{req.ID})
+		if err != nil {
+			return UpdateProfileResponse{}, err
+		}
+		return UpdateProfileResponse{findResponse.User}, nil
+	}
+
+	u, err := s.Repo.UpdateProfile(ctx, req.ID, req.Patch)
+	if err != nil {
+		return UpdateProfileResponse{}, err
+	}
+	return UpdateProfileResponse{u}, nil
+}
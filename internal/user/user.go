@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"auth/pkg/password"
+
 	"github.com/google/uuid"
 )
 
@@ -14,6 +16,9 @@ var (
 	ErrNotFoundByID       = errors.New("could not find any user with provided ID")
 	ErrNotFoundByEmail    = errors.New("could not find any user with provided email")
 	ErrEmailAlreadyInUse  = errors.New("provided email address is already in use")
+	ErrNotFoundDeleted    = errors.New("could not find any user pending deletion with provided ID")
+	ErrUnknownField       = errors.New("unknown profile field")
+	ErrInvalidCursor      = errors.New("cursor is malformed or was issued for another sort")
 )
 
 type User struct {
@@ -23,18 +28,66 @@ type User struct {
 	Password                   string
 	VerificationCode           *string
 	VerificationCodeExpiration *time.Time
-	CreatedAt                  time.Time
-	UpdatedAt                  time.Time
-	DeletedAt                  *time.Time
+	VerificationAttempts       int
+	// TokenVersion is stamped on access tokens and bumped whenever the password changes,
+	// which invalidates every access token issued before
+	TokenVersion int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	// DeletedAt is set on users pending deletion, which can be restored until the grace period runs out
+	DeletedAt *time.Time
+	// DisabledAt is set on users disabled by an admin, who cannot sign in until enabled again
+	DisabledAt *time.Time
+	// Profile, editable by the user and empty until then
+	DisplayName *string
+	Locale      *string
+	Timezone    *string
+	AvatarURL   *string
+}
+
+type DeletionConfig struct {
+	// Seconds that a deleted user can be restored for, before being purged
+	GracePeriod int
+	// Seconds between purges of users deleted past the grace period
+	Purge int
 }
 
 type Service struct {
-	Repo Repoer
+	Repo           Repoer
+	PasswordPolicy *password.Policy
+	Deletion       *DeletionConfig
 }
 
 type Repoer interface {
 	Insert(context.Context, *User) error
 	FindByID(context.Context, uuid.UUID) (*User, error)
+	// FindAnyByID also finds users pending deletion
+	FindAnyByID(context.Context, uuid.UUID) (*User, error)
+	List(context.Context, ListQuery) ([]*User, error)
 	FindByEmail(context.Context, string) (*User, error)
+	// SetVerificationCode replaces the verification code, its expiration and attempts of the user
+	SetVerificationCode(context.Context, *User) error
+	// CountVerificationAttempt increments the verification attempts of the user and returns the new count
+	CountVerificationAttempt(context.Context, uuid.UUID) (int, error)
+	UpdateProfile(context.Context, uuid.UUID, ProfilePatch) (*User, error)
+	// UpdateEmail changes the email address of a user to a confirmed one
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+	UpdatePassword(context.Context, *User) error
+	VerifyEmailByID(context.Context, uuid.UUID) error
+	// BumpTokenVersion invalidates every access token and login page session of the user
+	BumpTokenVersion(context.Context, uuid.UUID) error
+	// Disabling a user bumps its token version, just like a password change
+	DisableByID(context.Context, uuid.UUID, time.Time) error
+	EnableByID(context.Context, uuid.UUID) error
 	HardDeleteByID(context.Context, uuid.UUID) error
+	SoftDeleteByID(context.Context, uuid.UUID, time.Time) error
+	// Users pending deletion are only found when they were deleted after the given time
+	FindDeletedByEmail(ctx context.Context, email string, after time.Time) (*User, error)
+	RestoreByID(ctx context.Context, id uuid.UUID, after time.Time) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RestorableAfter returns the time after which deleted users are still within the grace period.
+func (c *DeletionConfig) RestorableAfter(now time.Time) time.Time {
+	return now.Add(-time.Duration(c.GracePeriod) * time.Second)
 }
@@ -0,0 +1,17 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type VerifyEmailByIDRequest struct {
+	ID uuid.UUID
+}
+
+// VerifyEmailByID marks the email address of the user as verified without a verification code,
+// discarding the pending one, if any.
+func (s *Service) VerifyEmailByID(ctx context.Context, req VerifyEmailByIDRequest) error {
+	return s.Repo.VerifyEmailByID(ctx, req.ID)
+}
@@ -0,0 +1,227 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoPEMBlock           = errors.New("keys: no PEM block found")
+	ErrUnsupportedKey       = errors.New("keys: unsupported private key type")
+	ErrAlgorithmMismatch    = errors.New("keys: private key does not match the signing algorithm")
+	ErrEmptyKeyDir          = errors.New("keys: key directory does not contain any PEM file")
+	ErrUnsupportedAlgorithm = errors.New("keys: unsupported signing algorithm")
+	ErrKeyExists            = errors.New("keys: key file already exists")
+)
+
+// Key is an asymmetric private key loaded from a PEM file.
+// ID is the file name without its extension and ModTime is the
+// time that the file was last written.
+type Key struct {
+	ID      string
+	Private crypto.Signer
+	ModTime time.Time
+}
+
+// Parse decodes the first PEM block of data as a PKCS #1, SEC 1 or PKCS #8 private key.
+func Parse(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrNoPEMBlock
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM block type %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keys: parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+	return signer, nil
+}
+
+// LoadFile reads and parses a PEM encoded private key file.
+func LoadFile(path string) (Key, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("keys: stat %q: %w", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("keys: read %q: %w", path, err)
+	}
+
+	private, err := Parse(data)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w (file %q)", err, path)
+	}
+
+	name := filepath.Base(path)
+	return Key{
+		ID:      strings.TrimSuffix(name, filepath.Ext(name)),
+		Private: private,
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+// LoadDir loads every "*.pem" file of dir, sorted by file name.
+func LoadDir(dir string) ([]Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("keys: list %q: %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return nil, ErrEmptyKeyDir
+	}
+	sort.Strings(paths)
+
+	keys := make([]Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Check reports whether key can be used for signing with the JWS algorithm alg.
+func Check(alg string, key crypto.Signer) error {
+	mismatch := fmt.Errorf("%w: %s cannot sign with %T", ErrAlgorithmMismatch, alg, key)
+
+	switch alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		if _, ok := key.(*rsa.PrivateKey); !ok {
+			return mismatch
+		}
+	case "ES256", "ES384", "ES512":
+		private, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return mismatch
+		}
+
+		curves := map[string]elliptic.Curve{
+			"ES256": elliptic.P256(),
+			"ES384": elliptic.P384(),
+			"ES512": elliptic.P521(),
+		}
+		if private.Curve != curves[alg] {
+			return fmt.Errorf("%w: %s requires curve %s", ErrAlgorithmMismatch, alg, curves[alg].Params().Name)
+		}
+	case "EdDSA":
+		if _, ok := key.(ed25519.PrivateKey); !ok {
+			return mismatch
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+
+	return nil
+}
+
+// Generate creates a new private key suitable for the JWS algorithm alg.
+func Generate(alg string) (crypto.Signer, error) {
+	switch alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ES512":
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case "EdDSA":
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// Write stores private as a PKCS #8 PEM file named "<id>.pem" inside dir.
+// The file is written to a temporary path first and then linked to its name,
+// so that concurrent readers of dir never observe a partial key. Linking fails
+// when the name is taken, in which case ErrKeyExists is returned and the
+// existing file is left untouched, so that concurrent writers of the same id
+// end up with a single key.
+func Write(dir, id string, private crypto.Signer) (Key, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return Key{}, fmt.Errorf("keys: marshal private key: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	tmp, err := os.CreateTemp(dir, id+".*.tmp")
+	if err != nil {
+		return Key{}, fmt.Errorf("keys: create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("keys: write %q: %w", tmp.Name(), err)
+	}
+
+	path := filepath.Join(dir, id+".pem")
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return Key{}, fmt.Errorf("%w (file %q)", ErrKeyExists, path)
+		}
+		return Key{}, fmt.Errorf("keys: link %q: %w", path, err)
+	}
+
+	return LoadFile(path)
+}
+
+// Remove deletes the file of the key named id from dir. A missing file is not an error,
+// since concurrent removers of the same key are expected.
+func Remove(dir, id string) error {
+	path := filepath.Join(dir, id+".pem")
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("keys: remove %q: %w", path, err)
+	}
+	return nil
+}
+
+// IsSymmetric reports whether alg is an HMAC based JWS algorithm.
+func IsSymmetric(alg string) bool {
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		return true
+	}
+	return false
+}
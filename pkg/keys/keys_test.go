@@ -0,0 +1,163 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var algorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+func TestGenerate(t *testing.T) {
+	for _, alg := range algorithms {
+		t.Run(alg, func(t *testing.T) {
+			private, err := Generate(alg)
+			require.NoError(t, err)
+			require.NoError(t, Check(alg, private))
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := Generate("HS256")
+		require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	})
+}
+
+func TestCheck(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		alg  string
+		key  crypto.Signer
+		err  error
+	}{
+		{"rsa", "RS256", rsaKey, nil},
+		{"rsa_pss", "PS512", rsaKey, nil},
+		{"p256", "ES256", p256, nil},
+		{"p384", "ES384", p384, nil},
+		{"p521", "ES512", p521, nil},
+		{"ed25519", "EdDSA", edKey, nil},
+		{"rsa_with_ecdsa_key", "RS256", p256, ErrAlgorithmMismatch},
+		{"ecdsa_with_rsa_key", "ES256", rsaKey, ErrAlgorithmMismatch},
+		{"eddsa_with_rsa_key", "EdDSA", rsaKey, ErrAlgorithmMismatch},
+		{"es256_with_p384_key", "ES256", p384, ErrAlgorithmMismatch},
+		{"es512_with_p256_key", "ES512", p256, ErrAlgorithmMismatch},
+		{"hmac", "HS256", rsaKey, ErrUnsupportedAlgorithm},
+		{"unknown", "none", edKey, ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.alg, tt.key)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	encode := func(typ string, der []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		key  crypto.Signer
+		err  error
+	}{
+		{"pkcs1", encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), rsaKey, nil},
+		{"sec1", encode("EC PRIVATE KEY", sec1), ecKey, nil},
+		{"pkcs8", encode("PRIVATE KEY", pkcs8), edKey, nil},
+		{"no_pem_block", []byte("not a key"), nil, ErrNoPEMBlock},
+		{"public_key", encode("PUBLIC KEY", nil), nil, ErrUnsupportedKey},
+		{"wrong_block_type", encode("EC PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			private, err := Parse(tt.data)
+			switch {
+			case tt.key != nil:
+				require.NoError(t, err)
+				require.True(t, tt.key.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(private.Public()))
+			case tt.err != nil:
+				require.ErrorIs(t, err, tt.err)
+			default:
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadDir(dir)
+	require.ErrorIs(t, err, ErrEmptyKeyDir)
+
+	first, err := Generate("ES256")
+	require.NoError(t, err)
+	second, err := Generate("ES256")
+	require.NoError(t, err)
+
+	written, err := Write(dir, "b", first)
+	require.NoError(t, err)
+	require.Equal(t, "b", written.ID)
+	_, err = Write(dir, "a", second)
+	require.NoError(t, err)
+
+	// Files other than "*.pem" are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("keys"), 0o600))
+
+	loaded, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	require.Equal(t, "a", loaded[0].ID)
+	require.Equal(t, "b", loaded[1].ID)
+	require.True(t, first.Public().(*ecdsa.PublicKey).Equal(loaded[1].Private.Public()))
+
+	_, err = LoadFile(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+}
+
+func TestIsSymmetric(t *testing.T) {
+	for alg, symmetric := range map[string]bool{"HS256": true, "HS384": true, "HS512": true, "RS256": false, "EdDSA": false} {
+		require.Equal(t, symmetric, IsSymmetric(alg), alg)
+	}
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	"errors"
+
+	"github.com/jkitajima/responder"
+)
+
+// ErrorObjects converts every policy rule that err reports as failed
+// into a client error of the given request field, with the rule as its code.
+func ErrorObjects(err error, field string) ([]responder.ErrorObject, bool) {
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		return nil, false
+	}
+
+	objects := make([]responder.ErrorObject, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		rule, message := v.Rule, v.Message
+		objects = append(objects, responder.ErrorObject{
+			Code:   &rule,
+			Title:  field,
+			Detail: &message,
+		})
+	}
+	return objects, true
+}
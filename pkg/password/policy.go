@@ -0,0 +1,163 @@
+package password
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrPolicyViolated = errors.New("password does not comply with the password policy")
+
+// Rules reported by the policy, which clients may use to localize messages.
+const (
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+	RuleUpper     = "upper"
+	RuleLower     = "lower"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleStrength  = "strength"
+	RuleEmail     = "email"
+	RuleBreached  = "breached"
+)
+
+type PolicyConfig struct {
+	MinLength int
+	MaxLength int
+	// Required character classes
+	Upper  bool
+	Lower  bool
+	Digit  bool
+	Symbol bool
+	// Minimum strength score, from 0 (too guessable) to 4 (very unguessable)
+	MinScore int
+	// Whether passwords containing the local-part of the email are rejected
+	Email bool
+	// File of breached or common passwords, one per line
+	Denylist string
+}
+
+// Violation is a policy rule that a password failed.
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// PolicyError lists every rule that a password failed.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return fmt.Sprintf("%s (%s)", ErrPolicyViolated, strings.Join(rules, ", "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicyViolated
+}
+
+// Policy validates passwords chosen by users.
+type Policy struct {
+	config   *PolicyConfig
+	denylist map[string]struct{}
+}
+
+func NewPolicy(config *PolicyConfig) (*Policy, error) {
+	if config.MaxLength > 0 && config.MinLength > config.MaxLength {
+		return nil, fmt.Errorf("minimum password length %d is greater than maximum %d", config.MinLength, config.MaxLength)
+	}
+
+	p := &Policy{config: config, denylist: map[string]struct{}{}}
+	if config.Denylist == "" {
+		return p, nil
+	}
+
+	file, err := os.Open(config.Denylist)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.denylist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks password against every rule of the policy, returning a *PolicyError
+// listing each failed rule. The email is optional and only used by the email rule.
+func (p *Policy) Validate(password, email string) error {
+	var violations []Violation
+	violate := func(rule, message string) {
+		violations = append(violations, Violation{rule, message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		violate(RuleMinLength, fmt.Sprintf("Password must have at least %d characters.", p.config.MinLength))
+	}
+	if p.config.MaxLength > 0 && length > p.config.MaxLength {
+		violate(RuleMaxLength, fmt.Sprintf("Password must have at most %d characters.", p.config.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.config.Upper && !upper {
+		violate(RuleUpper, "Password must have an uppercase letter.")
+	}
+	if p.config.Lower && !lower {
+		violate(RuleLower, "Password must have a lowercase letter.")
+	}
+	if p.config.Digit && !digit {
+		violate(RuleDigit, "Password must have a digit.")
+	}
+	if p.config.Symbol && !symbol {
+		violate(RuleSymbol, "Password must have a symbol.")
+	}
+
+	lowered := strings.ToLower(password)
+	if p.config.Email {
+		local, _, _ := strings.Cut(strings.ToLower(email), "@")
+		if len(local) >= 3 && strings.Contains(lowered, local) {
+			violate(RuleEmail, "Password must not contain the email address.")
+		}
+	}
+
+	if _, ok := p.denylist[lowered]; ok {
+		violate(RuleBreached, "Password is too common or has appeared in a data breach.")
+	} else if score := Score(password, p.denylist); score < p.config.MinScore {
+		violate(RuleStrength, "Password is too easy to guess, try a longer password or uncommon words.")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{violations}
+	}
+	return nil
+}
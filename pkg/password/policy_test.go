@@ -0,0 +1,86 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	t.Run("min_greater_than_max", func(t *testing.T) {
+		_, err := NewPolicy(&PolicyConfig{MinLength: 12, MaxLength: 8})
+		require.Error(t, err)
+	})
+
+	t.Run("missing_denylist", func(t *testing.T) {
+		_, err := NewPolicy(&PolicyConfig{Denylist: filepath.Join(t.TempDir(), "missing.txt")})
+		require.Error(t, err)
+	})
+}
+
+func TestPolicyValidate(t *testing.T) {
+	denylist := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(denylist, []byte("# common passwords\n\nPassword1!\n"), 0o600))
+
+	policy, err := NewPolicy(&PolicyConfig{
+		MinLength: 8,
+		MaxLength: 64,
+		Upper:     true,
+		Lower:     true,
+		Digit:     true,
+		Symbol:    true,
+		Email:     true,
+		Denylist:  denylist,
+	})
+	require.NoError(t, err)
+
+	strict, err := NewPolicy(&PolicyConfig{MinScore: 3})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		password string
+		email    string
+		rules    []string
+	}{
+		{"valid", policy, "Xk7!qmzp", "", nil},
+		{"min_length", policy, "Xk7!", "", []string{RuleMinLength}},
+		{"max_length", policy, "Xk7!" + strings.Repeat("qmzp", 16), "", []string{RuleMaxLength}},
+		{"min_length_counts_runes", policy, "Xk7!çãõé", "", nil},
+		{"upper", policy, "xk7!qmzp", "", []string{RuleUpper}},
+		{"lower", policy, "XK7!QMZP", "", []string{RuleLower}},
+		{"digit", policy, "Xkq!qmzp", "", []string{RuleDigit}},
+		{"symbol", policy, "Xk7qqmzp", "", []string{RuleSymbol}},
+		{"email", policy, "Xk7!Ronaldo", "ronaldo@spfc.com", []string{RuleEmail}},
+		{"short_email_local_part", policy, "Xk7!qmzp", "qm@spfc.com", nil},
+		{"breached", policy, "pASSWORD1!", "", []string{RuleBreached}},
+		{"strength", strict, "aaaaaaa1!", "", []string{RuleStrength}},
+		{"strong_enough", strict, "x7kq2mzp9fw", "", nil},
+		{"every_rule", policy, "abc", "", []string{RuleMinLength, RuleUpper, RuleDigit, RuleSymbol}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password, tt.email)
+			if tt.rules == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrPolicyViolated)
+			objects, ok := ErrorObjects(err, "password")
+			require.True(t, ok)
+
+			rules := make([]string, 0, len(objects))
+			for _, o := range objects {
+				require.Equal(t, "password", o.Title)
+				rules = append(rules, *o.Code)
+			}
+			require.Equal(t, tt.rules, rules)
+		})
+	}
+}
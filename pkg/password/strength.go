@@ -0,0 +1,130 @@
+package password
+
+import (
+	"math"
+	"strings"
+)
+
+// bruteforceCardinality is the number of guesses per character that is not part of a pattern,
+// as estimated by zxcvbn.
+const bruteforceCardinality = 10
+
+// keyboardRows are walked by passwords such as "qwerty" or "asdf".
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+// Score estimates how hard password is to guess, in the spirit of zxcvbn:
+// the password is split into patterns that attackers try first (repeated characters,
+// sequences, keyboard walks and words of the dictionary) and the remaining characters
+// are counted as brute force. The estimated number of guesses is mapped to a score
+// from 0 (too guessable) to 4 (very unguessable), using the zxcvbn thresholds.
+func Score(password string, dictionary map[string]struct{}) int {
+	guesses := Guesses(password, dictionary)
+	switch log := math.Log10(guesses); {
+	case log < 3:
+		return 0
+	case log < 6:
+		return 1
+	case log < 8:
+		return 2
+	case log < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Guesses estimates the number of guesses needed to find password.
+func Guesses(password string, dictionary map[string]struct{}) float64 {
+	runes := []rune(strings.ToLower(password))
+
+	guesses := 1.0
+	patterns := 0
+	for i := 0; i < len(runes); {
+		n, g := longestPattern(runes[i:], dictionary)
+		if n > 1 {
+			guesses *= g
+			patterns++
+			i += n
+			continue
+		}
+		guesses *= bruteforceCardinality
+		i++
+	}
+
+	// Attackers also have to guess how patterns are combined
+	for i := 2; i <= patterns; i++ {
+		guesses *= float64(i)
+	}
+	return guesses
+}
+
+// longestPattern finds the longest guessable pattern at the start of runes,
+// returning its length and the number of guesses it takes.
+func longestPattern(runes []rune, dictionary map[string]struct{}) (int, float64) {
+	best, guesses := 1, 0.0
+	consider := func(n int, g float64) {
+		if n > best || (n == best && g < guesses) {
+			best, guesses = n, g
+		}
+	}
+
+	// Repeated characters, such as "aaaa"
+	n := 1
+	for n < len(runes) && runes[n] == runes[0] {
+		n++
+	}
+	if n >= 3 {
+		consider(n, bruteforceCardinality*float64(n))
+	}
+
+	// Sequences, such as "abcd" or "4321"
+	if len(runes) >= 3 {
+		step := runes[1] - runes[0]
+		if step == 1 || step == -1 {
+			n := 2
+			for n < len(runes) && runes[n]-runes[n-1] == step {
+				n++
+			}
+			if n >= 3 {
+				consider(n, 2*26*float64(n))
+			}
+		}
+	}
+
+	// Keyboard walks, such as "qwerty"
+	for _, row := range keyboardRows {
+		start := strings.IndexRune(row, runes[0])
+		if start < 0 {
+			continue
+		}
+		n := 1
+		for n < len(runes) && start+n < len(row) && rune(row[start+n]) == runes[n] {
+			n++
+		}
+		if n >= 4 {
+			consider(n, 4*47*float64(n))
+		}
+	}
+
+	// Dictionary words, undoing common substitutions such as "p4ssw0rd"
+	for n := len(runes); n >= 4 && n > best; n-- {
+		word := unleet(string(runes[:n]))
+		if _, ok := dictionary[word]; ok {
+			consider(n, float64(len(dictionary)))
+			break
+		}
+	}
+
+	return best, guesses
+}
+
+var leet = strings.NewReplacer("4", "a", "@", "a", "3", "e", "1", "i", "!", "i", "0", "o", "5", "s", "$", "s", "7", "t")
+
+func unleet(word string) string {
+	return leet.Replace(word)
+}
@@ -0,0 +1,56 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuesses(t *testing.T) {
+	dictionary := map[string]struct{}{"password": {}, "monkey": {}}
+
+	tests := []struct {
+		name     string
+		password string
+		guesses  float64
+	}{
+		{"empty", "", 1},
+		{"bruteforce", "x7k", 1000},
+		{"repeat", "aaaaaa", 60},
+		{"short_repeat", "aa", 100},
+		{"sequence", "abcdef", 312},
+		{"descending_sequence", "4321", 208},
+		{"keyboard_walk", "qwerty", 1128},
+		{"dictionary", "password", 2},
+		{"leet_dictionary", "p4ssw0rd", 2},
+		{"case_insensitive", "MONKEY", 2},
+		{"combined_patterns", "aaaqwer", 30 * 752 * 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.guesses, Guesses(tt.password, dictionary))
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		score    int
+	}{
+		{"too_guessable", "x7", 0},
+		{"very_guessable", "x7kq", 1},
+		{"somewhat_guessable", "x7kq2mz", 2},
+		{"safely_unguessable", "x7kq2mzp9", 3},
+		{"very_unguessable", "x7kq2mzp9fw", 4},
+		{"long_repeat", "aaaaaaaaaaaa", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.score, Score(tt.password, nil))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package realip resolves the address of the client behind trusted reverse proxies.
+package realip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Resolver trusts the X-Forwarded-For header only when the request
+// comes from one of the configured proxies.
+type Resolver struct {
+	trusted []netip.Prefix
+}
+
+// New parses proxies given as CIDR ranges ("10.0.0.0/8") or single addresses ("10.0.0.1").
+func New(proxies []string) (*Resolver, error) {
+	r := &Resolver{}
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("realip: invalid trusted proxy %q: %w", proxy, err)
+			}
+			r.trusted = append(r.trusted, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("realip: invalid trusted proxy %q: %w", proxy, err)
+		}
+		r.trusted = append(r.trusted, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return r, nil
+}
+
+// ClientIP returns the address of the client that sent the request.
+// Forwarded addresses are read from right to left, skipping trusted proxies,
+// so that a client cannot spoof its address by sending the header itself.
+func (r *Resolver) ClientIP(req *http.Request) string {
+	peer := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	addr, err := netip.ParseAddr(peer)
+	if err != nil || !r.isTrusted(addr) {
+		return peer
+	}
+
+	var hops []string
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = hop.Unmap()
+		if !r.isTrusted(client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// Middleware replaces the RemoteAddr of the request with the address of the client.
+func (r *Resolver) Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		req.RemoteAddr = r.ClientIP(req)
+		next.ServeHTTP(w, req)
+	}
+	return http.HandlerFunc(fn)
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package realip
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New([]string{"10.0.0.0/8", " 192.168.1.1 ", "", "::1"})
+	require.NoError(t, err)
+
+	_, err = New([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+
+	_, err = New([]string{"proxy.local"})
+	require.Error(t, err)
+}
+
+func TestClientIP(t *testing.T) {
+	resolver, err := New([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded []string
+		want      string
+	}{
+		{"no_proxy", "203.0.113.7:4321", nil, "203.0.113.7"},
+		{"untrusted_peer_spoofing", "203.0.113.7:4321", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted_peer_without_header", "10.0.0.1:4321", nil, "10.0.0.1"},
+		{"trusted_peer", "10.0.0.1:4321", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"trusted_single_address", "192.168.1.1:4321", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"untrusted_single_address", "192.168.1.2:4321", []string{"203.0.113.7"}, "192.168.1.2"},
+		{"chain_of_proxies", "10.0.0.1:4321", []string{"198.51.100.1, 203.0.113.7, 10.0.0.2"}, "203.0.113.7"},
+		{"spoofed_leftmost_hop", "10.0.0.1:4321", []string{"1.1.1.1, 203.0.113.7"}, "203.0.113.7"},
+		{"multiple_headers", "10.0.0.1:4321", []string{"198.51.100.1", "203.0.113.7, 10.0.0.2"}, "203.0.113.7"},
+		{"every_hop_trusted", "10.0.0.1:4321", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"invalid_hop", "10.0.0.1:4321", []string{"203.0.113.7, not-an-ip"}, "10.0.0.1"},
+		{"invalid_hop_behind_client", "10.0.0.1:4321", []string{"not-an-ip, 203.0.113.7"}, "203.0.113.7"},
+		{"mapped_ipv6_peer", "[::ffff:10.0.0.1]:4321", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"mapped_ipv6_hop", "10.0.0.1:4321", []string{"::ffff:203.0.113.7"}, "203.0.113.7"},
+		{"peer_without_port", "203.0.113.7", nil, "203.0.113.7"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = test.peer
+			for _, value := range test.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			require.Equal(t, test.want, resolver.ClientIP(r))
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminUsers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	post := func(path, body string) int {
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("admin: users: failed to create request: %v\n", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("admin: users: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	login := func(email, password string) (int, tokenResponse) {
+		return exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {email},
+			"password":   {password},
+		})
+	}
+
+	for _, email := range []string{"rai@spfc.com", "pintado@spfc.com", "pintado.filho@spfc.com"} {
+		status := post("register", fmt.Sprintf(`{"email": %q, "password": "password"}`, email))
+		require.Equal(t, http.StatusCreated, status)
+	}
+
+	// The admin claim is only granted once the configured address is verified
+	status, unverified := login("rai@spfc.com", "password")
+	require.Equal(t, http.StatusOK, status)
+
+	code := readMail(ctx, t, "rai@spfc.com", regexp.MustCompile(`code is ([0-9]{6})`))
+	status = post("verify-email", fmt.Sprintf(`{"email": "rai@spfc.com", "code": %q}`, code))
+	require.Equal(t, http.StatusOK, status)
+
+	status, admin := login("rai@spfc.com", "password")
+	require.Equal(t, http.StatusOK, status)
+
+	type found struct {
+		ID            string  `json:"id"`
+		Email         string  `json:"email"`
+		EmailVerified bool    `json:"email_verified"`
+		DisabledAt    *string `json:"disabled_at"`
+		DeletedAt     *string `json:"deleted_at"`
+	}
+
+	type page struct {
+		Data       []found `json:"data"`
+		NextCursor *string `json:"next_cursor"`
+	}
+
+	send := func(method, path string, token string, v any) int {
+		route := fmt.Sprintf("http://%s:%s/admin/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, method, route, nil)
+		if err != nil {
+			t.Fatalf("admin: users: failed to create request: %v\n", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("admin: users: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		if v != nil {
+			json.NewDecoder(resp.Body).Decode(v)
+		}
+		return resp.StatusCode
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		status := send(http.MethodGet, "users", "", nil)
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		status = send(http.MethodGet, "users", unverified.AccessToken, nil)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	// Pages are linked by cursors, which keep the filters and sort of the first request
+	var pintado found
+	t.Run("list", func(t *testing.T) {
+		var first page
+		status := send(http.MethodGet, "users?email=PINTADO&sort=-email&limit=1", admin.AccessToken, &first)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, first.Data, 1)
+		require.Equal(t, "pintado@spfc.com", first.Data[0].Email)
+		require.NotNil(t, first.NextCursor)
+		pintado = first.Data[0]
+
+		var second page
+		status = send(http.MethodGet, "users?email=PINTADO&sort=-email&limit=1&cursor="+url.QueryEscape(*first.NextCursor), admin.AccessToken, &second)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, second.Data, 1)
+		require.Equal(t, "pintado.filho@spfc.com", second.Data[0].Email)
+		require.Nil(t, second.NextCursor)
+
+		status = send(http.MethodGet, "users?email=PINTADO&sort=email&limit=1&cursor="+url.QueryEscape(*first.NextCursor), admin.AccessToken, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		var verified page
+		status = send(http.MethodGet, "users?email=spfc.com&verified=true&limit=100", admin.AccessToken, &verified)
+		require.Equal(t, http.StatusOK, status)
+		for _, u := range verified.Data {
+			require.True(t, u.EmailVerified)
+		}
+
+		status = send(http.MethodGet, "users?limit=1000", admin.AccessToken, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status = send(http.MethodGet, "users?created_after=yesterday", admin.AccessToken, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// Disabled users are signed out and cannot sign in until enabled again
+	t.Run("disable", func(t *testing.T) {
+		status, tokens := login("pintado@spfc.com", "password")
+		require.Equal(t, http.StatusOK, status)
+
+		status = send(http.MethodPost, "users/"+pintado.ID+"/disable", admin.AccessToken, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		var disabled struct {
+			Data found `json:"data"`
+		}
+		status = send(http.MethodGet, "users/"+pintado.ID, admin.AccessToken, &disabled)
+		require.Equal(t, http.StatusOK, status)
+		require.NotNil(t, disabled.Data.DisabledAt)
+
+		status, refused := login("pintado@spfc.com", "password")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", refused.Error)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status = send(http.MethodPost, "users/"+pintado.ID+"/enable", admin.AccessToken, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status, _ = login("pintado@spfc.com", "password")
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("verify_email", func(t *testing.T) {
+		status := send(http.MethodPost, "users/"+pintado.ID+"/verify-email", admin.AccessToken, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		var verified struct {
+			Data found `json:"data"`
+		}
+		status = send(http.MethodGet, "users/"+pintado.ID, admin.AccessToken, &verified)
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, verified.Data.EmailVerified)
+	})
+
+	// The password stops working until a new one is chosen with the emailed token
+	t.Run("reset_password", func(t *testing.T) {
+		status := send(http.MethodPost, "users/"+pintado.ID+"/reset-password", admin.AccessToken, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status, _ = login("pintado@spfc.com", "password")
+		require.Equal(t, http.StatusBadRequest, status)
+
+		token := readMail(ctx, t, "pintado@spfc.com", regexp.MustCompile(`new password: ([A-Za-z0-9_-]+)`))
+		status = post("password/reset", fmt.Sprintf(`{"token": %q, "password": "new_password"}`, token))
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = login("pintado@spfc.com", "new_password")
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	// Deleted users are still found by admins, until they are deleted permanently
+	t.Run("delete", func(t *testing.T) {
+		status := send(http.MethodDelete, "users/"+pintado.ID, admin.AccessToken, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		var deleted page
+		status = send(http.MethodGet, "users?email=pintado&deleted=only", admin.AccessToken, &deleted)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, deleted.Data, 1)
+		require.Equal(t, pintado.ID, deleted.Data[0].ID)
+		require.NotNil(t, deleted.Data[0].DeletedAt)
+
+		var remaining page
+		status = send(http.MethodGet, "users?email=pintado", admin.AccessToken, &remaining)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, remaining.Data, 1)
+		require.Equal(t, "pintado.filho@spfc.com", remaining.Data[0].Email)
+
+		status = send(http.MethodDelete, "users/"+pintado.ID+"?permanent=true", admin.AccessToken, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status = send(http.MethodGet, "users/"+pintado.ID, admin.AccessToken, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+}
+
+func TestAdminRoles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	send := func(method, path, token, body string, v any) int {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("http://%s:%s/%s", env.host, env.port, path), reader)
+		if err != nil {
+			t.Fatalf("admin: roles: failed to create request: %v\n", err)
+		}
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("admin: roles: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		if v != nil {
+			json.NewDecoder(resp.Body).Decode(v)
+		}
+		return resp.StatusCode
+	}
+
+	login := func(email string) string {
+		status, tokens := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {email},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		return tokens.AccessToken
+	}
+
+	claims := func(token string) map[string]any {
+		parts := strings.Split(token, ".")
+		require.Len(t, parts, 3)
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		return claims
+	}
+
+	for _, email := range []string{"muller@spfc.com", "careca@spfc.com"} {
+		status := send(http.MethodPost, "auth/register", "", fmt.Sprintf(`{"email": %q, "password": "password"}`, email), nil)
+		require.Equal(t, http.StatusCreated, status)
+	}
+
+	code := readMail(ctx, t, "muller@spfc.com", regexp.MustCompile(`code is ([0-9]{6})`))
+	status := send(http.MethodPost, "auth/verify-email", "", fmt.Sprintf(`{"email": "muller@spfc.com", "code": %q}`, code), nil)
+	require.Equal(t, http.StatusOK, status)
+	admin := login("muller@spfc.com")
+
+	type role struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Permissions []string `json:"permissions"`
+	}
+
+	var careca struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	status = send(http.MethodGet, "admin/users?email=careca@spfc.com", admin, "", &careca)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, careca.Data, 1)
+	carecaID := careca.Data[0].ID
+
+	t.Run("permissions", func(t *testing.T) {
+		status := send(http.MethodPost, "admin/permissions", admin, `{"name": "reports:read", "description": "Read reports"}`, nil)
+		require.Equal(t, http.StatusCreated, status)
+
+		status = send(http.MethodPost, "admin/permissions", admin, `{"name": "reports:read"}`, nil)
+		require.Equal(t, http.StatusConflict, status)
+
+		status = send(http.MethodPost, "admin/permissions", admin, `{"name": "Reports"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		// Built-in permissions are gating the admin API itself
+		status = send(http.MethodDelete, "admin/permissions/users:read", admin, "", nil)
+		require.Equal(t, http.StatusConflict, status)
+	})
+
+	var support role
+	t.Run("create_role", func(t *testing.T) {
+		var created struct {
+			Data role `json:"data"`
+		}
+		status := send(http.MethodPost, "admin/roles", admin, `{"name": "support", "permissions": ["users:read", "reports:read", "users:read"]}`, &created)
+		require.Equal(t, http.StatusCreated, status)
+		require.Equal(t, []string{"reports:read", "users:read"}, created.Data.Permissions)
+		support = created.Data
+
+		status = send(http.MethodPost, "admin/roles", admin, `{"name": "support"}`, nil)
+		require.Equal(t, http.StatusConflict, status)
+
+		status = send(http.MethodPost, "admin/roles", admin, `{"name": "auditor", "permissions": ["audit:read"]}`, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// Roles reach access tokens issued after they are assigned, and their permissions gate the admin API
+	t.Run("assign", func(t *testing.T) {
+		status := send(http.MethodPut, "admin/users/"+carecaID+"/roles/"+support.ID, admin, "", nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		var roles struct {
+			Data []role `json:"data"`
+		}
+		status = send(http.MethodGet, "admin/users/"+carecaID+"/roles", admin, "", &roles)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, roles.Data, 1)
+		require.Equal(t, "support", roles.Data[0].Name)
+
+		token := login("careca@spfc.com")
+		c := claims(token)
+		require.Equal(t, []any{"support"}, c["roles"])
+		require.Equal(t, []any{"reports:read", "users:read"}, c["permissions"])
+		require.Nil(t, c["admin"])
+
+		status = send(http.MethodGet, "admin/users", token, "", nil)
+		require.Equal(t, http.StatusOK, status)
+
+		status = send(http.MethodDelete, "admin/users/"+carecaID, token, "", nil)
+		require.Equal(t, http.StatusForbidden, status)
+
+		status = send(http.MethodGet, "admin/roles", token, "", nil)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("set_permissions", func(t *testing.T) {
+		var updated struct {
+			Data role `json:"data"`
+		}
+		status := send(http.MethodPut, "admin/roles/"+support.ID+"/permissions", admin, `{"permissions": ["users:read", "roles:read"]}`, &updated)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{"roles:read", "users:read"}, updated.Data.Permissions)
+
+		status = send(http.MethodGet, "admin/roles", login("careca@spfc.com"), "", nil)
+		require.Equal(t, http.StatusOK, status)
+
+		status = send(http.MethodDelete, "admin/permissions/reports:read", admin, "", nil)
+		require.Equal(t, http.StatusNoContent, status)
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		status := send(http.MethodDelete, "admin/users/"+carecaID+"/roles/"+support.ID, admin, "", nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		token := login("careca@spfc.com")
+		require.Nil(t, claims(token)["roles"])
+
+		status = send(http.MethodGet, "admin/users", token, "", nil)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("delete_role", func(t *testing.T) {
+		status := send(http.MethodDelete, "admin/roles/"+support.ID, admin, "", nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status = send(http.MethodGet, "admin/roles/"+support.ID, admin, "", nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+}
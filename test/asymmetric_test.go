@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAsymmetricSigning runs against the server signing with ES256,
+// whose tokens resource servers verify with the published keys alone.
+func TestAsymmetricSigning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	symmetric, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+	asymmetric := &env{host: symmetric.host, port: asymmetricPort}
+	base := fmt.Sprintf("http://%s:%s", asymmetric.host, asymmetric.port)
+
+	get := func(route, token string) (int, []byte) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+route, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	var configuration struct {
+		SigningAlgorithms []string `json:"id_token_signing_alg_values_supported"`
+	}
+	status, body := get("/auth/.well-known/openid-configuration", "")
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(body, &configuration))
+	require.Equal(t, []string{"ES256"}, configuration.SigningAlgorithms)
+
+	// The bootstrapped key is published without its private part
+	status, body = get("/auth/.well-known/jwks.json", "")
+	require.Equal(t, http.StatusOK, status)
+	keySet, err := jwk.Parse(body)
+	require.NoError(t, err)
+	require.Equal(t, 1, keySet.Len())
+	published, _ := keySet.Key(0)
+	kid, ok := published.KeyID()
+	require.True(t, ok)
+	require.NotEmpty(t, kid)
+	isPrivate, err := jwk.IsPrivateKey(published)
+	require.NoError(t, err)
+	require.False(t, isPrivate)
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {"must_not_touch@email.com"},
+		"password":   {"password"},
+	}
+	status, tokens := exchangeToken(ctx, t, asymmetric, form)
+	require.Equal(t, http.StatusOK, status)
+
+	t.Run("verify_with_jwks", func(t *testing.T) {
+		msg, err := jws.Parse([]byte(tokens.AccessToken))
+		require.NoError(t, err)
+		header := msg.Signatures()[0].ProtectedHeaders()
+		alg, _ := header.Algorithm()
+		require.Equal(t, "ES256", alg.String())
+		signedBy, _ := header.KeyID()
+		require.Equal(t, kid, signedBy)
+
+		_, err = jwt.Parse([]byte(tokens.AccessToken), jwt.WithKeySet(keySet))
+		require.NoError(t, err)
+	})
+
+	t.Run("guard", func(t *testing.T) {
+		status, _ := get("/users/me", tokens.AccessToken)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	// Tokens signed with the secret of the other server are rejected
+	t.Run("foreign_token", func(t *testing.T) {
+		status, foreign := exchangeToken(ctx, t, symmetric, form)
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = get("/users/me", foreign.AccessToken)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+}
@@ -2,12 +2,16 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/pquerna/otp/totp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,6 +50,39 @@ func TestAuthRegisterUser(t *testing.T) {
 		require.Equal(t, http.StatusConflict, resp.StatusCode)
 	})
 
+	// Passwords failing the policy should return 400 Bad Request listing every failed rule
+	t.Run("weak_password", func(t *testing.T) {
+		body := strings.NewReader(`{"email": "cafu@spfc.com", "password": "cafu"}`)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, body)
+		if err != nil {
+			t.Errorf("user: register_user: failed to create request: %v\n", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("user: register_user: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var errs struct {
+			Errors []struct {
+				Code  string `json:"code"`
+				Title string `json:"title"`
+			} `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errs)
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		codes := make([]string, 0, len(errs.Errors))
+		for _, e := range errs.Errors {
+			require.Equal(t, "password", e.Title)
+			codes = append(codes, e.Code)
+		}
+		require.ElementsMatch(t, []string{"min_length", "email"}, codes)
+	})
+
 	// User successful registration should return 201 Created
 	t.Run("user_registered", func(t *testing.T) {
 		body := strings.NewReader(`
@@ -105,6 +142,10 @@ func TestAuthRequestAccessToken(t *testing.T) {
 		defer resp.Body.Close()
 
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		var failed tokenResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&failed))
+		require.Equal(t, "unsupported_grant_type", failed.Error)
 	})
 
 	// Invalid credentials should return 400 Bad Request
@@ -156,4 +197,947 @@ func TestAuthRequestAccessToken(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
+
+	// Parameters of the media type, such as a charset, are accepted
+	t.Run("form_charset", func(t *testing.T) {
+		formData := url.Values{}
+		formData.Set("grant_type", "password")
+		formData.Set("username", "must_not_touch@email.com")
+		formData.Set("password", "password")
+		body := strings.NewReader(formData.Encode())
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	// Refresh tokens are rotated on use and reusing a rotated one revokes its family
+	t.Run("refresh_token_rotation", func(t *testing.T) {
+		exchange := func(form url.Values) (int, tokenResponse) {
+			return exchangeToken(ctx, t, env, form)
+		}
+
+		status, first := exchange(url.Values{
+			"grant_type": {"password"},
+			"username":   {"must_not_touch@email.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, first.RefreshToken)
+
+		status, second := exchange(url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {first.RefreshToken},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+		// Reusing the rotated token is rejected
+		status, _ = exchange(url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {first.RefreshToken},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		// And the whole family is revoked, including the latest token
+		status, _ = exchange(url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {second.RefreshToken},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestAuthListPublicKeys(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	list := func(port string) []map[string]any {
+		route := fmt.Sprintf("http://%s:%s/auth/.well-known/jwks.json", env.host, port)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, route, nil)
+		if err != nil {
+			t.Errorf("auth: list_public_keys: failed to create request: %v\n", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("auth: list_public_keys: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var body struct {
+			Keys []map[string]any `json:"keys"`
+		}
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body.Keys
+	}
+
+	// The key set is public and never exposes HMAC secrets
+	t.Run("jwks_symmetric", func(t *testing.T) {
+		require.Empty(t, list(env.port))
+	})
+
+	// Nor the private part of asymmetric keys
+	t.Run("jwks_asymmetric", func(t *testing.T) {
+		keys := list(asymmetricPort)
+		require.NotEmpty(t, keys)
+		for _, key := range keys {
+			require.Equal(t, "EC", key["kty"])
+			require.Equal(t, "ES256", key["alg"])
+			require.Equal(t, "sig", key["use"])
+			require.NotEmpty(t, key["kid"])
+			require.NotContains(t, key, "d")
+		}
+	})
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	// Error code of failed requests (RFC 6749 section 5.2)
+	Error string `json:"error"`
+}
+
+func exchangeToken(ctx context.Context, t *testing.T, env *env, form url.Values) (int, tokenResponse) {
+	t.Helper()
+
+	route := fmt.Sprintf("http://%s:%s/auth/oauth/token", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("auth: request_access_token: failed to create request: %v\n", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("auth: request_access_token: request failed: %v\n", err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+// operatorToken returns an access token of the operator, whose address is listed by auth.admin.users
+// in the test configuration and is therefore granted every permission. It is registered on first use.
+func operatorToken(ctx context.Context, t *testing.T, env *env) string {
+	t.Helper()
+
+	const email = "operator@spfc.com"
+
+	post := func(path, body string) int {
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("auth: %s: failed to create request: %v\n", path, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("auth: %s: request failed: %v\n", path, err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The admin claim is only granted once the address is verified
+	if post("register", fmt.Sprintf(`{"email": %q, "password": "password"}`, email)) == http.StatusCreated {
+		code := readMail(ctx, t, email, regexp.MustCompile(`code is ([0-9]{6})`))
+		require.Equal(t, http.StatusOK, post("verify-email", fmt.Sprintf(`{"email": %q, "code": %q}`, email, code)))
+	}
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {email},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+	return tokens.AccessToken
+}
+
+// registerClient registers a client as the operator and returns its credentials.
+func registerClient(ctx context.Context, t *testing.T, env *env, body string) (string, string) {
+	route := fmt.Sprintf("http://%s:%s/clients", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("client: create: failed to create request: %v\n", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+operatorToken(ctx, t, env))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("client: create: request failed: %v\n", err)
+	}
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var registration struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&registration))
+	return registration.ClientID, registration.ClientSecret
+}
+
+func TestAuthRevokeToken(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	route := fmt.Sprintf("http://%s:%s/auth/oauth/revoke", env.host, env.port)
+	client := &http.Client{}
+
+	revoke := func(form url.Values) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("auth: revoke_token: failed to create request: %v\n", err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: revoke_token: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	// Missing token should return 400 Bad Request
+	t.Run("missing_token", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, revoke(url.Values{}))
+	})
+
+	// Unknown tokens are not an error
+	t.Run("unknown_token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, revoke(url.Values{"token": {"unknown"}}))
+	})
+
+	// A revoked access token is rejected by private routes
+	t.Run("access_token", func(t *testing.T) {
+		status, tokens := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"must_not_touch@email.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		deleteUser := func() int {
+			url := fmt.Sprintf("http://%s:%s/users/1aef49bd-3296-45fb-84b9-083cf81b0e44/delete", env.host, env.port)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+			if err != nil {
+				t.Fatalf("user: hard_delete_by_id: failed to create request: %v\n", err)
+			}
+
+			req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("user: hard_delete_by_id: request failed: %v\n", err)
+			}
+			defer resp.Body.Close()
+
+			return resp.StatusCode
+		}
+
+		// Without a body the request is authenticated but invalid
+		require.Equal(t, http.StatusBadRequest, deleteUser())
+
+		require.Equal(t, http.StatusOK, revoke(url.Values{
+			"token":           {tokens.AccessToken},
+			"token_type_hint": {"access_token"},
+		}))
+		require.Equal(t, http.StatusUnauthorized, deleteUser())
+	})
+
+	// A revoked refresh token cannot be exchanged anymore
+	t.Run("refresh_token", func(t *testing.T) {
+		status, tokens := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"must_not_touch@email.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		require.Equal(t, http.StatusOK, revoke(url.Values{"token": {tokens.RefreshToken}}))
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestAuthIntrospectToken(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	route := fmt.Sprintf("http://%s:%s/auth/oauth/introspect", env.host, env.port)
+	client := &http.Client{}
+
+	type introspection struct {
+		Active bool   `json:"active"`
+		Sub    string `json:"sub"`
+	}
+
+	introspect := func(form url.Values, clientID, clientSecret string) (int, introspection) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("auth: introspect_token: failed to create request: %v\n", err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if clientID != "" {
+			req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: introspect_token: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var body introspection
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"must_not_touch@email.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	// Resource servers are clients granted the introspect scope
+	gateway, gatewaySecret := registerClient(ctx, t, env, `{"name": "gateway", "grant_types": ["client_credentials"], "scopes": ["introspect"]}`)
+	billing, billingSecret := registerClient(ctx, t, env, `{"name": "billing", "grant_types": ["client_credentials"]}`)
+
+	// Callers must authenticate as a client
+	t.Run("unauthenticated", func(t *testing.T) {
+		status, _ := introspect(url.Values{"token": {tokens.AccessToken}}, "", "")
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		status, _ = introspect(url.Values{"token": {tokens.AccessToken}}, gateway, "wrong_secret")
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	// Other clients are not allowed to introspect tokens
+	t.Run("unauthorized", func(t *testing.T) {
+		status, _ := introspect(url.Values{"token": {tokens.AccessToken}}, billing, billingSecret)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	// Unknown tokens are inactive
+	t.Run("inactive", func(t *testing.T) {
+		status, body := introspect(url.Values{"token": {"unknown"}}, gateway, gatewaySecret)
+		require.Equal(t, http.StatusOK, status)
+		require.False(t, body.Active)
+	})
+
+	// Issued tokens are active and describe their subject
+	t.Run("active", func(t *testing.T) {
+		status, body := introspect(url.Values{"token": {tokens.AccessToken}}, gateway, gatewaySecret)
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, body.Active)
+		require.Equal(t, "1aef49bd-3296-45fb-84b9-083cf81b0e44", body.Sub)
+
+		status, body = introspect(url.Values{
+			"token":           {tokens.RefreshToken},
+			"token_type_hint": {"refresh_token"},
+		}, gateway, gatewaySecret)
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, body.Active)
+	})
+}
+
+func TestAuthVerifyEmail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	post := func(path, body string) int {
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("auth: verify_email: failed to create request: %v\n", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: verify_email: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	status := post("register", `{"email": "kaka@spfc.com", "password": "password"}`)
+	require.Equal(t, http.StatusCreated, status)
+
+	// A malformed code should return 400 Bad Request
+	t.Run("malformed_code", func(t *testing.T) {
+		status := post("verify-email", `{"email": "kaka@spfc.com", "code": "abc"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// Unknown emails are answered like wrong codes
+	t.Run("unknown_email", func(t *testing.T) {
+		status := post("verify-email", `{"email": "nobody@spfc.com", "code": "000000"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// Resending is always accepted, even within the cooldown
+	t.Run("resend", func(t *testing.T) {
+		status := post("verify-email/resend", `{"email": "kaka@spfc.com"}`)
+		require.Equal(t, http.StatusAccepted, status)
+
+		status = post("verify-email/resend", `{"email": "nobody@spfc.com"}`)
+		require.Equal(t, http.StatusAccepted, status)
+	})
+
+	// Wrong codes are rejected until the attempts are exhausted
+	t.Run("too_many_attempts", func(t *testing.T) {
+		// Codes are random, so a single guess may be right by chance
+		// but five wrong guesses in a row are 1 in 200000
+		codes := []string{"000001", "000002", "000003", "000004", "000005"}
+		for _, code := range codes {
+			status := post("verify-email", fmt.Sprintf(`{"email": "kaka@spfc.com", "code": %q}`, code))
+			if status == http.StatusOK {
+				t.Skip("guessed the verification code")
+			}
+			require.Equal(t, http.StatusBadRequest, status)
+		}
+
+		status := post("verify-email", `{"email": "kaka@spfc.com", "code": "000006"}`)
+		require.Equal(t, http.StatusTooManyRequests, status)
+	})
+}
+
+// readMail polls the MailHog API until an email sent to address matches pattern,
+// and returns the first submatch of pattern.
+func readMail(ctx context.Context, t *testing.T, address string, pattern *regexp.Regexp) string {
+	t.Helper()
+
+	type search struct {
+		Items []struct {
+			Content struct {
+				Body string `json:"Body"`
+			} `json:"Content"`
+		} `json:"items"`
+	}
+
+	route := fmt.Sprintf("http://localhost:8025/api/v2/search?kind=to&query=%s", url.QueryEscape(address))
+
+	for range 20 {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, route, nil)
+		if err != nil {
+			t.Fatalf("mailhog: failed to create request: %v\n", err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("mailhog: request failed: %v\n", err)
+		}
+
+		var body search
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+
+		// Most recent messages come first
+		for _, item := range body.Items {
+			// Bodies are quoted-printable, whose soft line breaks may split the match
+			content := strings.ReplaceAll(item.Content.Body, "=\r\n", "")
+			if match := pattern.FindStringSubmatch(content); match != nil {
+				return match[1]
+			}
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	t.Fatalf("mailhog: no email matching %q was delivered to %q\n", pattern, address)
+	return ""
+}
+
+func TestAuthVerifyEmailDelivered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	post := func(path, body string) int {
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("auth: verify_email: failed to create request: %v\n", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: verify_email: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	status := post("register", `{"email": "lugano@spfc.com", "password": "password"}`)
+	require.Equal(t, http.StatusCreated, status)
+
+	// The code delivered through the outbox verifies the email address
+	code := readMail(ctx, t, "lugano@spfc.com", regexp.MustCompile(`code is ([0-9]{6})`))
+	status = post("verify-email", fmt.Sprintf(`{"email": "lugano@spfc.com", "code": %q}`, code))
+	require.Equal(t, http.StatusOK, status)
+
+	// Verifying twice is answered like a wrong code
+	status = post("verify-email", fmt.Sprintf(`{"email": "lugano@spfc.com", "code": %q}`, code))
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAuthResetPassword(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	post := func(path, body string) int {
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("auth: reset_password: failed to create request: %v\n", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: reset_password: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	status := post("register", `{"email": "mineiro@spfc.com", "password": "password"}`)
+	require.Equal(t, http.StatusCreated, status)
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"mineiro@spfc.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	// Known and unknown emails are answered the same way
+	t.Run("forgot", func(t *testing.T) {
+		status := post("password/forgot", `{"email": "nobody@spfc.com"}`)
+		require.Equal(t, http.StatusAccepted, status)
+
+		status = post("password/forgot", `{"email": "mineiro@spfc.com"}`)
+		require.Equal(t, http.StatusAccepted, status)
+	})
+
+	// An unknown token should return 400 Bad Request
+	t.Run("invalid_token", func(t *testing.T) {
+		status := post("password/reset", `{"token": "unknown", "password": "new_password"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// The emailed token sets the new password only once and signs the user out
+	t.Run("reset", func(t *testing.T) {
+		token := readMail(ctx, t, "mineiro@spfc.com", regexp.MustCompile(`new password: ([A-Za-z0-9_-]+)`))
+
+		status := post("password/reset", fmt.Sprintf(`{"token": %q, "password": "new_password"}`, token))
+		require.Equal(t, http.StatusOK, status)
+
+		status = post("password/reset", fmt.Sprintf(`{"token": %q, "password": "other_password"}`, token))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"mineiro@spfc.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"mineiro@spfc.com"},
+			"password":   {"new_password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestAuthLockout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	unlock := func(accessToken, body string) int {
+		route := fmt.Sprintf("http://%s:%s/auth/lockout/unlock", env.host, env.port)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("auth: unlock_login: failed to create request: %v\n", err)
+		}
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: unlock_login: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	login := func(password string) int {
+		status, _ := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"casares@spfc.com"},
+			"password":   {password},
+		})
+		return status
+	}
+
+	route := fmt.Sprintf("http://%s:%s/auth/register", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(`{"email": "casares@spfc.com", "password": "password"}`))
+	if err != nil {
+		t.Fatalf("auth: register_user: failed to create request: %v\n", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("auth: register_user: request failed: %v\n", err)
+	}
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"casares@spfc.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	// A successful login clears the failures counted before it
+	t.Run("reset_on_success", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, login("wrong_password"))
+		require.Equal(t, http.StatusBadRequest, login("wrong_password"))
+		require.Equal(t, http.StatusOK, login("password"))
+		require.Equal(t, http.StatusBadRequest, login("wrong_password"))
+		require.Equal(t, http.StatusBadRequest, login("wrong_password"))
+		require.Equal(t, http.StatusOK, login("password"))
+	})
+
+	// Reaching the threshold locks the account, even for the right password
+	t.Run("locked", func(t *testing.T) {
+		for range 3 {
+			require.Equal(t, http.StatusBadRequest, login("wrong_password"))
+		}
+
+		status, _ := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"CASARES@spfc.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusTooManyRequests, status)
+	})
+
+	// Only operators granted the logins:unlock permission can unlock an account
+	t.Run("unlock", func(t *testing.T) {
+		status := unlock("", `{"email": "casares@spfc.com"}`)
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		status = unlock(tokens.AccessToken, `{"email": "casares@spfc.com"}`)
+		require.Equal(t, http.StatusForbidden, status)
+
+		operator := operatorToken(ctx, t, env)
+		status = unlock(operator, `{}`)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status = unlock(operator, `{"email": "casares@spfc.com"}`)
+		require.Equal(t, http.StatusOK, status)
+
+		require.Equal(t, http.StatusOK, login("password"))
+	})
+}
+
+func TestAuthMFA(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	post := func(path, accessToken, body string, v any) int {
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("auth: %s: failed to create request: %v\n", path, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: %s: request failed: %v\n", path, err)
+		}
+		defer resp.Body.Close()
+		if v != nil {
+			json.NewDecoder(resp.Body).Decode(v)
+		}
+		return resp.StatusCode
+	}
+
+	type mfaRequiredResponse struct {
+		Error    string `json:"error"`
+		MFAToken string `json:"mfa_token"`
+	}
+
+	// Logins of users with two-factor authentication enabled stop at the password grant
+	login := func() (int, mfaRequiredResponse) {
+		route := fmt.Sprintf("http://%s:%s/auth/oauth/token", env.host, env.port)
+		form := url.Values{
+			"grant_type": {"password"},
+			"username":   {"muller@spfc.com"},
+			"password":   {"password"},
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("auth: request_access_token: failed to create request: %v\n", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: request_access_token: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var body mfaRequiredResponse
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	status := post("register", "", `{"email": "muller@spfc.com", "password": "password"}`, nil)
+	require.Equal(t, http.StatusCreated, status)
+
+	status, _ = login()
+	require.Equal(t, http.StatusOK, status)
+	_, token := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"muller@spfc.com"},
+		"password":   {"password"},
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		status := post("mfa/totp", "", "", nil)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	var secret string
+	var recoveryCodes []string
+	t.Run("enroll", func(t *testing.T) {
+		var enrollment struct {
+			Secret string `json:"secret"`
+			URI    string `json:"otpauth_uri"`
+			QRCode string `json:"qr_code"`
+		}
+		status := post("mfa/totp", token.AccessToken, "", &enrollment)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, enrollment.Secret)
+		require.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+		require.True(t, strings.HasPrefix(enrollment.QRCode, "data:image/png;base64,"))
+		secret = enrollment.Secret
+
+		// Not enabled until confirmed
+		status, _ = login()
+		require.Equal(t, http.StatusOK, status)
+
+		status = post("mfa/totp/confirm", token.AccessToken, `{"code": "000000"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+
+		var confirmation struct {
+			RecoveryCodes []string `json:"recovery_codes"`
+		}
+		status = post("mfa/totp/confirm", token.AccessToken, fmt.Sprintf(`{"code": %q}`, code), &confirmation)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, confirmation.RecoveryCodes, 10)
+		recoveryCodes = confirmation.RecoveryCodes
+
+		status = post("mfa/totp", token.AccessToken, "", nil)
+		require.Equal(t, http.StatusConflict, status)
+	})
+
+	t.Run("mfa_required", func(t *testing.T) {
+		status, body := login()
+		require.Equal(t, http.StatusForbidden, status)
+		require.Equal(t, "mfa_required", body.Error)
+		require.NotEmpty(t, body.MFAToken)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"mfa_otp"},
+			"mfa_token":  {"unknown"},
+			"otp":        {recoveryCodes[0]},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"mfa_otp"},
+			"mfa_token":  {body.MFAToken},
+			"otp":        {"000000"},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, tokens := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"mfa_otp"},
+			"mfa_token":  {body.MFAToken},
+			"otp":        {strings.ToUpper(recoveryCodes[0])},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, tokens.AccessToken)
+		require.NotEmpty(t, tokens.RefreshToken)
+
+		// An mfa token completes a single login
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"mfa_otp"},
+			"mfa_token":  {body.MFAToken},
+			"otp":        {recoveryCodes[1]},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("recovery_code_reuse", func(t *testing.T) {
+		_, body := login()
+		status, _ := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"mfa_otp"},
+			"mfa_token":  {body.MFAToken},
+			"otp":        {recoveryCodes[0]},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("too_many_attempts", func(t *testing.T) {
+		_, body := login()
+		for range 3 {
+			status, _ := exchangeToken(ctx, t, env, url.Values{
+				"grant_type": {"mfa_otp"},
+				"mfa_token":  {body.MFAToken},
+				"otp":        {"000000"},
+			})
+			require.Equal(t, http.StatusBadRequest, status)
+		}
+
+		status, _ := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"mfa_otp"},
+			"mfa_token":  {body.MFAToken},
+			"otp":        {recoveryCodes[2]},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		status := post("mfa/totp/disable", token.AccessToken, `{"code": "000000"}`, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status = post("mfa/totp/disable", token.AccessToken, fmt.Sprintf(`{"code": %q}`, recoveryCodes[3]), nil)
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = login()
+		require.Equal(t, http.StatusOK, status)
+
+		status = post("mfa/totp/disable", token.AccessToken, `{"code": "000000"}`, nil)
+		require.Equal(t, http.StatusConflict, status)
+	})
+
+	// Codes sent outside of a login are limited per user, even when each request has a valid access token
+	t.Run("too_many_attempts_outside_login", func(t *testing.T) {
+		var enrollment struct {
+			Secret string `json:"secret"`
+		}
+		status := post("mfa/totp", token.AccessToken, "", &enrollment)
+		require.Equal(t, http.StatusOK, status)
+
+		code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+		var confirmation struct {
+			RecoveryCodes []string `json:"recovery_codes"`
+		}
+		status = post("mfa/totp/confirm", token.AccessToken, fmt.Sprintf(`{"code": %q}`, code), &confirmation)
+		require.Equal(t, http.StatusOK, status)
+
+		for range 3 {
+			status := post("mfa/recovery-codes", token.AccessToken, `{"code": "000000"}`, nil)
+			require.Equal(t, http.StatusBadRequest, status)
+		}
+
+		status = post("mfa/recovery-codes", token.AccessToken, fmt.Sprintf(`{"code": %q}`, confirmation.RecoveryCodes[0]), nil)
+		require.Equal(t, http.StatusTooManyRequests, status)
+
+		status = post("mfa/totp/disable", token.AccessToken, fmt.Sprintf(`{"code": %q}`, confirmation.RecoveryCodes[0]), nil)
+		require.Equal(t, http.StatusTooManyRequests, status)
+	})
 }
@@ -0,0 +1,228 @@
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthAuthorizationCode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	base := fmt.Sprintf("http://%s:%s", env.host, env.port)
+	redirectURI := "http://localhost:3000/callback"
+
+	// The browser keeps the cookies of the login page and does not follow redirects to the client
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	browser := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	send := func(client *http.Client, method, route string, body io.Reader, contentType string) (*http.Response, string) {
+		req, err := http.NewRequestWithContext(ctx, method, route, body)
+		if err != nil {
+			t.Fatalf("auth: authorize: failed to create request: %v\n", err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: authorize: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		b, _ := io.ReadAll(resp.Body)
+		return resp, string(b)
+	}
+
+	resp, _ := send(http.DefaultClient, http.MethodPost, base+"/auth/register", strings.NewReader(`{"email": "rafinha@spfc.com", "password": "password"}`), "application/json")
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// Browser applications are public clients, without a secret
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/clients", strings.NewReader(`{
+		"name": "Frontend",
+		"public": true,
+		"grant_types": ["authorization_code", "refresh_token"],
+		"scopes": ["profile"],
+		"redirect_uris": ["`+redirectURI+`"]
+	}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+operatorToken(ctx, t, env))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var frontend struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&frontend))
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Empty(t, frontend.ClientSecret)
+
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	hash := sha256.Sum256([]byte(verifier))
+	authorization := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {frontend.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {"profile"},
+		"state":                 {"af0ifjsldkj"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(hash[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	authorize := func(params url.Values) (*http.Response, string) {
+		return send(browser, http.MethodGet, base+"/auth/oauth/authorize?"+params.Encode(), nil, "")
+	}
+
+	signIn := func(params url.Values, csrfToken, password string) (*http.Response, string) {
+		form := url.Values{"csrf_token": {csrfToken}, "username": {"rafinha@spfc.com"}, "password": {password}}
+		for name, value := range params {
+			form[name] = value
+		}
+		return send(browser, http.MethodPost, base+"/auth/oauth/authorize", strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	}
+
+	exchange := func(code, codeVerifier string) (int, tokenResponse) {
+		return exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {redirectURI},
+			"code_verifier": {codeVerifier},
+			"client_id":     {frontend.ClientID},
+		})
+	}
+
+	// Requests that cannot be trusted are never redirected
+	t.Run("invalid_request", func(t *testing.T) {
+		params := url.Values{}
+		for name, value := range authorization {
+			params[name] = value
+		}
+
+		params.Set("redirect_uri", "http://localhost:3000/elsewhere")
+		resp, _ := authorize(params)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Empty(t, resp.Header.Get("Location"))
+
+		// PKCE is mandatory
+		params.Set("redirect_uri", redirectURI)
+		params.Del("code_challenge")
+		resp, _ = authorize(params)
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "invalid_request", location.Query().Get("error"))
+		require.Equal(t, "af0ifjsldkj", location.Query().Get("state"))
+	})
+
+	resp, page := authorize(authorization)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	matches := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`).FindStringSubmatch(page)
+	require.Len(t, matches, 2)
+	csrfToken := matches[1]
+
+	t.Run("sign_in", func(t *testing.T) {
+		resp, _ := signIn(authorization, "forged", "password")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp, _ = signIn(authorization, csrfToken, "wrong_password")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	resp, _ = signIn(authorization, csrfToken, "password")
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "af0ifjsldkj", location.Query().Get("state"))
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// Codes are single-use and bound to the code verifier
+	t.Run("exchange", func(t *testing.T) {
+		status, _ := exchange(code, "wrong-verifier-wrong-verifier-wrong-verifier")
+		require.Equal(t, http.StatusBadRequest, status)
+
+		// The session signs the user in again without the login page
+		resp, _ := authorize(authorization)
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		code := location.Query().Get("code")
+		require.NotEmpty(t, code)
+
+		// Another client presenting the code does not burn it
+		other, _ := registerClient(ctx, t, env, `{
+			"name": "Other",
+			"public": true,
+			"grant_types": ["authorization_code"],
+			"redirect_uris": ["`+redirectURI+`"]
+		}`)
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {redirectURI},
+			"code_verifier": {verifier},
+			"client_id":     {other},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, tokens := exchange(code, verifier)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, tokens.AccessToken)
+		require.NotEmpty(t, tokens.RefreshToken)
+		require.Equal(t, "profile", tokens.Scope)
+
+		status, refreshed := exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+			"client_id":     {frontend.ClientID},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, refreshed.RefreshToken)
+
+		// A code presented twice revokes the tokens issued from it
+		status, _ = exchange(code, verifier)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {refreshed.RefreshToken},
+			"client_id":     {frontend.ClientID},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		// Access tokens issued from it are no longer accepted either
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/users/me", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,245 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentials(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	route := fmt.Sprintf("http://%s:%s/clients", env.host, env.port)
+	client := &http.Client{}
+
+	type registration struct {
+		ClientID     string   `json:"client_id"`
+		ClientSecret string   `json:"client_secret"`
+		Grants       []string `json:"grant_types"`
+		Scopes       []string `json:"scopes"`
+	}
+
+	do := func(method, route, body, accessToken string) (*http.Response, []byte) {
+		req, err := http.NewRequestWithContext(ctx, method, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("client: failed to create request: %v\n", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("client: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var raw json.RawMessage
+		json.NewDecoder(resp.Body).Decode(&raw)
+		return resp, raw
+	}
+
+	exchange := func(form url.Values, clientID, clientSecret string) (int, tokenResponse) {
+		tokenRoute := fmt.Sprintf("http://%s:%s/auth/oauth/token", env.host, env.port)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenRoute, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("client: request_access_token: failed to create request: %v\n", err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if clientID != "" {
+			req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("client: request_access_token: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var body tokenResponse
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	operator := operatorToken(ctx, t, env)
+
+	// Only operators granted the clients:write permission can register clients
+	t.Run("unauthenticated", func(t *testing.T) {
+		resp, _ := do(http.MethodPost, route, `{"name": "billing", "grant_types": ["client_credentials"]}`, "")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		status, tokens := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"must_not_touch@email.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		resp, _ = do(http.MethodPost, route, `{"name": "billing", "grant_types": ["client_credentials"]}`, tokens.AccessToken)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp, _ = do(http.MethodPost, route, `{"name": "billing", "grant_types": ["password"]}`, operator)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Introspection requires a secret
+		resp, _ = do(http.MethodPost, route, `{"name": "gateway", "public": true, "grant_types": ["refresh_token"], "scopes": ["introspect"]}`, operator)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	resp, body := do(http.MethodPost, route, `{
+		"name": "billing",
+		"grant_types": ["client_credentials"],
+		"scopes": ["invoices:read", "invoices:write"],
+		"audiences": ["billing-api"],
+		"access_token_ttl": 120
+	}`, operator)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var billing registration
+	require.NoError(t, json.Unmarshal(body, &billing))
+	require.NotEmpty(t, billing.ClientID)
+	require.NotEmpty(t, billing.ClientSecret)
+	require.Equal(t, []string{"invoices:read", "invoices:write"}, billing.Scopes)
+
+	// The secret is only shown at registration
+	t.Run("find", func(t *testing.T) {
+		resp, body := do(http.MethodGet, route+"/"+billing.ClientID, "", operator)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var found registration
+		require.NoError(t, json.Unmarshal(body, &found))
+		require.Equal(t, billing.ClientID, found.ClientID)
+		require.Empty(t, found.ClientSecret)
+	})
+
+	// Clients authenticate with client_secret_basic or client_secret_post
+	t.Run("grant", func(t *testing.T) {
+		status, tokens := exchange(url.Values{"grant_type": {"client_credentials"}}, billing.ClientID, billing.ClientSecret)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, tokens.AccessToken)
+		require.Empty(t, tokens.RefreshToken)
+		require.Equal(t, 120, tokens.ExpiresIn)
+		require.Equal(t, "invoices:read invoices:write", tokens.Scope)
+
+		status, tokens = exchange(url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {billing.ClientID},
+			"client_secret": {billing.ClientSecret},
+			"scope":         {"invoices:read"},
+		}, "", "")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "invoices:read", tokens.Scope)
+
+		// The subject of the token is the client
+		gateway, gatewaySecret := registerClient(ctx, t, env, `{"name": "gateway", "grant_types": ["client_credentials"], "scopes": ["introspect"]}`)
+		introspectRoute := fmt.Sprintf("http://%s:%s/auth/oauth/introspect", env.host, env.port)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, introspectRoute, strings.NewReader(url.Values{"token": {tokens.AccessToken}}.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(gateway), url.QueryEscape(gatewaySecret))
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var introspection struct {
+			Active   bool     `json:"active"`
+			Sub      string   `json:"sub"`
+			ClientID string   `json:"client_id"`
+			Aud      []string `json:"aud"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&introspection))
+		require.True(t, introspection.Active)
+		require.Equal(t, billing.ClientID, introspection.Sub)
+		require.Equal(t, billing.ClientID, introspection.ClientID)
+		require.Equal(t, []string{"billing-api"}, introspection.Aud)
+
+		// Tokens for the custom audience of a client are not accepted by the routes of this service
+		resp, _ = do(http.MethodGet, route, "", tokens.AccessToken)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		// Wrong secret
+		status, body := exchange(url.Values{"grant_type": {"client_credentials"}}, billing.ClientID, "wrong_secret")
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "invalid_client", body.Error)
+
+		// Scope the client was not registered with
+		status, body = exchange(url.Values{
+			"grant_type": {"client_credentials"},
+			"scope":      {"invoices:read payments:write"},
+		}, billing.ClientID, billing.ClientSecret)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", body.Error)
+
+		// More than one authentication method
+		status, body = exchange(url.Values{
+			"grant_type": {"client_credentials"},
+			"client_id":  {billing.ClientID},
+		}, billing.ClientID, billing.ClientSecret)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_request", body.Error)
+	})
+
+	// Tokens issued to a client can only be revoked by that client (RFC 7009 section 2.1)
+	t.Run("revoke", func(t *testing.T) {
+		status, tokens := exchange(url.Values{"grant_type": {"client_credentials"}}, billing.ClientID, billing.ClientSecret)
+		require.Equal(t, http.StatusOK, status)
+
+		revoke := func(clientID, clientSecret string) (int, tokenResponse) {
+			revokeRoute := fmt.Sprintf("http://%s:%s/auth/oauth/revoke", env.host, env.port)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, revokeRoute, strings.NewReader(url.Values{"token": {tokens.AccessToken}}.Encode()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if clientID != "" {
+				req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+			}
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			var body tokenResponse
+			json.NewDecoder(resp.Body).Decode(&body)
+			return resp.StatusCode, body
+		}
+
+		status, body := revoke("", "")
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "invalid_client", body.Error)
+
+		status, body = revoke(billing.ClientID, "wrong_secret")
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "invalid_client", body.Error)
+
+		status, _ = revoke(billing.ClientID, billing.ClientSecret)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	// Deleted clients can no longer get tokens
+	t.Run("delete", func(t *testing.T) {
+		resp, _ := do(http.MethodDelete, route+"/"+billing.ClientID, "", operator)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, _ = do(http.MethodGet, route+"/"+billing.ClientID, "", operator)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		status, _ := exchange(url.Values{"grant_type": {"client_credentials"}}, billing.ClientID, billing.ClientSecret)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+}
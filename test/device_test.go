@@ -0,0 +1,219 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthDeviceCode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	base := fmt.Sprintf("http://%s:%s", env.host, env.port)
+
+	// The browser on which the user approves the device
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	browser := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	send := func(client *http.Client, method, route string, form url.Values) (*http.Response, string) {
+		var body io.Reader
+		if form != nil {
+			body = strings.NewReader(form.Encode())
+		}
+		req, err := http.NewRequestWithContext(ctx, method, route, body)
+		if err != nil {
+			t.Fatalf("auth: device: failed to create request: %v\n", err)
+		}
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: device: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		b, _ := io.ReadAll(resp.Body)
+		return resp, string(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/auth/register", strings.NewReader(`{"email": "luis.fabiano@spfc.com", "password": "password"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// CLI tools are public clients, without a secret
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, base+"/clients", strings.NewReader(`{
+		"name": "CLI",
+		"public": true,
+		"grant_types": ["urn:ietf:params:oauth:grant-type:device_code", "refresh_token"],
+		"scopes": ["profile"]
+	}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+operatorToken(ctx, t, env))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var cli struct {
+		ClientID string `json:"client_id"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&cli))
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, body := send(http.DefaultClient, http.MethodPost, base+"/auth/oauth/device_authorization", url.Values{
+		"client_id": {cli.ClientID},
+		"scope":     {"profile"},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var device struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &device))
+	require.NotEmpty(t, device.DeviceCode)
+	require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, device.UserCode)
+	require.True(t, strings.HasSuffix(device.VerificationURI, "/auth/device"))
+	require.Contains(t, device.VerificationURIComplete, url.Values{"user_code": {device.UserCode}}.Encode())
+	require.Positive(t, device.ExpiresIn)
+	require.Positive(t, device.Interval)
+
+	poll := func(deviceCode string) (int, string, tokenResponse) {
+		resp, body := send(http.DefaultClient, http.MethodPost, base+"/auth/oauth/token", url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {deviceCode},
+			"client_id":   {cli.ClientID},
+		})
+		var polled tokenResponse
+		json.Unmarshal([]byte(body), &polled)
+		return resp.StatusCode, polled.Error, polled
+	}
+
+	// Devices wait for the user, and are slowed down when polling faster than the interval
+	t.Run("polling", func(t *testing.T) {
+		status, code, _ := poll(device.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "authorization_pending", code)
+
+		status, code, _ = poll(device.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "slow_down", code)
+
+		status, _, _ = poll("unknown")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	csrfPattern := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+	route := base + "/auth/device?" + url.Values{"user_code": {device.UserCode}}.Encode()
+
+	// Users sign in on the verification page before approving the device
+	resp, page := send(browser, http.MethodGet, route, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	matches := csrfPattern.FindStringSubmatch(page)
+	require.Len(t, matches, 2)
+	csrfToken := matches[1]
+
+	resp, _ = send(browser, http.MethodPost, route, url.Values{
+		"csrf_token": {csrfToken},
+		"username":   {"luis.fabiano@spfc.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	// User codes are typed by hand, regardless of case and dashes
+	resp, page = send(browser, http.MethodGet, base+"/auth/device?"+url.Values{"user_code": {strings.ToLower(strings.ReplaceAll(device.UserCode, "-", ""))}}.Encode(), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, page, "Connect CLI")
+
+	t.Run("verification", func(t *testing.T) {
+		resp, _ := send(browser, http.MethodGet, base+"/auth/device?user_code=BBBB-BBBB", nil)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = send(browser, http.MethodPost, base+"/auth/device", url.Values{
+			"csrf_token": {"forged"},
+			"user_code":  {device.UserCode},
+			"decision":   {"approve"},
+		})
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	resp, _ = send(browser, http.MethodPost, base+"/auth/device", url.Values{
+		"csrf_token": {csrfToken},
+		"user_code":  {device.UserCode},
+		"decision":   {"approve"},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A user code is decided only once
+	resp, _ = send(browser, http.MethodPost, base+"/auth/device", url.Values{
+		"csrf_token": {csrfToken},
+		"user_code":  {device.UserCode},
+		"decision":   {"deny"},
+	})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The interval was increased by 5 seconds by slow_down
+	time.Sleep(time.Duration(device.Interval+5) * time.Second)
+	status, _, tokens := poll(device.DeviceCode)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, tokens.AccessToken)
+	require.NotEmpty(t, tokens.RefreshToken)
+	require.Equal(t, "profile", tokens.Scope)
+
+	// Device codes are single-use
+	status, _, _ = poll(device.DeviceCode)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	t.Run("denied", func(t *testing.T) {
+		resp, body := send(http.DefaultClient, http.MethodPost, base+"/auth/oauth/device_authorization", url.Values{
+			"client_id": {cli.ClientID},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var denied struct {
+			DeviceCode string `json:"device_code"`
+			UserCode   string `json:"user_code"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &denied))
+
+		resp, _ = send(browser, http.MethodPost, base+"/auth/device", url.Values{
+			"csrf_token": {csrfToken},
+			"user_code":  {denied.UserCode},
+			"decision":   {"deny"},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		status, code, _ := poll(denied.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "access_denied", code)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"auth/internal/auth"
 	"auth/internal/server"
 
 	tc "github.com/testcontainers/testcontainers-go/modules/compose"
@@ -49,10 +50,38 @@ func TestMain(m *testing.M) {
 	os.Setenv("AUTH_SERVER_HOST", "localhost")
 	os.Setenv("AUTH_SERVER_PORT", "8111")
 
-	go server.Exec(ctx, args, nil, os.Stdout, nil, nil, nil)
+	// Every access token is enriched with the tenant of the test suite
+	tenant := auth.ClaimsEnricherFunc(func(context.Context, auth.EnrichClaimsRequest) (auth.EnrichClaimsResponse, error) {
+		return auth.EnrichClaimsResponse{Claims: map[string]any{"tenant": "spfc"}}, nil
+	})
+
+	go server.Exec(ctx, args, nil, os.Stdout, nil, nil, nil, server.WithClaimsEnricher(tenant))
 
 	// Wait for server readiness
-	if err := waitForReadiness(ctx); err != nil {
+	if err := waitForReadiness(ctx, os.Getenv("AUTH_SERVER_PORT")); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// A second server signs with an asymmetric algorithm, bootstrapping its key directory.
+	// It is started once the first one has migrated the schema.
+	keyDir, err := os.MkdirTemp("", "auth-keys-")
+	if err != nil {
+		log.Fatalf("failed to create key directory: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(keyDir)
+
+	asymmetricArgs := append(args,
+		"--server.port", asymmetricPort,
+		"--auth.jwt.alg", "ES256",
+		"--auth.jwt.keydir", keyDir,
+		"--auth.jwt.rotation.interval", "86400",
+		"--auth.jwt.rotation.delay", "0",
+	)
+	go server.Exec(ctx, asymmetricArgs, nil, os.Stdout, nil, nil, nil)
+
+	if err := waitForReadiness(ctx, asymmetricPort); err != nil {
 		log.Fatal(err)
 		return
 	}
@@ -60,6 +89,9 @@ func TestMain(m *testing.M) {
 	m.Run()
 }
 
+// asymmetricPort is the port of the server signing with ES256
+const asymmetricPort = "8112"
+
 type env struct {
 	host string
 	port string
@@ -82,14 +114,14 @@ func newEnv() (*env, error) {
 	}, nil
 }
 
-func waitForReadiness(ctx context.Context) error {
+func waitForReadiness(ctx context.Context, port string) error {
 	env, err := newEnv()
 	if err != nil {
 		return err
 	}
 
 	client := &http.Client{}
-	url := fmt.Sprintf("http://%s:%s/healthz/readiness", env.host, env.port)
+	url := fmt.Sprintf("http://%s:%s/healthz/readiness", env.host, port)
 	startTime := time.Now()
 	waitTime := 750 * time.Millisecond
 	timeout := 5 * time.Second // minimum of server.health.delay + 1
@@ -0,0 +1,201 @@
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenIDConnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	issuer := fmt.Sprintf("http://%s:%s/auth", env.host, env.port)
+	redirectURI := "http://localhost:3000/login/generic_oauth"
+
+	get := func(route, accessToken string, v any) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, route, nil)
+		if err != nil {
+			t.Fatalf("auth: oidc: failed to create request: %v\n", err)
+		}
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("auth: oidc: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+		if v != nil {
+			json.NewDecoder(resp.Body).Decode(v)
+		}
+		return resp.StatusCode
+	}
+
+	// Clients find every endpoint from the issuer alone
+	var configuration struct {
+		Issuer                string   `json:"issuer"`
+		AuthorizationEndpoint string   `json:"authorization_endpoint"`
+		TokenEndpoint         string   `json:"token_endpoint"`
+		UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+		JWKSURI               string   `json:"jwks_uri"`
+		GrantTypes            []string `json:"grant_types_supported"`
+		SigningAlgorithms     []string `json:"id_token_signing_alg_values_supported"`
+	}
+	status := get(issuer+"/.well-known/openid-configuration", "", &configuration)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, issuer, configuration.Issuer)
+	require.Equal(t, issuer+"/oauth/authorize", configuration.AuthorizationEndpoint)
+	require.Equal(t, issuer+"/oauth/token", configuration.TokenEndpoint)
+	require.Contains(t, configuration.GrantTypes, "authorization_code")
+	require.Equal(t, []string{"HS256"}, configuration.SigningAlgorithms)
+
+	status = get(configuration.JWKSURI, "", nil)
+	require.Equal(t, http.StatusOK, status)
+
+	resp, err := http.Post(issuer+"/register", "application/json", strings.NewReader(`{"email": "calleri@spfc.com", "password": "password"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("http://%s:%s/clients", env.host, env.port), strings.NewReader(`{
+		"name": "Grafana",
+		"grant_types": ["authorization_code"],
+		"scopes": ["openid", "email", "profile"],
+		"redirect_uris": ["`+redirectURI+`"]
+	}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+operatorToken(ctx, t, env))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var grafana struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&grafana))
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// Sign in on the login page of the authorization endpoint
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	browser := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	verifier := "M25iVXpKU3puUjFaYWg3T1NDTDQtcW1ROUY5YXlwalNoc0hhakxifmZHag"
+	hash := sha256.Sum256([]byte(verifier))
+	authorization := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {grafana.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {"openid email"},
+		"state":                 {"xyz"},
+		"nonce":                 {"n-0S6_WzA2Mj"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(hash[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	resp, err = browser.Get(configuration.AuthorizationEndpoint + "?" + authorization.Encode())
+	require.NoError(t, err)
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	matches := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`).FindSubmatch(page)
+	require.Len(t, matches, 2)
+
+	form := url.Values{"csrf_token": {string(matches[1])}, "username": {"calleri@spfc.com"}, "password": {"password"}}
+	for name, value := range authorization {
+		form[name] = value
+	}
+	resp, err = browser.PostForm(configuration.AuthorizationEndpoint, form)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// Confidential clients authenticate with HTTP Basic
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, configuration.TokenEndpoint, strings.NewReader(url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	}.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(grafana.ClientID), url.QueryEscape(grafana.ClientSecret))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+		Scope       string `json:"scope"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tokens))
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "openid email", tokens.Scope)
+	require.NotEmpty(t, tokens.IDToken)
+
+	t.Run("id_token", func(t *testing.T) {
+		parts := strings.Split(tokens.IDToken, ".")
+		require.Len(t, parts, 3)
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		require.Equal(t, issuer, claims["iss"])
+		require.Equal(t, []any{grafana.ClientID}, claims["aud"])
+		require.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+		require.Equal(t, "calleri@spfc.com", claims["email"])
+		require.Equal(t, true, claims["email_verified"])
+		require.NotZero(t, claims["auth_time"])
+		require.NotContains(t, claims, "updated_at")
+
+		// ID tokens never grant access
+		status := get(configuration.UserInfoEndpoint, tokens.IDToken, nil)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("userinfo", func(t *testing.T) {
+		var claims map[string]any
+		status := get(configuration.UserInfoEndpoint, tokens.AccessToken, &claims)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, claims["sub"])
+		require.Equal(t, "calleri@spfc.com", claims["email"])
+
+		// Tokens without the openid scope are not allowed
+		_, password := exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"calleri@spfc.com"},
+			"password":   {"password"},
+		})
+		status = get(configuration.UserInfoEndpoint, password.AccessToken, nil)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	route := fmt.Sprintf("http://%s:%s/auth/oauth/introspect", env.host, env.port)
+	client := &http.Client{}
+
+	introspect := func(clientID string) *http.Response {
+		form := url.Values{"token": {"unknown"}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("ratelimit: failed to create request: %v\n", err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(clientID, "secret")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("ratelimit: request failed: %v\n", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// Requests within the limit carry the quota left
+	t.Run("within_limit", func(t *testing.T) {
+		resp := introspect("throttled")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Equal(t, "20", resp.Header.Get("RateLimit-Limit"))
+		require.Equal(t, "19", resp.Header.Get("RateLimit-Remaining"))
+		require.NotEmpty(t, resp.Header.Get("RateLimit-Reset"))
+	})
+
+	// Past the limit, requests are rejected with 429 Too Many Requests
+	t.Run("throttled", func(t *testing.T) {
+		for range 19 {
+			resp := introspect("throttled")
+			require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+
+		resp := introspect("throttled")
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		require.NoError(t, err)
+		require.Positive(t, retryAfter)
+	})
+
+	// Other clients have their own quota
+	t.Run("other_client", func(t *testing.T) {
+		resp := introspect("other")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthScope(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	route := fmt.Sprintf("http://%s:%s/auth/register", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(`{"email": "jorge.wagner@spfc.com", "password": "password"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	claims := func(token string) map[string]any {
+		parts := strings.Split(token, ".")
+		require.Len(t, parts, 3)
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		return claims
+	}
+
+	login := func(scope string) (int, tokenResponse) {
+		return exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"jorge.wagner@spfc.com"},
+			"password":   {"password"},
+			"scope":      {scope},
+		})
+	}
+
+	// Users are granted every configured scope when none is requested
+	t.Run("default", func(t *testing.T) {
+		status, tokens := login("")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "orders:read orders:write", tokens.Scope)
+		require.Equal(t, "orders:read orders:write", claims(tokens.AccessToken)["scope"])
+	})
+
+	t.Run("invalid_scope", func(t *testing.T) {
+		status, tokens := login("orders:read admin")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", tokens.Error)
+	})
+
+	status, tokens := login("orders:read")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "orders:read", tokens.Scope)
+
+	// Custom claims are added by the enricher of the test server
+	t.Run("enriched", func(t *testing.T) {
+		issued := claims(tokens.AccessToken)
+		require.Equal(t, "orders:read", issued["scope"])
+		require.Equal(t, "spfc", issued["tenant"])
+	})
+
+	// Refreshed tokens keep the scope of the login, which cannot be widened
+	t.Run("refresh", func(t *testing.T) {
+		status, refreshed := exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+			"scope":         {"orders:write"},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", refreshed.Error)
+
+		status, refreshed = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "orders:read", refreshed.Scope)
+		require.Equal(t, "spfc", claims(refreshed.AccessToken)["tenant"])
+	})
+}
@@ -2,8 +2,11 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -124,3 +127,486 @@ func TestUserHardDeleteByID(t *testing.T) {
 		require.Equal(t, http.StatusNoContent, resp.StatusCode)
 	})
 }
+
+func TestUserChangePassword(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	// Registering a dedicated user, since its password is changed
+	route := fmt.Sprintf("http://%s:%s/auth/register", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(`{"email": "hernanes@spfc.com", "password": "password"}`))
+	if err != nil {
+		t.Fatalf("user: change_password: failed to create request: %v\n", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("user: change_password: request failed: %v\n", err)
+	}
+	var registered struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&registered)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"hernanes@spfc.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	changePassword := func(id, accessToken, body string) (int, tokenResponse) {
+		route := fmt.Sprintf("http://%s:%s/users/%s/password", env.host, env.port, id)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("user: change_password: failed to create request: %v\n", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("user: change_password: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var tokens tokenResponse
+		json.NewDecoder(resp.Body).Decode(&tokens)
+		return resp.StatusCode, tokens
+	}
+
+	// Wrong current password receives Bad Request
+	t.Run("invalid_password", func(t *testing.T) {
+		status, _ := changePassword(registered.Data.ID, tokens.AccessToken, `{"current_password": "wrong", "new_password": "new_password"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// User tries to change the password of another user receives Forbidden
+	t.Run("forbidden", func(t *testing.T) {
+		status, _ := changePassword("1aef49bd-3296-45fb-84b9-083cf81b0e44", tokens.AccessToken, `{"current_password": "password", "new_password": "new_password"}`)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	// Changing the password returns a new token pair and invalidates the previous tokens
+	t.Run("changed", func(t *testing.T) {
+		status, renewed := changePassword(registered.Data.ID, tokens.AccessToken, `{"current_password": "password", "new_password": "new_password"}`)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, renewed.AccessToken)
+		require.NotEmpty(t, renewed.RefreshToken)
+
+		status, _ = changePassword(registered.Data.ID, tokens.AccessToken, `{"current_password": "new_password", "new_password": "password"}`)
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tokens.RefreshToken},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = changePassword(registered.Data.ID, renewed.AccessToken, `{"current_password": "new_password", "new_password": "password"}`)
+		require.Equal(t, http.StatusOK, status)
+	})
+}
+
+func TestUserSoftDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	// Registering a dedicated user, since it is deleted
+	route := fmt.Sprintf("http://%s:%s/auth/register", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(`{"email": "rogerio.ceni@spfc.com", "password": "password"}`))
+	if err != nil {
+		t.Fatalf("user: soft_delete: failed to create request: %v\n", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("user: soft_delete: request failed: %v\n", err)
+	}
+	var registered struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&registered)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	login := func() (int, tokenResponse) {
+		return exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"rogerio.ceni@spfc.com"},
+			"password":   {"password"},
+		})
+	}
+
+	deleteUser := func(accessToken, body string) int {
+		route := fmt.Sprintf("http://%s:%s/users/%s/delete", env.host, env.port, registered.Data.ID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("user: soft_delete: failed to create request: %v\n", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("user: soft_delete: request failed: %v\n", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	restoreUser := func(accessToken string) int {
+		route := fmt.Sprintf("http://%s:%s/users/%s/restore", env.host, env.port, registered.Data.ID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, nil)
+		if err != nil {
+			t.Fatalf("user: soft_delete: failed to create request: %v\n", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("user: soft_delete: request failed: %v\n", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	status, tokens := login()
+	require.Equal(t, http.StatusOK, status)
+
+	// Deleted users are signed out everywhere
+	require.Equal(t, http.StatusNoContent, deleteUser(tokens.AccessToken, `{"password": "password"}`))
+	require.Equal(t, http.StatusUnauthorized, deleteUser(tokens.AccessToken, `{"password": "password"}`))
+	status, _ = exchangeToken(ctx, t, env, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {tokens.RefreshToken},
+	})
+	require.Equal(t, http.StatusBadRequest, status)
+
+	// Logging in within the grace period restores the user
+	t.Run("restored_by_login", func(t *testing.T) {
+		status, restored := login()
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, restored.AccessToken)
+		tokens = restored
+	})
+
+	require.Equal(t, http.StatusNoContent, deleteUser(tokens.AccessToken, `{"password": "password"}`))
+
+	operator := operatorToken(ctx, t, env)
+
+	// Operators granted the users:restore permission restore deleted users on request
+	t.Run("restored_by_operator", func(t *testing.T) {
+		require.Equal(t, http.StatusUnauthorized, restoreUser("invalid"))
+		require.Equal(t, http.StatusNoContent, restoreUser(operator))
+
+		// Only users pending deletion can be restored
+		require.Equal(t, http.StatusNotFound, restoreUser(operator))
+
+		status, restored := login()
+		require.Equal(t, http.StatusOK, status)
+		tokens = restored
+	})
+
+	// Permanently deleted users are gone for good
+	t.Run("permanent", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, deleteUser(tokens.AccessToken, `{"password": "password", "permanent": true}`))
+		require.Equal(t, http.StatusNotFound, restoreUser(operator))
+
+		status, _ := login()
+		require.NotEqual(t, http.StatusOK, status)
+	})
+}
+
+func TestUserProfile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	route := fmt.Sprintf("http://%s:%s/auth/register", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(`{"email": "kaka@spfc.com", "password": "password"}`))
+	if err != nil {
+		t.Fatalf("user: profile: failed to create request: %v\n", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("user: profile: request failed: %v\n", err)
+	}
+	var registered struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&registered)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"kaka@spfc.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	type profile struct {
+		ID            string  `json:"id"`
+		Email         string  `json:"email"`
+		EmailVerified bool    `json:"email_verified"`
+		DisplayName   *string `json:"display_name"`
+		Locale        *string `json:"locale"`
+		Timezone      *string `json:"timezone"`
+		AvatarURL     *string `json:"avatar_url"`
+	}
+
+	send := func(method, path, body string, auth func(*http.Request)) (int, profile) {
+		route := fmt.Sprintf("http://%s:%s/users/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, method, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("user: profile: failed to create request: %v\n", err)
+		}
+		if body != "" {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+		auth(req)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("user: profile: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var found struct {
+			Data profile `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&found)
+		return resp.StatusCode, found.Data
+	}
+
+	bearer := func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	}
+
+	// Users find themselves, with an empty profile until edited
+	status, me := send(http.MethodGet, "me", "", bearer)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, registered.Data.ID, me.ID)
+	require.Equal(t, "kaka@spfc.com", me.Email)
+	require.Nil(t, me.DisplayName)
+
+	t.Run("patched", func(t *testing.T) {
+		status, me := send(http.MethodPatch, "me", `{"display_name": "Kaká", "locale": "pt-BR", "timezone": "America/Sao_Paulo"}`, bearer)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "Kaká", *me.DisplayName)
+		require.Equal(t, "pt-BR", *me.Locale)
+		require.Equal(t, "America/Sao_Paulo", *me.Timezone)
+		require.Nil(t, me.AvatarURL)
+
+		// Absent fields are left unchanged, null fields are cleared
+		status, me = send(http.MethodPatch, "me", `{"locale": null, "avatar_url": "https://cdn.spfc.com/kaka.png"}`, bearer)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "Kaká", *me.DisplayName)
+		require.Nil(t, me.Locale)
+		require.Equal(t, "America/Sao_Paulo", *me.Timezone)
+		require.Equal(t, "https://cdn.spfc.com/kaka.png", *me.AvatarURL)
+	})
+
+	t.Run("invalid_patch", func(t *testing.T) {
+		status, _ := send(http.MethodPatch, "me", `{"email": "other@spfc.com"}`, bearer)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = send(http.MethodPatch, "me", `{"timezone": "Mars/Olympus_Mons"}`, bearer)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = send(http.MethodPatch, "me", `{"display_name": 10}`, bearer)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = send(http.MethodPatch, "me", `["display_name"]`, bearer)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// Users can only find themselves by ID, while operators granted users:read can find anyone
+	t.Run("find_by_id", func(t *testing.T) {
+		status, found := send(http.MethodGet, registered.Data.ID, "", bearer)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "Kaká", *found.DisplayName)
+
+		status, _ = send(http.MethodGet, "1aef49bd-3296-45fb-84b9-083cf81b0e44", "", bearer)
+		require.Equal(t, http.StatusForbidden, status)
+
+		operator := operatorToken(ctx, t, env)
+		status, found = send(http.MethodGet, registered.Data.ID, "", func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+operator)
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "kaka@spfc.com", found.Email)
+
+		status, _ = send(http.MethodGet, registered.Data.ID, "", func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer invalid")
+		})
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		status, _ = send(http.MethodGet, registered.Data.ID, "", func(*http.Request) {})
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+}
+
+func TestUserChangeEmail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	route := fmt.Sprintf("http://%s:%s/auth/register", env.host, env.port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, strings.NewReader(`{"email": "luis.fabiano@spfc.com", "password": "password"}`))
+	if err != nil {
+		t.Fatalf("user: change email: failed to create request: %v\n", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("user: change email: request failed: %v\n", err)
+	}
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	status, tokens := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"luis.fabiano@spfc.com"},
+		"password":   {"password"},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	type changed struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+
+	send := func(method, path, body string) (int, changed) {
+		route := fmt.Sprintf("http://%s:%s/users/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, method, route, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("user: change email: failed to create request: %v\n", err)
+		}
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("user: change email: request failed: %v\n", err)
+		}
+		defer resp.Body.Close()
+
+		var found struct {
+			Data changed `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&found)
+		return resp.StatusCode, found.Data
+	}
+
+	t.Run("rejected", func(t *testing.T) {
+		status, _ := send(http.MethodPost, "me/email", `{"password": "wrong_password", "email": "fabuloso@spfc.com"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = send(http.MethodPost, "me/email", `{"password": "password", "email": "Luis.Fabiano@spfc.com"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = send(http.MethodPost, "me/email", `{"password": "password", "email": "must_not_touch@email.com"}`)
+		require.Equal(t, http.StatusConflict, status)
+
+		status, _ = send(http.MethodPost, "me/email/confirm", `{"code": "000000"}`)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	// The code is sent to the new address, which is verified once confirmed
+	t.Run("confirmed", func(t *testing.T) {
+		status, _ := send(http.MethodPost, "me/email", `{"password": "password", "email": "fabuloso@spfc.com"}`)
+		require.Equal(t, http.StatusAccepted, status)
+
+		code := readMail(ctx, t, "fabuloso@spfc.com", regexp.MustCompile(`new one of your account is ([0-9]{6})`))
+		wrong := "000000"
+		if code == wrong {
+			wrong = "000001"
+		}
+
+		status, _ = send(http.MethodPost, "me/email/confirm", fmt.Sprintf(`{"code": %q}`, wrong))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, me := send(http.MethodPost, "me/email/confirm", fmt.Sprintf(`{"code": %q}`, code))
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "fabuloso@spfc.com", me.Email)
+		require.True(t, me.EmailVerified)
+
+		// Codes are single use
+		status, _ = send(http.MethodPost, "me/email/confirm", fmt.Sprintf(`{"code": %q}`, code))
+		require.Equal(t, http.StatusNotFound, status)
+
+		status, _ = exchangeToken(ctx, t, env, url.Values{
+			"grant_type": {"password"},
+			"username":   {"fabuloso@spfc.com"},
+			"password":   {"password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	// The current address is sent a token that cancels the change without signing in
+	t.Run("canceled", func(t *testing.T) {
+		status, _ := send(http.MethodPost, "me/email", `{"password": "password", "email": "luis.fabiano.9@spfc.com"}`)
+		require.Equal(t, http.StatusAccepted, status)
+
+		token := readMail(ctx, t, "fabuloso@spfc.com", regexp.MustCompile(`use this token to cancel it: ([A-Za-z0-9_-]+)`))
+
+		route := fmt.Sprintf("http://%s:%s/users/email/cancel?token=%s", env.host, env.port, url.QueryEscape(token))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, route, nil)
+		if err != nil {
+			t.Fatalf("user: change email: failed to create request: %v\n", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("user: change email: request failed: %v\n", err)
+		}
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		code := readMail(ctx, t, "luis.fabiano.9@spfc.com", regexp.MustCompile(`new one of your account is ([0-9]{6})`))
+		status, _ = send(http.MethodPost, "me/email/confirm", fmt.Sprintf(`{"code": %q}`, code))
+		require.Equal(t, http.StatusNotFound, status)
+
+		// Tokens are single use
+		resp, err = client.Do(req)
+		if err != nil {
+			t.Fatalf("user: change email: request failed: %v\n", err)
+		}
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,341 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// softAuthenticator emulates a platform authenticator holding a single passkey,
+// with "none" attestation and user verification always performed.
+type softAuthenticator struct {
+	rpID         string
+	origin       string
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+// Authenticator data flags
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credentialID := make([]byte, 32)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &softAuthenticator{
+		rpID:         "localhost",
+		origin:       "http://localhost:3000",
+		key:          key,
+		credentialID: credentialID,
+	}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony, challenge string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func (a *softAuthenticator) authenticatorData(t *testing.T, attested bool) []byte {
+	t.Helper()
+
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+
+	flags := byte(flagUserPresent | flagUserVerified)
+	if attested {
+		flags |= flagAttestedData
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+
+	if attested {
+		// COSE_Key of an ES256 public key
+		publicKey, err := cbor.Marshal(map[int]any{
+			1:  2,
+			3:  -7,
+			-1: 1,
+			-2: a.key.X.FillBytes(make([]byte, 32)),
+			-3: a.key.Y.FillBytes(make([]byte, 32)),
+		})
+		require.NoError(t, err)
+
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, publicKey...)
+	}
+	return data
+}
+
+// create answers navigator.credentials.create() with the options of a registration ceremony.
+func (a *softAuthenticator) create(t *testing.T, challenge, userID string) json.RawMessage {
+	t.Helper()
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(userID)
+	require.NoError(t, err)
+	a.userHandle = userHandle
+
+	attestationObject, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authenticatorData(t, true),
+	})
+	require.NoError(t, err)
+
+	id := base64.RawURLEncoding.EncodeToString(a.credentialID)
+	credential, err := json.Marshal(map[string]any{
+		"id":    id,
+		"rawId": id,
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData(t, "webauthn.create", challenge)),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+			"transports":        []string{"internal"},
+		},
+	})
+	require.NoError(t, err)
+	return credential
+}
+
+// get answers navigator.credentials.get() with the options of a login ceremony.
+func (a *softAuthenticator) get(t *testing.T, challenge string) json.RawMessage {
+	t.Helper()
+
+	a.signCount++
+	authenticatorData := a.authenticatorData(t, false)
+	clientData := a.clientData(t, "webauthn.get", challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	id := base64.RawURLEncoding.EncodeToString(a.credentialID)
+	credential, err := json.Marshal(map[string]any{
+		"id":    id,
+		"rawId": id,
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authenticatorData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	})
+	require.NoError(t, err)
+	return credential
+}
+
+func TestAuthWebAuthn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env, err := newEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	client := &http.Client{}
+
+	do := func(method, path, accessToken string, body any, v any) int {
+		var payload bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&payload).Encode(body))
+		}
+
+		route := fmt.Sprintf("http://%s:%s/auth/%s", env.host, env.port, path)
+		req, err := http.NewRequestWithContext(ctx, method, route, &payload)
+		if err != nil {
+			t.Fatalf("auth: %s: failed to create request: %v\n", path, err)
+		}
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("auth: %s: request failed: %v\n", path, err)
+		}
+		defer resp.Body.Close()
+		if v != nil {
+			json.NewDecoder(resp.Body).Decode(v)
+		}
+		return resp.StatusCode
+	}
+
+	type registrationOptions struct {
+		CeremonyToken string `json:"ceremony_token"`
+		PublicKey     struct {
+			Challenge string `json:"challenge"`
+			User      struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+
+	type loginOptions struct {
+		CeremonyToken string `json:"ceremony_token"`
+		PublicKey     struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+
+	login := func(a *softAuthenticator) (int, tokenResponse) {
+		var options loginOptions
+		status := do(http.MethodPost, "webauthn/login/begin", "", nil, &options)
+		require.Equal(t, http.StatusOK, status)
+
+		var tokens tokenResponse
+		status = do(http.MethodPost, "webauthn/login/finish", "", map[string]any{
+			"ceremony_token": options.CeremonyToken,
+			"credential":     a.get(t, options.PublicKey.Challenge),
+		}, &tokens)
+		return status, tokens
+	}
+
+	status := do(http.MethodPost, "register", "", map[string]any{"email": "lugano@spfc.com", "password": "password"}, nil)
+	require.Equal(t, http.StatusCreated, status)
+
+	_, token := exchangeToken(ctx, t, env, url.Values{
+		"grant_type": {"password"},
+		"username":   {"lugano@spfc.com"},
+		"password":   {"password"},
+	})
+
+	passkey := newSoftAuthenticator(t)
+	credentialID := base64.RawURLEncoding.EncodeToString(passkey.credentialID)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		status := do(http.MethodPost, "webauthn/register/begin", "", nil, nil)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("register", func(t *testing.T) {
+		var options registrationOptions
+		status := do(http.MethodPost, "webauthn/register/begin", token.AccessToken, nil, &options)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, options.CeremonyToken)
+		require.NotEmpty(t, options.PublicKey.Challenge)
+
+		credential := passkey.create(t, options.PublicKey.Challenge, options.PublicKey.User.ID)
+
+		var created struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		status = do(http.MethodPost, "webauthn/register/finish", token.AccessToken, map[string]any{
+			"ceremony_token": options.CeremonyToken,
+			"name":           "Laptop",
+			"credential":     credential,
+		}, &created)
+		require.Equal(t, http.StatusCreated, status)
+		require.Equal(t, credentialID, created.ID)
+		require.Equal(t, "Laptop", created.Name)
+
+		// A ceremony can only be finished once
+		status = do(http.MethodPost, "webauthn/register/finish", token.AccessToken, map[string]any{
+			"ceremony_token": options.CeremonyToken,
+			"credential":     credential,
+		}, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		var list struct {
+			Credentials []struct {
+				ID         string   `json:"id"`
+				Transports []string `json:"transports"`
+			} `json:"credentials"`
+		}
+		status = do(http.MethodGet, "webauthn/credentials", token.AccessToken, nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, list.Credentials, 1)
+		require.Equal(t, credentialID, list.Credentials[0].ID)
+		require.Equal(t, []string{"internal"}, list.Credentials[0].Transports)
+	})
+
+	t.Run("wrong_challenge", func(t *testing.T) {
+		var options registrationOptions
+		status := do(http.MethodPost, "webauthn/register/begin", token.AccessToken, nil, &options)
+		require.Equal(t, http.StatusOK, status)
+
+		other := newSoftAuthenticator(t)
+		status = do(http.MethodPost, "webauthn/register/finish", token.AccessToken, map[string]any{
+			"ceremony_token": options.CeremonyToken,
+			"credential":     other.create(t, "bm90IHRoZSBjaGFsbGVuZ2U", options.PublicKey.User.ID),
+		}, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("login", func(t *testing.T) {
+		status, tokens := login(passkey)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, tokens.AccessToken)
+		require.NotEmpty(t, tokens.RefreshToken)
+
+		status = do(http.MethodGet, "webauthn/credentials", tokens.AccessToken, nil, nil)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("wrong_key", func(t *testing.T) {
+		impostor := newSoftAuthenticator(t)
+		impostor.credentialID = passkey.credentialID
+		impostor.userHandle = passkey.userHandle
+		impostor.signCount = passkey.signCount
+
+		status, _ := login(impostor)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	// A signature counter that goes backwards means that the key was copied
+	t.Run("cloned", func(t *testing.T) {
+		clone := *passkey
+		clone.signCount = 0
+
+		status, _ := login(&clone)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		status := do(http.MethodDelete, "webauthn/credentials/"+strings.Repeat("A", 43), token.AccessToken, nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+
+		status = do(http.MethodDelete, "webauthn/credentials/"+credentialID, token.AccessToken, nil, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status, _ = login(passkey)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}